@@ -21,11 +21,22 @@ func UnmarshalFromString(str string, v interface{}) error {
 	return ConfigDefault.UnmarshalFromString(str, v)
 }
 
+// MergePatch applies a RFC 7396 JSON Merge Patch to the value pointed to by target.
+// Only the fields present in the patch are changed, null deletes them.
+func MergePatch(target interface{}, patch []byte) error {
+	return ConfigDefault.MergePatch(target, patch)
+}
+
 // Get quick method to get value from deeply nested JSON structure
 func Get(data []byte, path ...interface{}) Any {
 	return ConfigDefault.Get(data, path...)
 }
 
+// Query evaluates a JSONPath expression against data, returning all matched values
+func Query(data []byte, expr string) ([]Any, error) {
+	return ConfigDefault.Query(data, expr)
+}
+
 // Marshal adapts to json/encoding Marshal API
 //
 // Marshal returns the JSON encoding of v, adapts to json/encoding Marshal API
@@ -55,7 +66,7 @@ func NewDecoder(reader io.Reader) *Decoder {
 }
 
 // Decoder reads and decodes JSON values from an input stream.
-// Decoder provides identical APIs with json/stream Decoder (Token() and UseNumber() are in progress)
+// Decoder provides identical APIs with json/stream Decoder
 type Decoder struct {
 	iter *Iterator
 }
@@ -64,15 +75,41 @@ type Decoder struct {
 func (adapter *Decoder) Decode(obj interface{}) error {
 	if adapter.iter.head == adapter.iter.tail && adapter.iter.reader != nil {
 		if !adapter.iter.loadMore() {
+			if limitErr, isLimitErr := adapter.iter.Error.(*LimitError); isLimitErr {
+				return limitErr
+			}
 			return io.EOF
 		}
 	}
 	adapter.iter.ReadVal(obj)
 	err := adapter.iter.Error
 	if err == io.EOF {
-		return nil
+		return adapter.iter.withCollectedErrors(nil)
+	}
+	return adapter.iter.withCollectedErrors(adapter.iter.Error)
+}
+
+// Token returns the next JSON token in the input stream, io.EOF at the end of the input.
+// Values between tokens can be decoded with Decode, e.g. the elements of a huge array.
+// Refer to https://godoc.org/encoding/json#Decoder.Token for more information
+func (adapter *Decoder) Token() (Token, error) {
+	iter := adapter.iter
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	iter.Error = nil
+	token := iter.Token()
+	if iter.Error != nil {
+		return nil, iter.Error
 	}
-	return adapter.iter.Error
+	return token, nil
+}
+
+// InputOffset returns the input stream byte offset of the current decoder position.
+// The offset gives the location of the end of the most recently returned token
+// and the beginning of the next token.
+func (adapter *Decoder) InputOffset() int64 {
+	return adapter.iter.InputOffset()
 }
 
 // More is there more?
@@ -125,7 +162,7 @@ type Encoder struct {
 // Encode encode interface{} as JSON to io.Writer
 func (adapter *Encoder) Encode(val interface{}) error {
 	adapter.stream.WriteVal(val)
-	adapter.stream.WriteRaw("\n")
+	adapter.stream.writeByte('\n')
 	adapter.stream.Flush()
 	return adapter.stream.Error
 }
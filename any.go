@@ -27,14 +27,31 @@ type Any interface {
 	ToFloat64() float64
 	ToString() string
 	ToVal(val interface{})
+	AsBool() (bool, error)
+	AsInt() (int, error)
+	AsInt32() (int32, error)
+	AsInt64() (int64, error)
+	AsUint() (uint, error)
+	AsUint32() (uint32, error)
+	AsUint64() (uint64, error)
+	AsFloat32() (float32, error)
+	AsFloat64() (float64, error)
+	AsString() (string, error)
+	AsVal(val interface{}) error
 	Get(path ...interface{}) Any
 	Size() int
 	Keys() []string
+	ForEach(callback func(key string, value Any) bool)
+	ForEachIndex(callback func(index int, value Any) bool)
 	GetInterface() interface{}
 	WriteTo(stream *Stream)
 }
 
-type baseAny struct{}
+// baseAny holds the defaults of the methods of Any. kind is the kind of the value,
+// named in the errors of the checked conversions to the other kinds.
+type baseAny struct {
+	kind ValueType
+}
 
 func (any *baseAny) Get(path ...interface{}) Any {
 	return &invalidAny{baseAny{}, fmt.Errorf("GetIndex %v from simple value", path)}
@@ -48,44 +65,60 @@ func (any *baseAny) Keys() []string {
 	return []string{}
 }
 
+func (any *baseAny) ForEach(callback func(key string, value Any) bool) {
+}
+
+func (any *baseAny) ForEachIndex(callback func(index int, value Any) bool) {
+}
+
 func (any *baseAny) ToVal(obj interface{}) {
 	panic("not implemented")
 }
 
 // WrapInt32 turn int32 into Any interface
 func WrapInt32(val int32) Any {
-	return &int32Any{baseAny{}, val}
+	any := &int32Any{val: val}
+	any.checkedNumber = newCheckedNumber(any)
+	return any
 }
 
 // WrapInt64 turn int64 into Any interface
 func WrapInt64(val int64) Any {
-	return &int64Any{baseAny{}, val}
+	any := &int64Any{val: val}
+	any.checkedNumber = newCheckedNumber(any)
+	return any
 }
 
 // WrapUint32 turn uint32 into Any interface
 func WrapUint32(val uint32) Any {
-	return &uint32Any{baseAny{}, val}
+	any := &uint32Any{val: val}
+	any.checkedNumber = newCheckedNumber(any)
+	return any
 }
 
 // WrapUint64 turn uint64 into Any interface
 func WrapUint64(val uint64) Any {
-	return &uint64Any{baseAny{}, val}
+	any := &uint64Any{val: val}
+	any.checkedNumber = newCheckedNumber(any)
+	return any
 }
 
 // WrapFloat64 turn float64 into Any interface
 func WrapFloat64(val float64) Any {
-	return &floatAny{baseAny{}, val}
+	any := &floatAny{val: val}
+	any.checkedNumber = newCheckedNumber(any)
+	return any
 }
 
 // WrapString turn string into Any interface
 func WrapString(val string) Any {
-	return &stringAny{baseAny{}, val}
+	return &stringAny{baseAny{StringValue}, val}
 }
 
 // Wrap turn a go object into Any interface
 func Wrap(val interface{}) Any {
 	if val == nil {
-		return &nilAny{}
+		return &nilAny{baseAny{NilValue}}
 	}
 	asAny, isAny := val.(Any)
 	if isAny {
@@ -138,9 +171,9 @@ func Wrap(val interface{}) Any {
 		return WrapFloat64(val.(float64))
 	case reflect.Bool:
 		if val.(bool) == true {
-			return &trueAny{}
+			return &trueAny{baseAny{BoolValue}}
 		}
-		return &falseAny{}
+		return &falseAny{baseAny{BoolValue}}
 	}
 	return &invalidAny{baseAny{}, fmt.Errorf("unsupported type: %v", typ)}
 }
@@ -155,16 +188,16 @@ func (iter *Iterator) readAny() Any {
 	switch c {
 	case '"':
 		iter.unreadByte()
-		return &stringAny{baseAny{}, iter.ReadString()}
+		return &stringAny{baseAny{StringValue}, iter.ReadString()}
 	case 'n':
 		iter.skipThreeBytes('u', 'l', 'l') // null
-		return &nilAny{}
+		return &nilAny{baseAny{NilValue}}
 	case 't':
 		iter.skipThreeBytes('r', 'u', 'e') // true
-		return &trueAny{}
+		return &trueAny{baseAny{BoolValue}}
 	case 'f':
 		iter.skipFourBytes('a', 'l', 's', 'e') // false
-		return &falseAny{}
+		return &falseAny{baseAny{BoolValue}}
 	case '{':
 		return iter.readObjectAny()
 	case '[':
@@ -173,6 +206,12 @@ func (iter *Iterator) readAny() Any {
 		return iter.readNumberAny(false)
 	case 0:
 		return &invalidAny{baseAny{}, errors.New("input is empty")}
+	case '\'':
+		if iter.cfg.allowSingleQuotes {
+			iter.unreadByte()
+			return &stringAny{baseAny{StringValue}, iter.ReadString()}
+		}
+		return iter.readNumberAny(true)
 	default:
 		return iter.readNumberAny(true)
 	}
@@ -180,68 +219,122 @@ func (iter *Iterator) readAny() Any {
 
 func (iter *Iterator) readNumberAny(positive bool) Any {
 	iter.startCapture(iter.head - 1)
-	iter.skipNumber()
+	if iter.cfg.checkedNumbers {
+		iter.unreadByte()
+		iter.readCheckedNumber()
+	} else {
+		iter.skipNumber()
+	}
 	lazyBuf := iter.stopCapture()
-	return &numberLazyAny{baseAny{}, iter.cfg, lazyBuf, nil}
+	any := &numberLazyAny{cfg: iter.cfg, buf: lazyBuf}
+	any.checkedNumber = newCheckedNumber(any)
+	return any
 }
 
 func (iter *Iterator) readObjectAny() Any {
-	iter.startCapture(iter.head - 1)
-	iter.skipObject()
+	start := iter.head - 1
+	iter.startCapture(start)
+	iter.unreadByte()
+	iter.Skip()
 	lazyBuf := iter.stopCapture()
-	return &objectLazyAny{baseAny{}, iter.cfg, lazyBuf, nil}
+	return &objectLazyAny{baseAny{ObjectValue}, iter.cfg, lazyBuf, nil, iter.structural, iter.structuralBase + start, 0}
 }
 
 func (iter *Iterator) readArrayAny() Any {
-	iter.startCapture(iter.head - 1)
-	iter.skipArray()
+	start := iter.head - 1
+	iter.startCapture(start)
+	iter.unreadByte()
+	iter.Skip()
 	lazyBuf := iter.stopCapture()
-	return &arrayLazyAny{baseAny{}, iter.cfg, lazyBuf, nil}
+	return &arrayLazyAny{baseAny{ArrayValue}, iter.cfg, lazyBuf, nil, iter.structural, iter.structuralBase + start, 0}
 }
 
-func locateObjectField(iter *Iterator, target string) []byte {
-	var found []byte
+// locateObjectField moves iter to the value of the field target, telling if it is found.
+// Unless the first key wins, the rest of the object is read for a later value of target,
+// or a duplicate key reported with DuplicateKeysError. An error in the rest of the object
+// leaves the value found before it.
+func locateObjectField(iter *Iterator, target string) bool {
+	firstWins := iter.cfg.duplicateKeys == DuplicateKeysFirstWins
+	found, at := false, 0
+	// the value found, when it is read from a reader and so cannot be read again
+	var value []byte
 	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
-		if field == target {
-			found = iter.SkipAndReturnBytes()
+		if field != target {
+			iter.Skip()
+			return true
+		}
+		found = true
+		if firstWins {
 			return false
 		}
-		iter.Skip()
+		if iter.reader != nil {
+			value = iter.SkipAndReturnBytes()
+		} else {
+			at = iter.head
+			iter.Skip()
+		}
 		return true
 	})
-	return found
+	if !found {
+		return false
+	}
+	if !firstWins {
+		if iter.Error != nil && iter.Error != io.EOF {
+			if iter.cfg.duplicateKeys == DuplicateKeysError {
+				return false
+			}
+			iter.Error = nil
+		}
+		if value != nil {
+			iter.ResetBytes(value)
+			return true
+		}
+		iter.head = at
+	}
+	iter.narrowToValue()
+	return true
 }
 
-func locateArrayElement(iter *Iterator, target int) []byte {
-	var found []byte
+// locateArrayElement moves iter to the element target, telling if it is found.
+func locateArrayElement(iter *Iterator, target int) bool {
+	found := false
 	n := 0
 	iter.ReadArrayCB(func(iter *Iterator) bool {
 		if n == target {
-			found = iter.SkipAndReturnBytes()
+			found = true
 			return false
 		}
 		iter.Skip()
 		n++
 		return true
 	})
+	if found {
+		iter.narrowToValue()
+	}
 	return found
 }
 
+// narrowToValue makes the next value the whole input of iter.
+// With a structural index, iter stays where it is rather than copying the value.
+func (iter *Iterator) narrowToValue() {
+	if iter.structural != nil {
+		return
+	}
+	iter.ResetBytes(iter.SkipAndReturnBytes())
+}
+
 func locatePath(iter *Iterator, path []interface{}) Any {
 	for i, pathKeyObj := range path {
 		switch pathKey := pathKeyObj.(type) {
 		case string:
-			valueBytes := locateObjectField(iter, pathKey)
-			if valueBytes == nil {
-				return newInvalidAny(path[i:])
+			// a value of another type has no field, which is not an error to report
+			if iter.WhatIsNext() != ObjectValue || !locateObjectField(iter, pathKey) {
+				return locateFailure(iter, path[i:])
 			}
-			iter.ResetBytes(valueBytes)
 		case int:
-			valueBytes := locateArrayElement(iter, pathKey)
-			if valueBytes == nil {
-				return newInvalidAny(path[i:])
+			if iter.WhatIsNext() != ArrayValue || !locateArrayElement(iter, pathKey) {
+				return locateFailure(iter, path[i:])
 			}
-			iter.ResetBytes(valueBytes)
 		case int32:
 			if '*' == pathKey {
 				return iter.readAny().Get(path[i:]...)
@@ -257,6 +350,14 @@ func locatePath(iter *Iterator, path []interface{}) Any {
 	return iter.readAny()
 }
 
+// locateFailure is the invalid value of path not found, or of the error of iter looking for it.
+func locateFailure(iter *Iterator, path []interface{}) Any {
+	if iter.Error != nil && iter.Error != io.EOF {
+		return &invalidAny{baseAny{}, iter.Error}
+	}
+	return newInvalidAny(path)
+}
+
 var anyType = reflect2.TypeOfPtr((*Any)(nil)).Elem()
 
 func createDecoderOfAny(ctx *ctx, typ reflect2.Type) ValDecoder {
@@ -1,6 +1,7 @@
 package jsoniter
 
 import (
+	"io"
 	"reflect"
 	"unsafe"
 )
@@ -10,6 +11,24 @@ type arrayLazyAny struct {
 	cfg *frozenConfig
 	buf []byte
 	err error
+	// the structural index of the input buf was read from, buf starting at indexBase,
+	// or of buf once it is read again
+	index     *structuralIndex
+	indexBase int
+	reads     int
+}
+
+func (any *arrayLazyAny) borrowIterator() *Iterator {
+	iter := any.cfg.BorrowIterator(any.buf)
+	if any.index == nil && any.reads == 1 {
+		// the lookups read buf again, they are worth an index
+		iter.buildStructuralIndex()
+		any.index, any.indexBase = iter.structural, 0
+	} else if any.index != nil {
+		iter.useStructuralIndex(any.index, any.indexBase)
+	}
+	any.reads++
+	return iter
 }
 
 func (any *arrayLazyAny) ValueType() ValueType {
@@ -25,7 +44,7 @@ func (any *arrayLazyAny) LastError() error {
 }
 
 func (any *arrayLazyAny) ToBool() bool {
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	return iter.ReadArray()
 }
@@ -91,9 +110,16 @@ func (any *arrayLazyAny) ToString() string {
 }
 
 func (any *arrayLazyAny) ToVal(val interface{}) {
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	iter.ReadVal(val)
+	if iter.Error != nil && iter.Error != io.EOF {
+		any.err = iter.Error
+	}
+}
+
+func (any *arrayLazyAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
 }
 
 func (any *arrayLazyAny) Get(path ...interface{}) Any {
@@ -102,17 +128,15 @@ func (any *arrayLazyAny) Get(path ...interface{}) Any {
 	}
 	switch firstPath := path[0].(type) {
 	case int:
-		iter := any.cfg.BorrowIterator(any.buf)
+		iter := any.borrowIterator()
 		defer any.cfg.ReturnIterator(iter)
-		valueBytes := locateArrayElement(iter, firstPath)
-		if valueBytes == nil {
+		if !locateArrayElement(iter, firstPath) {
 			return newInvalidAny(path)
 		}
-		iter.ResetBytes(valueBytes)
 		return locatePath(iter, path[1:])
 	case int32:
 		if '*' == firstPath {
-			iter := any.cfg.BorrowIterator(any.buf)
+			iter := any.borrowIterator()
 			defer any.cfg.ReturnIterator(iter)
 			arr := make([]Any, 0)
 			iter.ReadArrayCB(func(iter *Iterator) bool {
@@ -130,9 +154,19 @@ func (any *arrayLazyAny) Get(path ...interface{}) Any {
 	}
 }
 
+func (any *arrayLazyAny) ForEachIndex(callback func(index int, value Any) bool) {
+	index := 0
+	iter := any.borrowIterator()
+	defer any.cfg.ReturnIterator(iter)
+	iter.ReadArrayCB(func(iter *Iterator) bool {
+		index++
+		return callback(index-1, iter.readAny())
+	})
+}
+
 func (any *arrayLazyAny) Size() int {
 	size := 0
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	iter.ReadArrayCB(func(iter *Iterator) bool {
 		size++
@@ -143,11 +177,11 @@ func (any *arrayLazyAny) Size() int {
 }
 
 func (any *arrayLazyAny) WriteTo(stream *Stream) {
-	stream.Write(any.buf)
+	stream.writeRawValue(any.buf)
 }
 
 func (any *arrayLazyAny) GetInterface() interface{} {
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	return iter.Read()
 }
@@ -158,7 +192,7 @@ type arrayAny struct {
 }
 
 func wrapArray(val interface{}) *arrayAny {
-	return &arrayAny{baseAny{}, reflect.ValueOf(val)}
+	return &arrayAny{baseAny{ArrayValue}, reflect.ValueOf(val)}
 }
 
 func (any *arrayAny) ValueType() ValueType {
@@ -265,10 +299,22 @@ func (any *arrayAny) Get(path ...interface{}) Any {
 	}
 }
 
+func (any *arrayAny) ForEachIndex(callback func(index int, value Any) bool) {
+	for i := 0; i < any.val.Len(); i++ {
+		if !callback(i, Wrap(any.val.Index(i).Interface())) {
+			return
+		}
+	}
+}
+
 func (any *arrayAny) Size() int {
 	return any.val.Len()
 }
 
+func (any *arrayAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *arrayAny) WriteTo(stream *Stream) {
 	stream.WriteVal(any.val)
 }
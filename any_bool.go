@@ -44,10 +44,18 @@ func (any *trueAny) ToFloat64() float64 {
 	return 1
 }
 
+func (any *trueAny) AsBool() (bool, error) {
+	return true, nil
+}
+
 func (any *trueAny) ToString() string {
 	return "true"
 }
 
+func (any *trueAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *trueAny) WriteTo(stream *Stream) {
 	stream.WriteTrue()
 }
@@ -112,10 +120,18 @@ func (any *falseAny) ToFloat64() float64 {
 	return 0
 }
 
+func (any *falseAny) AsBool() (bool, error) {
+	return false, nil
+}
+
 func (any *falseAny) ToString() string {
 	return "false"
 }
 
+func (any *falseAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *falseAny) WriteTo(stream *Stream) {
 	stream.WriteFalse()
 }
@@ -0,0 +1,245 @@
+package jsoniter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The checked conversions of Any. Where ToInt and the others coerce any value, reading 0 from
+// an object or the digits heading a string, AsInt and the others report a value of the wrong
+// kind, a number which is not an integer or out of range, and the error of an invalid value.
+
+// valueTypeName returns the name of the kind of JSON value in the errors.
+func valueTypeName(valueType ValueType) string {
+	switch valueType {
+	case StringValue:
+		return "string"
+	case NumberValue:
+		return "number"
+	case NilValue:
+		return "null"
+	case BoolValue:
+		return "bool"
+	case ArrayValue:
+		return "array"
+	case ObjectValue:
+		return "object"
+	}
+	return "invalid value"
+}
+
+func (any *baseAny) AsBool() (bool, error) {
+	return false, any.kindError("AsBool", BoolValue)
+}
+
+func (any *baseAny) AsInt() (int, error) {
+	return 0, any.kindError("AsInt", NumberValue)
+}
+
+func (any *baseAny) AsInt32() (int32, error) {
+	return 0, any.kindError("AsInt32", NumberValue)
+}
+
+func (any *baseAny) AsInt64() (int64, error) {
+	return 0, any.kindError("AsInt64", NumberValue)
+}
+
+func (any *baseAny) AsUint() (uint, error) {
+	return 0, any.kindError("AsUint", NumberValue)
+}
+
+func (any *baseAny) AsUint32() (uint32, error) {
+	return 0, any.kindError("AsUint32", NumberValue)
+}
+
+func (any *baseAny) AsUint64() (uint64, error) {
+	return 0, any.kindError("AsUint64", NumberValue)
+}
+
+func (any *baseAny) AsFloat32() (float32, error) {
+	return 0, any.kindError("AsFloat32", NumberValue)
+}
+
+func (any *baseAny) AsFloat64() (float64, error) {
+	return 0, any.kindError("AsFloat64", NumberValue)
+}
+
+func (any *baseAny) AsString() (string, error) {
+	return "", any.kindError("AsString", StringValue)
+}
+
+func (any *baseAny) kindError(operation string, expected ValueType) error {
+	return kindError(operation, any.kind, expected)
+}
+
+// kindError is the error of the checked conversion operation of a value of valueType,
+// which is not of the expected kind.
+func kindError(operation string, valueType ValueType, expected ValueType) error {
+	return fmt.Errorf("%s: %s is not a %s", operation, valueTypeName(valueType), valueTypeName(expected))
+}
+
+// checkedNumber is embedded in the Any of a number, number, for its checked conversions.
+type checkedNumber struct {
+	baseAny
+	number Any
+}
+
+func newCheckedNumber(number Any) checkedNumber {
+	return checkedNumber{baseAny{NumberValue}, number}
+}
+
+func (any *checkedNumber) AsInt() (int, error) {
+	val, err := checkedInt(any.number, "AsInt", 0)
+	return int(val), err
+}
+
+func (any *checkedNumber) AsInt32() (int32, error) {
+	val, err := checkedInt(any.number, "AsInt32", 32)
+	return int32(val), err
+}
+
+func (any *checkedNumber) AsInt64() (int64, error) {
+	return checkedInt(any.number, "AsInt64", 64)
+}
+
+func (any *checkedNumber) AsUint() (uint, error) {
+	val, err := checkedUint(any.number, "AsUint", 0)
+	return uint(val), err
+}
+
+func (any *checkedNumber) AsUint32() (uint32, error) {
+	val, err := checkedUint(any.number, "AsUint32", 32)
+	return uint32(val), err
+}
+
+func (any *checkedNumber) AsUint64() (uint64, error) {
+	return checkedUint(any.number, "AsUint64", 64)
+}
+
+func (any *checkedNumber) AsFloat32() (float32, error) {
+	val, err := checkedFloat(any.number, "AsFloat32", 32)
+	return float32(val), err
+}
+
+func (any *checkedNumber) AsFloat64() (float64, error) {
+	return checkedFloat(any.number, "AsFloat64", 64)
+}
+
+// checkedDecimal returns the value of the number val.
+func checkedDecimal(val Any, operation string) (anyDecimal, error) {
+	if valueType := val.ValueType(); valueType != NumberValue {
+		return anyDecimal{}, kindError(operation, valueType, NumberValue)
+	}
+	if lazy, isLazy := val.(*numberLazyAny); isLazy {
+		if d, ok := parseAnyDecimal(string(lazy.buf)); ok {
+			return d, nil
+		}
+		// such as a number of JSON5 in hexadecimal, which the Iterator reads
+		lazy.err = nil
+		f := lazy.ToFloat64()
+		if lazy.err != nil {
+			return anyDecimal{}, fmt.Errorf("%s: %v", operation, lazy.err)
+		}
+		return anyDecimalOf(WrapFloat64(f)), nil
+	}
+	return anyDecimalOf(val), nil
+}
+
+// checkedInteger returns the magnitude of the number val, telling if it is negative,
+// if it is an integer of at most 64 bits.
+func checkedInteger(val Any, operation string) (uint64, bool, error) {
+	d, err := checkedDecimal(val, operation)
+	if err != nil {
+		return 0, false, err
+	}
+	if d.rank != rankFinite || len(d.digits) > d.exp {
+		return 0, false, fmt.Errorf("%s: %s is not an integer", operation, val.ToString())
+	}
+	if d.digits == "" {
+		return 0, false, nil
+	}
+	if d.exp > 20 {
+		return 0, false, fmt.Errorf("%s: %s overflows", operation, val.ToString())
+	}
+	magnitude, err := strconv.ParseUint(d.digits+strings.Repeat("0", d.exp-len(d.digits)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: %s overflows", operation, val.ToString())
+	}
+	return magnitude, d.neg, nil
+}
+
+// checkedInt returns the number val if it is an integer of bitSize bits, 0 being the size of int.
+func checkedInt(val Any, operation string, bitSize int) (int64, error) {
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+	magnitude, neg, err := checkedInteger(val, operation)
+	if err != nil {
+		return 0, err
+	}
+	limit := uint64(1) << uint(bitSize-1)
+	if neg && magnitude > limit || !neg && magnitude >= limit {
+		return 0, fmt.Errorf("%s: %s overflows int%d", operation, val.ToString(), bitSize)
+	}
+	if neg {
+		return -int64(magnitude-1) - 1, nil
+	}
+	return int64(magnitude), nil
+}
+
+// checkedUint returns the number val if it is an unsigned integer of bitSize bits,
+// 0 being the size of uint.
+func checkedUint(val Any, operation string, bitSize int) (uint64, error) {
+	if bitSize == 0 {
+		bitSize = strconv.IntSize
+	}
+	magnitude, neg, err := checkedInteger(val, operation)
+	if err != nil {
+		return 0, err
+	}
+	if neg && magnitude != 0 || bitSize < 64 && magnitude >= uint64(1)<<uint(bitSize) {
+		return 0, fmt.Errorf("%s: %s overflows uint%d", operation, val.ToString(), bitSize)
+	}
+	return magnitude, nil
+}
+
+// checkedFloat returns the number val if it is in the range of a float of bitSize bits.
+func checkedFloat(val Any, operation string, bitSize int) (float64, error) {
+	d, err := checkedDecimal(val, operation)
+	if err != nil {
+		return 0, err
+	}
+	if d.rank != rankFinite {
+		return 0, fmt.Errorf("%s: %s is not a JSON number", operation, val.ToString())
+	}
+	if d.digits == "" {
+		return 0, nil
+	}
+	text := "0." + d.digits + "e" + strconv.Itoa(d.exp)
+	if d.neg {
+		text = "-" + text
+	}
+	f, err := strconv.ParseFloat(text, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s overflows float%d", operation, val.ToString(), bitSize)
+	}
+	return f, nil
+}
+
+// checkedVal reads val into obj as Unmarshal does, from the bytes of a lazy value,
+// reporting what Unmarshal reports.
+func checkedVal(val Any, obj interface{}) error {
+	if val.ValueType() == InvalidValue {
+		return val.LastError()
+	}
+	cfg, buf := lazyBytesOf(val)
+	if cfg == nil {
+		cfg = ConfigDefault.(*frozenConfig)
+		var err error
+		if buf, err = cfg.Marshal(val.GetInterface()); err != nil {
+			return err
+		}
+	}
+	return cfg.Unmarshal(buf, obj)
+}
@@ -0,0 +1,303 @@
+package jsoniter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The edits of a lazy object or array. The buffer of the Any is spliced: the value at the path
+// is replaced by the new one written as JSON, all around it being copied as read. Editing a
+// field of a big document only skips the rest, nothing else is decoded.
+
+// EditableAny is an Any which can be edited: an object or array read by Get, or built
+// with NewObjectAny or NewArrayAny. The other values, and those of Wrap, are not.
+type EditableAny interface {
+	Any
+	Set(value interface{}, path ...interface{}) error
+	Delete(path ...interface{}) error
+	Append(value interface{}, path ...interface{}) error
+}
+
+// NewObjectAny returns an empty JSON object, to be built with Set. The values set are
+// written as JSON with cfg, which reads the object as well.
+func NewObjectAny(cfg API) EditableAny {
+	return &objectLazyAny{baseAny{ObjectValue}, cfg.(*frozenConfig), []byte("{}"), nil, nil, 0, 0}
+}
+
+// NewArrayAny returns a JSON array of values, to be built further with Append and Set.
+// The values are written as JSON with cfg, which reads the array as well.
+func NewArrayAny(cfg API, values ...interface{}) EditableAny {
+	any := &arrayLazyAny{baseAny{ArrayValue}, cfg.(*frozenConfig), []byte("[]"), nil, nil, 0, 0}
+	for _, value := range values {
+		if any.err = any.Append(value); any.err != nil {
+			break
+		}
+	}
+	return any
+}
+
+// Set sets the value at path, the keys of objects and indexes of arrays as with Get. The objects
+// missing on the path are added, an index out of an array is an error.
+func (any *objectLazyAny) Set(value interface{}, path ...interface{}) error {
+	return any.edit(editSet(any.cfg, value), path)
+}
+
+// Delete deletes the value at path, every field of the key if it is repeated.
+func (any *objectLazyAny) Delete(path ...interface{}) error {
+	return any.edit(editDelete(any.cfg), path)
+}
+
+// Append appends value to the array at path.
+func (any *objectLazyAny) Append(value interface{}, path ...interface{}) error {
+	return any.edit(editAppend(any.cfg, value), append(path[:len(path):len(path)], appendKey{}))
+}
+
+func (any *objectLazyAny) edit(edit editFunc, path []interface{}) error {
+	buf, err := editPath(any.cfg, any.buf, path, edit)
+	if err != nil {
+		return err
+	}
+	any.buf, any.index, any.indexBase, any.reads = buf, nil, 0, 0
+	return nil
+}
+
+// Set sets the value at path, the keys of objects and indexes of arrays as with Get. The objects
+// missing on the path are added, an index out of an array is an error.
+func (any *arrayLazyAny) Set(value interface{}, path ...interface{}) error {
+	return any.edit(editSet(any.cfg, value), path)
+}
+
+// Delete deletes the value at path, every field of the key if it is repeated.
+func (any *arrayLazyAny) Delete(path ...interface{}) error {
+	return any.edit(editDelete(any.cfg), path)
+}
+
+// Append appends value to the array at path.
+func (any *arrayLazyAny) Append(value interface{}, path ...interface{}) error {
+	return any.edit(editAppend(any.cfg, value), append(path[:len(path):len(path)], appendKey{}))
+}
+
+func (any *arrayLazyAny) edit(edit editFunc, path []interface{}) error {
+	buf, err := editPath(any.cfg, any.buf, path, edit)
+	if err != nil {
+		return err
+	}
+	any.buf, any.index, any.indexBase, any.reads = buf, nil, 0, 0
+	return nil
+}
+
+// appendKey ends the path of Append, the key of the element past the end of the array.
+type appendKey struct{}
+
+// editFunc edits the member key of the object or array in buf, returning the new buffer.
+type editFunc func(buf []byte, members *editMembers, key interface{}) ([]byte, error)
+
+// editPath returns a copy of buf with its value at path edited by edit.
+// The last key of path is given to edit, along with the members of its object or array.
+func editPath(cfg *frozenConfig, buf []byte, path []interface{}, edit editFunc) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, errors.New("edit with an empty path")
+	}
+	members, err := scanMembers(cfg, buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 1 {
+		return edit(buf, members, path[0])
+	}
+	at, err := members.find(path[0])
+	if err != nil {
+		return nil, err
+	}
+	if at < 0 {
+		// an object missing on the path
+		value, err := editPath(cfg, []byte("{}"), path[1:], edit)
+		if err != nil {
+			return nil, err
+		}
+		return members.insert(cfg, buf, path[0].(string), value)
+	}
+	member := members.spans[at]
+	value, err := editPath(cfg, buf[member.start:member.end], path[1:], edit)
+	if err != nil {
+		return nil, err
+	}
+	return splice(buf, member.start, member.end, value), nil
+}
+
+func editSet(cfg *frozenConfig, value interface{}) editFunc {
+	return func(buf []byte, members *editMembers, key interface{}) ([]byte, error) {
+		at, err := members.find(key)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := cfg.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if at < 0 {
+			return members.insert(cfg, buf, key.(string), encoded)
+		}
+		return splice(buf, members.spans[at].start, members.spans[at].end, encoded), nil
+	}
+}
+
+func editDelete(cfg *frozenConfig) editFunc {
+	return func(buf []byte, members *editMembers, key interface{}) ([]byte, error) {
+		at, err := members.find(key)
+		if err != nil {
+			return nil, err
+		}
+		if at < 0 {
+			return nil, fmt.Errorf("Delete %v: no such field", key)
+		}
+		for at >= 0 {
+			buf = members.remove(cfg, buf, at)
+			if members, err = scanMembers(cfg, buf); err != nil {
+				return nil, err
+			}
+			if _, isIndex := key.(int); isIndex {
+				break
+			}
+			at, _ = members.find(key)
+		}
+		return buf, nil
+	}
+}
+
+func editAppend(cfg *frozenConfig, value interface{}) editFunc {
+	return func(buf []byte, members *editMembers, key interface{}) ([]byte, error) {
+		if !members.array {
+			return nil, errors.New("Append to an object")
+		}
+		encoded, err := cfg.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(members.spans) == 0 {
+			return splice(buf, members.close, members.close, encoded), nil
+		}
+		end := members.spans[len(members.spans)-1].end
+		return splice(buf, end, end, append([]byte{','}, encoded...)), nil
+	}
+}
+
+// editMembers is where the members of an object or array are in its buffer.
+type editMembers struct {
+	array bool
+	// the positions of the opening and closing brace or bracket
+	open, close int
+	spans       []memberSpan
+}
+
+// memberSpan is where the value of a member is, from its first byte to past its last.
+type memberSpan struct {
+	key        string
+	start, end int
+}
+
+func scanMembers(cfg *frozenConfig, buf []byte) (*editMembers, error) {
+	iter := cfg.BorrowIterator(buf)
+	defer cfg.ReturnIterator(iter)
+	members := &editMembers{}
+	valueType := iter.WhatIsNext()
+	iter.nextToken()
+	members.open = iter.head - 1
+	iter.unreadByte()
+	readMember := func(iter *Iterator, field string) bool {
+		iter.nextToken()
+		iter.unreadByte()
+		start := iter.head
+		iter.Skip()
+		members.spans = append(members.spans, memberSpan{field, start, iter.head})
+		return true
+	}
+	switch valueType {
+	case ObjectValue:
+		iter.ReadObjectCB(readMember)
+	case ArrayValue:
+		members.array = true
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			return readMember(iter, "")
+		})
+	default:
+		return nil, errors.New("edit a value which is neither an object nor an array")
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	members.close = iter.head - 1
+	return members, nil
+}
+
+// find returns the member of key, -1 if there is no field of the key. It is the field read
+// by Get when the key is repeated.
+func (members *editMembers) find(key interface{}) (int, error) {
+	switch key := key.(type) {
+	case string:
+		if members.array {
+			return 0, fmt.Errorf("edit field %q of an array", key)
+		}
+		for i := len(members.spans) - 1; i >= 0; i-- {
+			if members.spans[i].key == key {
+				return i, nil
+			}
+		}
+		return -1, nil
+	case int:
+		if !members.array {
+			return 0, fmt.Errorf("edit index %v of an object", key)
+		}
+		if key < 0 || key >= len(members.spans) {
+			return 0, fmt.Errorf("edit index %v out of an array of %v elements", key, len(members.spans))
+		}
+		return key, nil
+	case appendKey:
+		return 0, errors.New("Append with an empty path")
+	default:
+		return 0, fmt.Errorf("edit with the key %v, neither a string nor an int", key)
+	}
+}
+
+// insert appends the field key of value, as JSON, to the object.
+func (members *editMembers) insert(cfg *frozenConfig, buf []byte, key string, value []byte) ([]byte, error) {
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	at := members.close
+	if len(members.spans) != 0 {
+		at = members.spans[len(members.spans)-1].end
+		stream.writeByte(',')
+	}
+	stream.WriteString(key)
+	stream.writeByte(':')
+	stream.Write(value)
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	return splice(buf, at, at, stream.Buffer()), nil
+}
+
+// remove removes the member at, with the comma before it, or after it for the first member.
+func (members *editMembers) remove(cfg *frozenConfig, buf []byte, at int) []byte {
+	if at > 0 {
+		return splice(buf, members.spans[at-1].end, members.spans[at].end, nil)
+	}
+	if len(members.spans) == 1 {
+		return splice(buf, members.open+1, members.close, nil)
+	}
+	iter := cfg.BorrowIterator(buf)
+	defer cfg.ReturnIterator(iter)
+	iter.head = members.spans[0].end
+	iter.nextToken()
+	return splice(buf, members.open+1, iter.head, nil)
+}
+
+// splice returns a new buffer, buf with its bytes from start to end replaced by value.
+// buf itself is left as is, being the input of other values too.
+func splice(buf []byte, start, end int, value []byte) []byte {
+	spliced := make([]byte, 0, len(buf)-(end-start)+len(value))
+	spliced = append(spliced, buf[:start]...)
+	spliced = append(spliced, value...)
+	return append(spliced, buf[end:]...)
+}
@@ -0,0 +1,361 @@
+package jsoniter
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Equal tells if a and b are the same JSON value. The numbers are compared as decimals,
+// 1.0 equals 1 and 1e2 equals 100, and the fields of objects in any order. A repeated key
+// counts once, with the value Get reads. Two invalid values are equal.
+func Equal(a, b Any) bool {
+	return Compare(a, b) == 0
+}
+
+// Compare orders a and b, returning 0 if they are Equal, -1 if a is before b and +1 if it is
+// after. The values are ordered by kind first: invalid, null, bool, number, string, array then
+// object. Numbers are ordered by value, strings by bytes, arrays element by element and objects
+// as the arrays of their fields sorted by key, each being ordered by key then by value.
+func Compare(a, b Any) int {
+	if sameLazyBytes(a, b) {
+		return 0
+	}
+	rankA, rankB := anyRank(a), anyRank(b)
+	if rankA != rankB {
+		return compareInts(rankA, rankB)
+	}
+	switch rankA {
+	case rankBool:
+		return compareInts(boolRank(a.ToBool()), boolRank(b.ToBool()))
+	case rankNumber:
+		return compareDecimals(anyDecimalOf(a), anyDecimalOf(b))
+	case rankString:
+		return strings.Compare(a.ToString(), b.ToString())
+	case rankArray:
+		elementsA, elementsB := anyElements(a), anyElements(b)
+		for i := 0; i < len(elementsA) && i < len(elementsB); i++ {
+			if c := Compare(elementsA[i], elementsB[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(elementsA), len(elementsB))
+	case rankObject:
+		membersA, membersB := anyMembers(a), anyMembers(b)
+		for i := 0; i < len(membersA) && i < len(membersB); i++ {
+			if c := strings.Compare(membersA[i].key, membersB[i].key); c != 0 {
+				return c
+			}
+			if c := Compare(membersA[i].value, membersB[i].value); c != 0 {
+				return c
+			}
+		}
+		return compareInts(len(membersA), len(membersB))
+	}
+	return 0
+}
+
+// Hash returns a hash of val which is the same for the Equal values, in any process.
+func Hash(val Any) uint64 {
+	h := anyHash(fnvOffset64)
+	h.writeAny(val)
+	return uint64(h)
+}
+
+const (
+	rankInvalid = iota
+	rankNull
+	rankBool
+	rankNumber
+	rankString
+	rankArray
+	rankObject
+)
+
+func anyRank(val Any) int {
+	if val == nil {
+		return rankNull
+	}
+	switch val.ValueType() {
+	case NilValue:
+		return rankNull
+	case BoolValue:
+		return rankBool
+	case NumberValue:
+		return rankNumber
+	case StringValue:
+		return rankString
+	case ArrayValue:
+		return rankArray
+	case ObjectValue:
+		return rankObject
+	}
+	return rankInvalid
+}
+
+func boolRank(val bool) int {
+	if val {
+		return 1
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// sameLazyBytes tells if a and b are lazy values read from the same bytes with the same config,
+// which are Equal without reading them.
+func sameLazyBytes(a, b Any) bool {
+	cfgA, bufA := lazyBytesOf(a)
+	cfgB, bufB := lazyBytesOf(b)
+	return cfgA != nil && cfgA == cfgB && bytes.Equal(bufA, bufB)
+}
+
+func lazyBytesOf(val Any) (*frozenConfig, []byte) {
+	switch any := val.(type) {
+	case *objectLazyAny:
+		return any.cfg, any.buf
+	case *arrayLazyAny:
+		return any.cfg, any.buf
+	case *numberLazyAny:
+		return any.cfg, any.buf
+	}
+	return nil, nil
+}
+
+// anyElements returns the elements of the array val.
+func anyElements(val Any) []Any {
+	elements := []Any{}
+	val.ForEachIndex(func(index int, element Any) bool {
+		elements = append(elements, element)
+		return true
+	})
+	return elements
+}
+
+type anyMember struct {
+	key   string
+	value Any
+}
+
+// anyMembers returns the fields of the object val sorted by key, a repeated key once.
+func anyMembers(val Any) []anyMember {
+	members := []anyMember{}
+	at := map[string]int{}
+	val.ForEach(func(key string, value Any) bool {
+		if i, found := at[key]; found {
+			// the last value wins, ForEach giving the repeated keys only then
+			members[i].value = value
+			return true
+		}
+		at[key] = len(members)
+		members = append(members, anyMember{key, value})
+		return true
+	})
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].key < members[j].key
+	})
+	return members
+}
+
+// anyDecimal is the value of a number, 0.digits times 10 to the power of exp.
+type anyDecimal struct {
+	// rankNaN, rankNegativeInf, rankFinite or rankPositiveInf
+	rank   int
+	neg    bool
+	digits string
+	exp    int
+}
+
+const (
+	rankNaN = iota
+	rankNegativeInf
+	rankFinite
+	rankPositiveInf
+)
+
+func anyDecimalOf(val Any) anyDecimal {
+	var text string
+	if lazy, isLazy := val.(*numberLazyAny); isLazy {
+		text = string(lazy.buf)
+	} else {
+		text = val.ToString()
+	}
+	if d, ok := parseAnyDecimal(text); ok {
+		return d
+	}
+	// such as NaN, or a number of JSON5 in hexadecimal
+	f := val.ToFloat64()
+	switch {
+	case math.IsNaN(f):
+		return anyDecimal{rank: rankNaN}
+	case math.IsInf(f, -1):
+		return anyDecimal{rank: rankNegativeInf}
+	case math.IsInf(f, 1):
+		return anyDecimal{rank: rankPositiveInf}
+	}
+	d, _ := parseAnyDecimal(strconv.FormatFloat(f, 'e', -1, 64))
+	return d
+}
+
+// parseAnyDecimal parses the decimal number text, telling if it is one.
+func parseAnyDecimal(text string) (anyDecimal, bool) {
+	d := anyDecimal{rank: rankFinite}
+	i := 0
+	if i < len(text) && (text[i] == '-' || text[i] == '+') {
+		d.neg = text[i] == '-'
+		i++
+	}
+	digits := make([]byte, 0, len(text))
+	hasDigits, hasDot := false, false
+	for ; i < len(text); i++ {
+		c := text[i]
+		if c == '.' && !hasDot {
+			hasDot = true
+			continue
+		}
+		if c < '0' || c > '9' {
+			break
+		}
+		hasDigits = true
+		if c == '0' && len(digits) == 0 {
+			// a leading zero, moving the first digit right when after the dot
+			if hasDot {
+				d.exp--
+			}
+			continue
+		}
+		digits = append(digits, c)
+		if !hasDot {
+			d.exp++
+		}
+	}
+	if !hasDigits {
+		return d, false
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		i++
+		expNeg := false
+		if i < len(text) && (text[i] == '-' || text[i] == '+') {
+			expNeg = text[i] == '-'
+			i++
+		}
+		start, exp := i, 0
+		for ; i < len(text) && text[i] >= '0' && text[i] <= '9'; i++ {
+			if exp < 1<<30 {
+				exp = exp*10 + int(text[i]-'0')
+			}
+		}
+		if i == start {
+			return d, false
+		}
+		if expNeg {
+			exp = -exp
+		}
+		d.exp += exp
+	}
+	if i != len(text) {
+		return d, false
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		// -0 as well
+		return anyDecimal{rank: rankFinite}, true
+	}
+	d.digits = string(digits)
+	return d, true
+}
+
+func (d anyDecimal) sign() int {
+	switch {
+	case d.digits == "":
+		return 0
+	case d.neg:
+		return -1
+	}
+	return 1
+}
+
+func compareDecimals(a, b anyDecimal) int {
+	if a.rank != rankFinite || b.rank != rankFinite {
+		return compareInts(a.rank, b.rank)
+	}
+	if c := compareInts(a.sign(), b.sign()); c != 0 || a.sign() == 0 {
+		return c
+	}
+	c := compareInts(a.exp, b.exp)
+	if c == 0 {
+		c = strings.Compare(a.digits, b.digits)
+	}
+	if a.neg {
+		return -c
+	}
+	return c
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// anyHash is the FNV-1a hash of the values written to it.
+type anyHash uint64
+
+func (h *anyHash) writeByte(b byte) {
+	*h = (*h ^ anyHash(b)) * fnvPrime64
+}
+
+func (h *anyHash) writeUint64(val uint64) {
+	for i := 0; i < 8; i++ {
+		h.writeByte(byte(val))
+		val >>= 8
+	}
+}
+
+func (h *anyHash) writeString(s string) {
+	h.writeUint64(uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h.writeByte(s[i])
+	}
+}
+
+func (h *anyHash) writeAny(val Any) {
+	rank := anyRank(val)
+	h.writeByte(byte(rank))
+	switch rank {
+	case rankBool:
+		h.writeByte(byte(boolRank(val.ToBool())))
+	case rankNumber:
+		d := anyDecimalOf(val)
+		h.writeByte(byte(d.rank))
+		h.writeByte(byte(d.sign() + 1))
+		h.writeUint64(uint64(d.exp))
+		h.writeString(d.digits)
+	case rankString:
+		h.writeString(val.ToString())
+	case rankArray:
+		elements := anyElements(val)
+		h.writeUint64(uint64(len(elements)))
+		for _, element := range elements {
+			h.writeAny(element)
+		}
+	case rankObject:
+		members := anyMembers(val)
+		h.writeUint64(uint64(len(members)))
+		for _, member := range members {
+			h.writeString(member.key)
+			h.writeAny(member.value)
+		}
+	}
+}
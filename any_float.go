@@ -5,7 +5,7 @@ import (
 )
 
 type floatAny struct {
-	baseAny
+	checkedNumber
 	val float64
 }
 
@@ -74,6 +74,10 @@ func (any *floatAny) ToString() string {
 	return strconv.FormatFloat(any.val, 'E', -1, 64)
 }
 
+func (any *floatAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *floatAny) WriteTo(stream *Stream) {
 	stream.WriteFloat64(any.val)
 }
@@ -5,7 +5,7 @@ import (
 )
 
 type int32Any struct {
-	baseAny
+	checkedNumber
 	val int32
 }
 
@@ -61,6 +61,10 @@ func (any *int32Any) ToString() string {
 	return strconv.FormatInt(int64(any.val), 10)
 }
 
+func (any *int32Any) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *int32Any) WriteTo(stream *Stream) {
 	stream.WriteInt32(any.val)
 }
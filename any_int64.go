@@ -5,7 +5,7 @@ import (
 )
 
 type int64Any struct {
-	baseAny
+	checkedNumber
 	val int64
 }
 
@@ -61,6 +61,10 @@ func (any *int64Any) ToString() string {
 	return strconv.FormatInt(any.val, 10)
 }
 
+func (any *int64Any) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *int64Any) WriteTo(stream *Stream) {
 	stream.WriteInt64(any.val)
 }
@@ -59,6 +59,50 @@ func (any *invalidAny) ToFloat64() float64 {
 	return 0
 }
 
+func (any *invalidAny) AsBool() (bool, error) {
+	return false, any.err
+}
+
+func (any *invalidAny) AsInt() (int, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsInt32() (int32, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsInt64() (int64, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsUint() (uint, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsUint32() (uint32, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsUint64() (uint64, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsFloat32() (float32, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsFloat64() (float64, error) {
+	return 0, any.err
+}
+
+func (any *invalidAny) AsString() (string, error) {
+	return "", any.err
+}
+
+func (any *invalidAny) AsVal(obj interface{}) error {
+	return any.err
+}
+
 func (any *invalidAny) ToString() string {
 	return ""
 }
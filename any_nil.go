@@ -56,6 +56,10 @@ func (any *nilAny) ToString() string {
 	return ""
 }
 
+func (any *nilAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *nilAny) WriteTo(stream *Stream) {
 	stream.WriteNil()
 }
@@ -6,7 +6,7 @@ import (
 )
 
 type numberLazyAny struct {
-	baseAny
+	checkedNumber
 	cfg *frozenConfig
 	buf []byte
 	err error
@@ -112,8 +112,12 @@ func (any *numberLazyAny) ToString() string {
 	return *(*string)(unsafe.Pointer(&any.buf))
 }
 
+func (any *numberLazyAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *numberLazyAny) WriteTo(stream *Stream) {
-	stream.Write(any.buf)
+	stream.writeRawValue(any.buf)
 }
 
 func (any *numberLazyAny) GetInterface() interface{} {
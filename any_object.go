@@ -1,7 +1,10 @@
 package jsoniter
 
 import (
+	"encoding"
+	"io"
 	"reflect"
+	"strconv"
 	"unsafe"
 )
 
@@ -10,6 +13,24 @@ type objectLazyAny struct {
 	cfg *frozenConfig
 	buf []byte
 	err error
+	// the structural index of the input buf was read from, buf starting at indexBase,
+	// or of buf once it is read again
+	index     *structuralIndex
+	indexBase int
+	reads     int
+}
+
+func (any *objectLazyAny) borrowIterator() *Iterator {
+	iter := any.cfg.BorrowIterator(any.buf)
+	if any.index == nil && any.reads == 1 {
+		// the lookups read buf again, they are worth an index
+		iter.buildStructuralIndex()
+		any.index, any.indexBase = iter.structural, 0
+	} else if any.index != nil {
+		iter.useStructuralIndex(any.index, any.indexBase)
+	}
+	any.reads++
+	return iter
 }
 
 func (any *objectLazyAny) ValueType() ValueType {
@@ -65,9 +86,16 @@ func (any *objectLazyAny) ToString() string {
 }
 
 func (any *objectLazyAny) ToVal(obj interface{}) {
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	iter.ReadVal(obj)
+	if iter.Error != nil && iter.Error != io.EOF {
+		any.err = iter.Error
+	}
+}
+
+func (any *objectLazyAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
 }
 
 func (any *objectLazyAny) Get(path ...interface{}) Any {
@@ -76,21 +104,19 @@ func (any *objectLazyAny) Get(path ...interface{}) Any {
 	}
 	switch firstPath := path[0].(type) {
 	case string:
-		iter := any.cfg.BorrowIterator(any.buf)
+		iter := any.borrowIterator()
 		defer any.cfg.ReturnIterator(iter)
-		valueBytes := locateObjectField(iter, firstPath)
-		if valueBytes == nil {
-			return newInvalidAny(path)
+		if !locateObjectField(iter, firstPath) {
+			return locateFailure(iter, path)
 		}
-		iter.ResetBytes(valueBytes)
 		return locatePath(iter, path[1:])
 	case int32:
 		if '*' == firstPath {
 			mappedAll := map[string]Any{}
-			iter := any.cfg.BorrowIterator(any.buf)
+			iter := any.borrowIterator()
 			defer any.cfg.ReturnIterator(iter)
 			iter.ReadMapCB(func(iter *Iterator, field string) bool {
-				mapped := locatePath(iter, path[1:])
+				mapped := iter.readAny().Get(path[1:]...)
 				if mapped.ValueType() != InvalidValue {
 					mappedAll[field] = mapped
 				}
@@ -106,7 +132,7 @@ func (any *objectLazyAny) Get(path ...interface{}) Any {
 
 func (any *objectLazyAny) Keys() []string {
 	keys := []string{}
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	iter.ReadMapCB(func(iter *Iterator, field string) bool {
 		iter.Skip()
@@ -116,9 +142,17 @@ func (any *objectLazyAny) Keys() []string {
 	return keys
 }
 
+func (any *objectLazyAny) ForEach(callback func(key string, value Any) bool) {
+	iter := any.borrowIterator()
+	defer any.cfg.ReturnIterator(iter)
+	iter.ReadMapCB(func(iter *Iterator, field string) bool {
+		return callback(field, iter.readAny())
+	})
+}
+
 func (any *objectLazyAny) Size() int {
 	size := 0
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
 		iter.Skip()
@@ -129,11 +163,11 @@ func (any *objectLazyAny) Size() int {
 }
 
 func (any *objectLazyAny) WriteTo(stream *Stream) {
-	stream.Write(any.buf)
+	stream.writeRawValue(any.buf)
 }
 
 func (any *objectLazyAny) GetInterface() interface{} {
-	iter := any.cfg.BorrowIterator(any.buf)
+	iter := any.borrowIterator()
 	defer any.cfg.ReturnIterator(iter)
 	return iter.Read()
 }
@@ -145,7 +179,7 @@ type objectAny struct {
 }
 
 func wrapStruct(val interface{}) *objectAny {
-	return &objectAny{baseAny{}, nil, reflect.ValueOf(val)}
+	return &objectAny{baseAny{ObjectValue}, nil, reflect.ValueOf(val)}
 }
 
 func (any *objectAny) ValueType() ValueType {
@@ -245,10 +279,23 @@ func (any *objectAny) Keys() []string {
 	return keys
 }
 
+func (any *objectAny) ForEach(callback func(key string, value Any) bool) {
+	for i := 0; i < any.val.NumField(); i++ {
+		field := any.val.Field(i)
+		if field.CanInterface() && !callback(any.val.Type().Field(i).Name, Wrap(field.Interface())) {
+			return
+		}
+	}
+}
+
 func (any *objectAny) Size() int {
 	return any.val.NumField()
 }
 
+func (any *objectAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *objectAny) WriteTo(stream *Stream) {
 	stream.WriteVal(any.val)
 }
@@ -264,7 +311,7 @@ type mapAny struct {
 }
 
 func wrapMap(val interface{}) *mapAny {
-	return &mapAny{baseAny{}, nil, reflect.ValueOf(val)}
+	return &mapAny{baseAny{ObjectValue}, nil, reflect.ValueOf(val)}
 }
 
 func (any *mapAny) ValueType() ValueType {
@@ -334,7 +381,7 @@ func (any *mapAny) Get(path ...interface{}) Any {
 		if '*' == firstPath {
 			mappedAll := map[string]Any{}
 			for _, key := range any.val.MapKeys() {
-				keyAsStr := key.String()
+				keyAsStr := mapKeyString(key)
 				element := Wrap(any.val.MapIndex(key).Interface())
 				mapped := element.Get(path[1:]...)
 				if mapped.ValueType() != InvalidValue {
@@ -356,15 +403,53 @@ func (any *mapAny) Get(path ...interface{}) Any {
 func (any *mapAny) Keys() []string {
 	keys := make([]string, 0, any.val.Len())
 	for _, key := range any.val.MapKeys() {
-		keys = append(keys, key.String())
+		keys = append(keys, mapKeyString(key))
 	}
 	return keys
 }
 
+func (any *mapAny) ForEach(callback func(key string, value Any) bool) {
+	iter := any.val.MapRange()
+	for iter.Next() {
+		if !callback(mapKeyString(iter.Key()), Wrap(iter.Value().Interface())) {
+			return
+		}
+	}
+}
+
+// mapKeyString formats key as the encoders of map keys write it: by its MarshalText,
+// as the string itself, or as the number or bool written in the string.
+func mapKeyString(key reflect.Value) string {
+	if key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+	if marshaler, isMarshaler := key.Interface().(encoding.TextMarshaler); isMarshaler {
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return ""
+		}
+		text, _ := marshaler.MarshalText()
+		return string(text)
+	}
+	switch key.Kind() {
+	case reflect.String:
+		return key.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	}
+	text, _ := ConfigDefault.MarshalToString(key.Interface())
+	return text
+}
+
 func (any *mapAny) Size() int {
 	return any.val.Len()
 }
 
+func (any *mapAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *mapAny) WriteTo(stream *Stream) {
 	stream.WriteVal(any.val)
 }
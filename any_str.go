@@ -153,10 +153,18 @@ func (any *stringAny) ToFloat64() float64 {
 	return parsed
 }
 
+func (any *stringAny) AsString() (string, error) {
+	return any.val, nil
+}
+
 func (any *stringAny) ToString() string {
 	return any.val
 }
 
+func (any *stringAny) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *stringAny) WriteTo(stream *Stream) {
 	stream.WriteString(any.val)
 }
@@ -0,0 +1,164 @@
+package any_tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checked_integers(t *testing.T) {
+	testCases := []struct {
+		input   string
+		int32   bool
+		int64   bool
+		uint32  bool
+		uint64  bool
+		float32 bool
+	}{
+		{`0`, true, true, true, true, true},
+		{`-0.0`, true, true, true, true, true},
+		{`1.55e1`, false, false, false, false, true},
+		{`1e2`, true, true, true, true, true},
+		{`1.0`, true, true, true, true, true},
+		{`-1`, true, true, false, false, true},
+		{`2147483647`, true, true, true, true, true},
+		{`2147483648`, false, true, true, true, true},
+		{`-2147483648`, true, true, false, false, true},
+		{`-2147483649`, false, true, false, false, true},
+		{`4294967296`, false, true, false, true, true},
+		{`9223372036854775808`, false, false, false, true, true},
+		{`-9223372036854775808`, false, true, false, false, true},
+		{`18446744073709551616`, false, false, false, false, true},
+		{`1e400`, false, false, false, false, false},
+		{`1e39`, false, false, false, false, false},
+	}
+	for _, testCase := range testCases {
+		should := require.New(t)
+		any := jsoniter.Get([]byte(testCase.input))
+		_, err := any.AsInt32()
+		should.Equal(testCase.int32, err == nil, testCase.input)
+		_, err = any.AsInt64()
+		should.Equal(testCase.int64, err == nil, testCase.input)
+		_, err = any.AsUint32()
+		should.Equal(testCase.uint32, err == nil, testCase.input)
+		_, err = any.AsUint64()
+		should.Equal(testCase.uint64, err == nil, testCase.input)
+		_, err = any.AsFloat32()
+		should.Equal(testCase.float32, err == nil, testCase.input)
+		_, err = any.AsFloat64()
+		should.Equal(testCase.input != `1e400`, err == nil, testCase.input)
+	}
+
+	should := require.New(t)
+	val, err := jsoniter.Get([]byte(`-9223372036854775808`)).AsInt64()
+	should.NoError(err)
+	should.Equal(int64(math.MinInt64), val)
+	uval, err := jsoniter.Get([]byte(`18446744073709551615`)).AsUint64()
+	should.NoError(err)
+	should.Equal(uint64(math.MaxUint64), uval)
+	f, err := jsoniter.Get([]byte(`[1.5e-3]`), 0).AsFloat64()
+	should.NoError(err)
+	should.Equal(1.5e-3, f)
+	i, err := jsoniter.WrapFloat64(3).AsInt()
+	should.NoError(err)
+	should.Equal(3, i)
+	_, err = jsoniter.WrapFloat64(3.5).AsInt()
+	should.Error(err)
+	_, err = jsoniter.WrapFloat64(math.NaN()).AsFloat64()
+	should.Error(err)
+	_, err = jsoniter.WrapInt64(-1).AsUint()
+	should.Error(err)
+	_, err = jsoniter.WrapUint64(1 << 40).AsInt32()
+	should.Error(err)
+	i32, err := jsoniter.WrapInt32(-7).AsInt32()
+	should.NoError(err)
+	should.Equal(int32(-7), i32)
+	u32, err := jsoniter.Wrap(uint8(7)).AsUint32()
+	should.NoError(err)
+	should.Equal(uint32(7), u32)
+	hex, err := jsoniter.Config{AllowJSON5Numbers: true}.Froze().Get([]byte(`0x1F`)).AsInt()
+	should.NoError(err)
+	should.Equal(31, hex)
+}
+
+func Test_checked_kinds(t *testing.T) {
+	should := require.New(t)
+	_, err := jsoniter.Get([]byte(`"12abc"`)).AsInt()
+	should.Error(err)
+	should.Equal(12, jsoniter.Get([]byte(`"12abc"`)).ToInt())
+	str, err := jsoniter.Get([]byte(`"12abc"`)).AsString()
+	should.NoError(err)
+	should.Equal("12abc", str)
+	_, err = jsoniter.Get([]byte(`12`)).AsString()
+	should.Error(err)
+	_, err = jsoniter.Get([]byte(`{"a":1}`)).AsFloat64()
+	should.Error(err)
+	_, err = jsoniter.Get([]byte(`[1]`)).AsBool()
+	should.Error(err)
+	_, err = jsoniter.Get([]byte(`null`)).AsBool()
+	should.Error(err)
+	b, err := jsoniter.Get([]byte(`false`)).AsBool()
+	should.NoError(err)
+	should.False(b)
+	b, err = jsoniter.Wrap(true).AsBool()
+	should.NoError(err)
+	should.True(b)
+	_, err = jsoniter.Get([]byte(`{"a":1}`), "b").AsInt()
+	should.Error(err)
+	should.Contains(err.Error(), "not found")
+	_, err = jsoniter.Get([]byte(`{"a":1}`), "b").AsString()
+	should.Error(err)
+
+	// the errors of a value of the wrong kind name both kinds
+	messages := map[string]error{
+		"AsInt: string is not a number":     second(jsoniter.Get([]byte(`"1"`)).AsInt()),
+		"AsString: number is not a string":  second(jsoniter.WrapInt32(1).AsString()),
+		"AsBool: number is not a bool":      second(jsoniter.Get([]byte(`1`)).AsBool()),
+		"AsBool: array is not a bool":       second(jsoniter.Wrap([]int{}).AsBool()),
+		"AsFloat64: object is not a number": second(jsoniter.Get([]byte(`{}`)).AsFloat64()),
+		"AsUint: null is not a number":      second(jsoniter.Get([]byte(`null`)).AsUint()),
+		"AsString: bool is not a string":    second(jsoniter.Get([]byte(`true`)).AsString()),
+	}
+	for message, err := range messages {
+		should.EqualError(err, message)
+	}
+}
+
+func second(_ interface{}, err error) error {
+	return err
+}
+
+func Test_checked_to_val(t *testing.T) {
+	should := require.New(t)
+	type account struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	any := jsoniter.Get([]byte(`{"users":[{"name":"a","count":1},{"name":"b","count":"x"}]}`))
+	var val account
+	should.NoError(any.Get("users", 0).AsVal(&val))
+	should.Equal(account{"a", 1}, val)
+	should.Error(any.Get("users", 1).AsVal(&val))
+	any.Get("users", 1).ToVal(&val)
+	should.Equal("b", val.Name)
+	var vals []account
+	users := any.Get("users")
+	should.Error(users.AsVal(&vals))
+	should.Nil(users.LastError())
+	users.ToVal(&vals)
+	should.Error(users.LastError())
+	var count int
+	should.NoError(jsoniter.Get([]byte(`[7]`), 0).AsVal(&count))
+	should.Equal(7, count)
+	should.Error(jsoniter.Get([]byte(`[7.5]`), 0).AsVal(&count))
+	var name string
+	should.NoError(jsoniter.Get([]byte(`["x"]`), 0).AsVal(&name))
+	should.Equal("x", name)
+	should.Error(jsoniter.WrapString("x").AsVal(&count))
+	var ints []int
+	should.NoError(jsoniter.Wrap([]int{1, 2}).AsVal(&ints))
+	should.Equal([]int{1, 2}, ints)
+	should.Error(jsoniter.Get([]byte(`[]`), 0).AsVal(&count))
+}
@@ -0,0 +1,95 @@
+package any_tests
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_edit_object_any(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`{ "id": 1, "user": { "name": "a",  "tags": [ "x" ] }, "big": [1, 2, 3] }`)
+	any := jsoniter.Get(input).(jsoniter.EditableAny)
+	should.NoError(any.Set("b", "user", "name"))
+	should.Equal(`{ "id": 1, "user": { "name": "b",  "tags": [ "x" ] }, "big": [1, 2, 3] }`, any.ToString())
+	should.NoError(any.Append([]int{1}, "user", "tags"))
+	should.NoError(any.Set(true, "user", "admin"))
+	should.Equal(`{ "id": 1, "user": { "name": "b",  "tags": [ "x",[1] ],"admin":true }, "big": [1, 2, 3] }`, any.ToString())
+	should.NoError(any.Delete("id"))
+	should.NoError(any.Delete("big", 1))
+	should.NoError(any.Set(2, "created", "by"))
+	should.Equal(`{ "user": { "name": "b",  "tags": [ "x",[1] ],"admin":true }, "big": [1, 3],"created":{"by":2} }`, any.ToString())
+	should.Equal(2, any.Get("created", "by").ToInt())
+	should.Equal([]string{"user", "big", "created"}, any.Keys())
+	should.Equal(`{ "id": 1, "user": { "name": "a",  "tags": [ "x" ] }, "big": [1, 2, 3] }`, string(input))
+
+	should.NoError(any.Delete("user"))
+	should.NoError(any.Delete("created"))
+	should.NoError(any.Delete("big"))
+	should.Equal(`{}`, any.ToString())
+	should.NoError(any.Set("z", "k"))
+	should.Equal(`{"k":"z"}`, any.ToString())
+
+	any = jsoniter.Get([]byte(`{"a":1,"b":2,"a":3}`)).(jsoniter.EditableAny)
+	should.NoError(any.Set(4, "a"))
+	should.Equal(`{"a":1,"b":2,"a":4}`, any.ToString())
+	should.NoError(any.Delete("a"))
+	should.Equal(`{"b":2}`, any.ToString())
+
+	any = jsoniter.Config{UseStructuralIndex: true}.Froze().Get([]byte(`{"a":[{"b":1},{"b":2}],"c":{}}`)).(jsoniter.EditableAny)
+	should.NoError(any.Set("x", "a", 1, "b"))
+	should.Equal("x", any.Get("a", 1, "b").ToString())
+	should.Equal(`{"a":[{"b":1},{"b":"x"}],"c":{}}`, any.ToString())
+
+	any = jsoniter.Get([]byte(`{"a":[1],"b":"c"}`)).(jsoniter.EditableAny)
+	should.Error(any.Set(1))
+	should.Error(any.Set(1, "a", 1))
+	should.Error(any.Set(1, "a", "b"))
+	should.Error(any.Set(1, 0))
+	should.Error(any.Set(1, "b", "c"))
+	should.Error(any.Delete("d"))
+	should.Error(any.Append(1))
+	should.Error(any.Append(1, "b"))
+	should.Error(any.Set(func() {}, "a", 0))
+	should.Equal(`{"a":[1],"b":"c"}`, any.ToString())
+	for _, val := range []jsoniter.Any{jsoniter.Wrap(1), jsoniter.Wrap([]int{}), jsoniter.Wrap(map[string]int{}),
+		jsoniter.Get([]byte(`{"a":1}`), "a")} {
+		_, editable := val.(jsoniter.EditableAny)
+		should.False(editable)
+	}
+	nested, editable := jsoniter.Get([]byte(`{"a":{"b":1}}`), "a").(jsoniter.EditableAny)
+	should.True(editable)
+	should.NoError(nested.Set(2, "b"))
+	should.Equal(`{"b":2}`, nested.ToString())
+}
+
+func Test_build_any(t *testing.T) {
+	should := require.New(t)
+	obj := jsoniter.NewObjectAny(jsoniter.ConfigDefault)
+	should.Equal(`{}`, obj.ToString())
+	should.NoError(obj.Set("x", "name"))
+	should.NoError(obj.Set(jsoniter.NewArrayAny(jsoniter.ConfigDefault, 1, "a", nil), "list"))
+	should.NoError(obj.Append(2.5, "list"))
+	should.NoError(obj.Set(jsoniter.Get([]byte(`{"k": [true]}`)), "nested", "raw"))
+	output, err := jsoniter.MarshalToString(obj)
+	should.NoError(err)
+	should.Equal(`{"name":"x","list":[1,"a",null,2.5],"nested":{"raw":{"k": [true]}}}`, output)
+	should.Equal(4, obj.Get("list").Size())
+
+	arr := jsoniter.NewArrayAny(jsoniter.ConfigDefault)
+	should.Equal(0, arr.Size())
+	should.NoError(arr.Append(jsoniter.NewObjectAny(jsoniter.ConfigDefault)))
+	should.NoError(arr.Set("v", 0, "k"))
+	should.NoError(arr.Append("w"))
+	should.NoError(arr.Delete(0))
+	should.Equal(`["w"]`, arr.ToString())
+	should.Error(jsoniter.NewArrayAny(jsoniter.ConfigDefault, func() {}).LastError())
+
+	unescaped := jsoniter.NewObjectAny(jsoniter.Config{}.Froze())
+	should.NoError(unescaped.Set("<a>", "html"))
+	should.Equal(`{"html":"<a>"}`, unescaped.ToString())
+	json5 := jsoniter.NewArrayAny(jsoniter.Config{AllowJSON5: true}.Froze())
+	should.NoError(json5.Append(1))
+	should.Equal(1, json5.Get(0).ToInt())
+}
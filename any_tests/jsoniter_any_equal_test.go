@@ -0,0 +1,74 @@
+package any_tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_equal_any(t *testing.T) {
+	equal := [][2]string{
+		{`1`, `1.0`},
+		{`100`, `1e2`},
+		{`0.05`, `5E-2`},
+		{`-0`, `0.000`},
+		{`12345678901234567890123`, `1.2345678901234567890123e22`},
+		{`"a"`, `"a"`},
+		{`[1, [true, null]]`, `[1.0,[true,null]]`},
+		{`{"a":1,"b":[2]}`, `{ "b" : [2.0], "a" : 1 }`},
+		{`{"a":0,"b":{},"a":1}`, `{"b":{},"a":1}`},
+		{`{}`, `{ }`},
+	}
+	for _, pair := range equal {
+		should := require.New(t)
+		a, b := jsoniter.Get([]byte(pair[0])), jsoniter.Get([]byte(pair[1]))
+		should.True(jsoniter.Equal(a, b), pair[0])
+		should.Equal(0, jsoniter.Compare(a, b), pair[0])
+		should.Equal(jsoniter.Hash(a), jsoniter.Hash(b), pair[0])
+	}
+	// each one before the next
+	ordered := []string{
+		`null`, `false`, `true`, `-1e400`, `-2`, `-1.5`, `0`, `1e-400`, `0.5`, `1`, `12345678901234567890`,
+		`12345678901234567891`, `""`, `"a"`, `"ab"`, `"b"`, `[]`, `[1]`, `[1,2]`, `[2]`, `{}`, `{"a":1}`,
+		`{"a":2}`, `{"a":2,"b":0}`, `{"b":0}`,
+	}
+	for i := range ordered {
+		should := require.New(t)
+		a := jsoniter.Get([]byte(ordered[i]))
+		should.Equal(0, jsoniter.Compare(a, a), ordered[i])
+		for _, after := range ordered[i+1:] {
+			b := jsoniter.Get([]byte(after))
+			should.Equal(-1, jsoniter.Compare(a, b), ordered[i]+" "+after)
+			should.Equal(1, jsoniter.Compare(b, a), ordered[i]+" "+after)
+			should.False(jsoniter.Equal(a, b), ordered[i]+" "+after)
+			should.NotEqual(jsoniter.Hash(a), jsoniter.Hash(b), ordered[i]+" "+after)
+		}
+	}
+
+	should := require.New(t)
+	lazy := jsoniter.Get([]byte(`{"n":[1,2.5,"x",true,null],"s":{"k":-3}}`))
+	type inner struct {
+		K int `json:"k"`
+	}
+	wrapped := jsoniter.Wrap(struct {
+		N []interface{}
+		S inner
+	}{[]interface{}{uint8(1), float32(2.5), "x", true, nil}, inner{-3}})
+	should.False(jsoniter.Equal(lazy, wrapped))
+	should.True(jsoniter.Equal(lazy.Get("n"), wrapped.Get("N")))
+	should.Equal(jsoniter.Hash(lazy.Get("n")), jsoniter.Hash(wrapped.Get("N")))
+	should.True(jsoniter.Equal(jsoniter.Get([]byte(`-1.5e1`)), jsoniter.WrapFloat64(-15)))
+	should.True(jsoniter.Equal(jsoniter.Get([]byte(`18446744073709551615`)), jsoniter.WrapUint64(math.MaxUint64)))
+	should.True(jsoniter.Equal(jsoniter.WrapFloat64(math.NaN()), jsoniter.WrapFloat64(math.NaN())))
+	should.Equal(-1, jsoniter.Compare(jsoniter.WrapFloat64(math.Inf(-1)), jsoniter.WrapInt64(math.MinInt64)))
+	should.True(jsoniter.Equal(nil, jsoniter.Get([]byte(`null`))))
+	should.Equal(-1, jsoniter.Compare(jsoniter.Get([]byte(`{`), "a"), jsoniter.Get([]byte(`null`))))
+
+	obj := jsoniter.NewObjectAny(jsoniter.ConfigDefault)
+	should.NoError(obj.Set(1, "b"))
+	should.NoError(obj.Set("x", "a"))
+	should.True(jsoniter.Equal(obj, jsoniter.Get([]byte(`{"a":"x","b":1.0}`))))
+	should.Equal(uint64(0x52e4c601979ddbe7), jsoniter.Hash(obj))
+}
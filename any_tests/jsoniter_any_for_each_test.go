@@ -0,0 +1,111 @@
+package any_tests
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_for_each(t *testing.T) {
+	input := []byte(`{"a":1,"b":[true,"x",{"c":null}],"a":2}`)
+	for _, api := range []jsoniter.API{jsoniter.ConfigDefault, jsoniter.Config{UseStructuralIndex: true}.Froze()} {
+		should := require.New(t)
+		any := api.Get(input)
+		keys, values := []string{}, []string{}
+		any.ForEach(func(key string, value jsoniter.Any) bool {
+			keys = append(keys, key)
+			values = append(values, value.ToString())
+			return true
+		})
+		should.Equal([]string{"a", "b", "a"}, keys)
+		should.Equal([]string{"1", `[true,"x",{"c":null}]`, "2"}, values)
+		indexes, types := []int{}, []jsoniter.ValueType{}
+		any.Get("b").ForEachIndex(func(index int, value jsoniter.Any) bool {
+			indexes = append(indexes, index)
+			types = append(types, value.ValueType())
+			return value.ValueType() != jsoniter.StringValue
+		})
+		should.Equal([]int{0, 1}, indexes)
+		should.Equal([]jsoniter.ValueType{jsoniter.BoolValue, jsoniter.StringValue}, types)
+		visits := 0
+		any.ForEachIndex(func(index int, value jsoniter.Any) bool {
+			visits++
+			return true
+		})
+		any.Get("b").ForEach(func(key string, value jsoniter.Any) bool {
+			visits++
+			return true
+		})
+		any.Get("a").ForEach(func(key string, value jsoniter.Any) bool {
+			visits++
+			return true
+		})
+		any.ForEach(func(key string, value jsoniter.Any) bool {
+			visits++
+			return false
+		})
+		should.Equal(1, visits)
+	}
+
+	should := require.New(t)
+	type item struct {
+		Name   string
+		Count  int
+		hidden bool
+	}
+	fields := map[string]interface{}{}
+	jsoniter.Wrap(item{"x", 2, true}).ForEach(func(key string, value jsoniter.Any) bool {
+		fields[key] = value.ToString()
+		return true
+	})
+	should.Equal(map[string]interface{}{"Name": "x", "Count": "2"}, fields)
+	fields = map[string]interface{}{}
+	jsoniter.Wrap(map[string]int{"a": 1, "b": 2}).ForEach(func(key string, value jsoniter.Any) bool {
+		fields[key] = value.ToInt()
+		return true
+	})
+	should.Equal(map[string]interface{}{"a": 1, "b": 2}, fields)
+	sum := 0
+	jsoniter.Wrap([]int{1, 2, 3}).ForEachIndex(func(index int, value jsoniter.Any) bool {
+		sum += index * value.ToInt()
+		return index < 1
+	})
+	should.Equal(2, sum)
+}
+
+type forEachKey struct {
+	a, b int
+}
+
+func (key forEachKey) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(key.a) + "-" + strconv.Itoa(key.b)), nil
+}
+
+func Test_for_each_of_map_with_keys_not_strings(t *testing.T) {
+	should := require.New(t)
+	for _, val := range []interface{}{
+		map[int]int{-1: 1, 2: 2},
+		map[uint8]int{255: 1},
+		map[forEachKey]int{{1, 2}: 1, {3, 4}: 2},
+	} {
+		// the keys encoding/json writes
+		output, err := json.Marshal(val)
+		should.NoError(err)
+		var expected map[string]int
+		should.NoError(json.Unmarshal(output, &expected))
+		fields := map[string]int{}
+		any := jsoniter.Wrap(val)
+		any.ForEach(func(key string, value jsoniter.Any) bool {
+			fields[key] = value.ToInt()
+			return true
+		})
+		should.Equal(expected, fields)
+		should.Len(any.Keys(), len(expected))
+		for _, key := range any.Keys() {
+			should.Contains(expected, key)
+		}
+	}
+}
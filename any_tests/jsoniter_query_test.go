@@ -0,0 +1,160 @@
+package any_tests
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+var storeDocument = []byte(`{ "store": {
+    "book": [
+      { "category": "reference", "author": "Nigel Rees",
+        "title": "Sayings of the Century", "price": 8.95 },
+      { "category": "fiction", "author": "Evelyn Waugh",
+        "title": "Sword of Honour", "price": 12.99 },
+      { "category": "fiction", "author": "Herman Melville",
+        "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99 },
+      { "category": "fiction", "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99 }
+    ],
+    "bicycle": { "color": "red", "price": 19.95 }
+  }
+}`)
+
+func queryAsStrings(should *require.Assertions, expr string) []string {
+	found, err := jsoniter.Query(storeDocument, expr)
+	should.Nil(err)
+	strs := []string{}
+	for _, any := range found {
+		strs = append(strs, any.ToString())
+	}
+	return strs
+}
+
+func Test_query_child_members(t *testing.T) {
+	should := require.New(t)
+	should.Equal([]string{"red"}, queryAsStrings(should, `$.store.bicycle.color`))
+	should.Equal([]string{"red"}, queryAsStrings(should, `$['store']["bicycle"]['color']`))
+	should.Equal([]string{"Nigel Rees"}, queryAsStrings(should, `$.store.book[0].author`))
+	should.Equal([]string{}, queryAsStrings(should, `$.store.car`))
+}
+
+func Test_query_type_mismatch(t *testing.T) {
+	testCases := []struct {
+		input string
+		expr  string
+	}{
+		{`{"a":1}`, `$.a.b`},
+		{`{"a":[1]}`, `$.a.b`},
+		{`{"a":null}`, `$.a[0]`},
+		{`{"a":"x"}`, `$.a[0]`},
+		{`{"a":1}`, `$[0]`},
+		{`[1]`, `$.x`},
+		{`[{"a":1}]`, `$[0][0]`},
+	}
+	for _, testCase := range testCases {
+		should := require.New(t)
+		found, err := jsoniter.Query([]byte(testCase.input), testCase.expr)
+		should.NoError(err, testCase.expr)
+		should.Empty(found, testCase.expr)
+	}
+}
+
+func Test_query_wildcard_and_union(t *testing.T) {
+	should := require.New(t)
+	should.Equal([]string{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"},
+		queryAsStrings(should, `$.store.book[*].author`))
+	should.Equal([]string{"Sayings of the Century", "Moby Dick"},
+		queryAsStrings(should, `$.store.book[0,2].title`))
+	should.Equal([]string{"reference", "Nigel Rees"},
+		queryAsStrings(should, `$.store.book[0]['category','author']`))
+}
+
+func Test_query_recursive_descent(t *testing.T) {
+	should := require.New(t)
+	should.Equal([]string{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"},
+		queryAsStrings(should, `$..author`))
+	should.Equal([]string{"8.95", "12.99", "8.99", "22.99", "19.95"},
+		queryAsStrings(should, `$.store..price`))
+	should.Equal([]string{"The Lord of the Rings"}, queryAsStrings(should, `$..book[-1].title`))
+}
+
+func Test_query_slice(t *testing.T) {
+	should := require.New(t)
+	should.Equal([]string{"Nigel Rees", "Evelyn Waugh"}, queryAsStrings(should, `$..book[:2].author`))
+	should.Equal([]string{"Evelyn Waugh", "J. R. R. Tolkien"}, queryAsStrings(should, `$..book[1::2].author`))
+	should.Equal([]string{"Herman Melville", "J. R. R. Tolkien"}, queryAsStrings(should, `$..book[-2:].author`))
+	should.Equal([]string{"J. R. R. Tolkien", "Herman Melville", "Evelyn Waugh", "Nigel Rees"},
+		queryAsStrings(should, `$..book[::-1].author`))
+}
+
+func Test_query_filter(t *testing.T) {
+	should := require.New(t)
+	should.Equal([]string{"Sayings of the Century", "Moby Dick"},
+		queryAsStrings(should, `$..book[?(@.price < 10)].title`))
+	should.Equal([]string{"Moby Dick", "The Lord of the Rings"},
+		queryAsStrings(should, `$..book[?(@.isbn)].title`))
+	should.Equal([]string{"Sayings of the Century", "Sword of Honour"},
+		queryAsStrings(should, `$..book[?(!@.isbn)].title`))
+	should.Equal([]string{"Moby Dick"},
+		queryAsStrings(should, `$..book[?(@.category == 'fiction' && @.price < 10)].title`))
+	should.Equal([]string{"Sayings of the Century", "The Lord of the Rings"},
+		queryAsStrings(should, `$..book[?(@.category == "reference" || (@.price > 20))].title`))
+	should.Equal([]string{"Sayings of the Century", "Moby Dick"},
+		queryAsStrings(should, `$..book[?(@.price < $.store.bicycle.price && @.price < 10)].title`))
+}
+
+func Test_query_filter_reads_root_after_literal_prefix(t *testing.T) {
+	should := require.New(t)
+	should.Equal([]string{"The Lord of the Rings"},
+		queryAsStrings(should, `$.store.book[?(@.price > $.store.bicycle.price)].title`))
+	should.Equal([]string{},
+		queryAsStrings(should, `$.store.book[?(@.price == $[0].price)].title`))
+	should.Equal([]string{"Sayings of the Century"},
+		queryAsStrings(should, `$.store.book[?(@.price == $.store.book[0].price)].title`))
+	should.Equal([]string{},
+		queryAsStrings(should, `$.store.car[?(@.price > $.store.bicycle.price)]`))
+}
+
+func Test_query_filter_compares_numbers_as_decimals(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`{"items":[{"id":9007199254740992},{"id":9007199254740993},{"id":1e2}]}`)
+	found, err := jsoniter.Query(input, `$.items[?(@.id == 9007199254740993)].id`)
+	should.NoError(err)
+	should.Len(found, 1)
+	should.Equal("9007199254740993", found[0].ToString())
+	found, err = jsoniter.Query(input, `$.items[?(@.id < $.items[1].id)].id`)
+	should.NoError(err)
+	should.Len(found, 2)
+	should.Equal("9007199254740992", found[0].ToString())
+	should.Equal("1e2", found[1].ToString())
+	found, err = jsoniter.Query(input, `$.items[?(@.id == 100)].id`)
+	should.NoError(err)
+	should.Len(found, 1)
+}
+
+func Test_query_returns_lazy_any(t *testing.T) {
+	should := require.New(t)
+	found, err := jsoniter.Query(storeDocument, `$.store.bicycle`)
+	should.Nil(err)
+	should.Len(found, 1)
+	should.Equal(jsoniter.ObjectValue, found[0].ValueType())
+	should.Equal(19.95, found[0].Get("price").ToFloat64())
+}
+
+func Test_query_invalid_expression(t *testing.T) {
+	should := require.New(t)
+	_, err := jsoniter.Query(storeDocument, `store.book`)
+	should.NotNil(err)
+	_, err = jsoniter.Query(storeDocument, `$.store[`)
+	should.NotNil(err)
+	_, err = jsoniter.Query(storeDocument, `$..book[?(@.price <)]`)
+	should.NotNil(err)
+}
+
+func Test_query_invalid_document(t *testing.T) {
+	should := require.New(t)
+	_, err := jsoniter.Query([]byte(`{"a":`), `$.a`)
+	should.NotNil(err)
+}
@@ -5,7 +5,7 @@ import (
 )
 
 type uint32Any struct {
-	baseAny
+	checkedNumber
 	val uint32
 }
 
@@ -61,6 +61,10 @@ func (any *uint32Any) ToString() string {
 	return strconv.FormatInt(int64(any.val), 10)
 }
 
+func (any *uint32Any) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *uint32Any) WriteTo(stream *Stream) {
 	stream.WriteUint32(any.val)
 }
@@ -5,7 +5,7 @@ import (
 )
 
 type uint64Any struct {
-	baseAny
+	checkedNumber
 	val uint64
 }
 
@@ -61,6 +61,10 @@ func (any *uint64Any) ToString() string {
 	return strconv.FormatUint(any.val, 10)
 }
 
+func (any *uint64Any) AsVal(obj interface{}) error {
+	return checkedVal(any, obj)
+}
+
 func (any *uint64Any) WriteTo(stream *Stream) {
 	stream.WriteUint64(any.val)
 }
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_document(t *testing.T) {
+	should := require.New(t)
+	input := `{"a": "shadowed", "e": 1.5, "a": {"b": [1, {"c": "d"}, [true, null]]}}`
+	doc, err := jsoniter.ParseDocument([]byte(input))
+	should.NoError(err)
+	root := doc.Root()
+	should.Equal(jsoniter.ObjectValue, root.ValueType())
+	should.Equal(3, root.Len())
+	should.Equal([]string{"a", "e", "a"}, root.Keys())
+	should.Equal(jsoniter.ObjectValue, root.Field("a").ValueType())
+
+	b := doc.Path("a", "b")
+	should.Equal(jsoniter.ArrayValue, b.ValueType())
+	should.Equal(3, b.Len())
+	should.Equal(`{"c": "d"}`, string(b.Index(1).Raw()))
+	should.Equal(`[true, null]`, string(doc.Path("a", "b", 2).Raw()))
+	should.Equal(jsoniter.NilValue, b.Path(2, 1).ValueType())
+
+	var c struct {
+		C string `json:"c"`
+	}
+	should.NoError(doc.Path("a", "b", 1).Decode(&c))
+	should.Equal("d", c.C)
+	var e float64
+	should.NoError(doc.Path("e").Decode(&e))
+	should.Equal(1.5, e)
+	should.Equal("d", doc.Path("a", "b").Any().Get(1, "c").ToString())
+
+	missing := doc.Path("a", "b", 3, "c")
+	should.Equal(jsoniter.InvalidValue, missing.ValueType())
+	should.Equal("[3 c] not found", missing.Err().Error())
+	should.Equal(missing.Err(), missing.Decode(&e))
+	should.Equal(jsoniter.InvalidValue, doc.Path("e", 0).ValueType())
+	should.Equal(jsoniter.InvalidValue, doc.Path("a", "b", -1).ValueType())
+	should.Equal(jsoniter.InvalidValue, missing.Path("x").ValueType())
+	should.Nil(missing.Raw())
+}
+
+func Test_document_large_object(t *testing.T) {
+	should := require.New(t)
+	var sb strings.Builder
+	sb.WriteString(`{"key7": "shadowed"`)
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&sb, `, "key%d": %d`, i, i)
+	}
+	sb.WriteString(`}`)
+	doc, err := jsoniter.ParseDocument([]byte(sb.String()))
+	should.NoError(err)
+	for i := 0; i < 100; i++ {
+		var n int
+		should.NoError(doc.Path(fmt.Sprintf("key%d", i)).Decode(&n))
+		should.Equal(i, n)
+	}
+	should.Equal(jsoniter.InvalidValue, doc.Path("key100").ValueType())
+}
+
+func Test_document_repeated_keys(t *testing.T) {
+	should := require.New(t)
+	small := `{"a": 1, "b": 2, "a": 3}`
+	var sb strings.Builder
+	sb.WriteString(`{"a": 1`)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&sb, `, "key%d": %d`, i, i)
+	}
+	sb.WriteString(`, "a": 3}`)
+	for _, input := range []string{small, sb.String()} {
+		doc, err := jsoniter.ParseDocument([]byte(input))
+		should.NoError(err)
+		should.Equal(`3`, string(doc.Path("a").Raw()))
+		should.Equal(jsoniter.Get([]byte(input), "a").ToInt(), doc.Root().Field("a").Any().ToInt())
+		keys := doc.Root().Keys()
+		should.Equal("a", keys[0])
+		should.Equal("a", keys[len(keys)-1])
+	}
+	doc, err := jsoniter.Config{DuplicateKeys: jsoniter.DuplicateKeysFirstWins}.Froze().ParseDocument([]byte(small))
+	should.NoError(err)
+	should.Equal(`1`, string(doc.Path("a").Raw()))
+	should.Equal([]string{"a", "b"}, doc.Root().Keys())
+	_, err = jsoniter.Config{DuplicateKeys: jsoniter.DuplicateKeysError}.Froze().ParseDocument([]byte(small))
+	should.Error(err)
+}
+
+func Test_document_errors(t *testing.T) {
+	should := require.New(t)
+	for _, input := range []string{``, `{"a":}`, `[1, 2`, `{} []`, `{"a": [1, 2,]}`} {
+		_, err := jsoniter.ParseDocument([]byte(input))
+		should.Error(err, input)
+	}
+	_, err := jsoniter.Config{MaxDepth: 2}.Froze().ParseDocument([]byte(`[[[1]]]`))
+	should.Error(err)
+
+	doc, err := jsoniter.ParseDocument([]byte("{\n  \"a\": [1, \"x\"]\n}"))
+	should.NoError(err)
+	var ints []int
+	err = doc.Path("a").Decode(&ints)
+	decodeErr, isDecodeErr := err.(*jsoniter.DecodeError)
+	should.True(isDecodeErr, "%v", err)
+	should.Equal("/1", decodeErr.Path)
+	should.Equal(2, decodeErr.Line)
+}
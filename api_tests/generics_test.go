@@ -0,0 +1,80 @@
+//go:build go1.18
+// +build go1.18
+
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type genericsPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func Test_unmarshal_as(t *testing.T) {
+	should := require.New(t)
+	point, err := jsoniter.UnmarshalAs[genericsPoint](jsoniter.ConfigDefault, []byte(`{"x":1,"y":2}`))
+	should.NoError(err)
+	should.Equal(genericsPoint{1, 2}, point)
+	points, err := jsoniter.UnmarshalAs[[]*genericsPoint](jsoniter.ConfigDefault, []byte(`[{"x":1},null]`))
+	should.NoError(err)
+	should.Equal([]*genericsPoint{{X: 1}, nil}, points)
+	any, err := jsoniter.UnmarshalAs[interface{}](jsoniter.ConfigDefault, []byte(`[1,"a"]`))
+	should.NoError(err)
+	should.Equal([]interface{}{float64(1), "a"}, any)
+	_, err = jsoniter.UnmarshalAs[genericsPoint](jsoniter.ConfigDefault, []byte(`{"x":"1"}`))
+	decodeErr, isDecodeErr := err.(*jsoniter.DecodeError)
+	should.True(isDecodeErr, "%v", err)
+	should.Equal("/x", decodeErr.Path)
+	_, err = jsoniter.UnmarshalAs[int](jsoniter.ConfigDefault, []byte(`1 2`))
+	should.Error(err)
+}
+
+func Test_marshal_t(t *testing.T) {
+	should := require.New(t)
+	output, err := jsoniter.MarshalT(jsoniter.ConfigDefault, genericsPoint{1, 2})
+	should.NoError(err)
+	should.Equal(`{"x":1,"y":2}`, string(output))
+	output, err = jsoniter.MarshalT[*genericsPoint](jsoniter.ConfigDefault, nil)
+	should.NoError(err)
+	should.Equal(`null`, string(output))
+	output, err = jsoniter.MarshalT[interface{}](jsoniter.ConfigDefault, []int{1})
+	should.NoError(err)
+	should.Equal(`[1]`, string(output))
+	output, err = jsoniter.MarshalT(jsoniter.ConfigCompatibleWithStandardLibrary, "<a>")
+	should.NoError(err)
+	should.Equal(`"\u003ca\u003e"`, string(output))
+}
+
+func Test_codec(t *testing.T) {
+	should := require.New(t)
+	codec := jsoniter.NewCodec[genericsPoint](jsoniter.ConfigDefault)
+	iter := jsoniter.ParseString(jsoniter.ConfigDefault, `{"x":1} {"y":2}`)
+	should.Equal(genericsPoint{X: 1}, codec.Decode(iter))
+	should.Equal(genericsPoint{Y: 2}, codec.Decode(iter))
+	should.NoError(iter.Error)
+	buf := &bytes.Buffer{}
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, buf, 64)
+	codec.Encode(stream, genericsPoint{3, 4})
+	stream.WriteRaw(" ")
+	codec.Encode(stream, genericsPoint{})
+	should.NoError(stream.Flush())
+	should.Equal(`{"x":3,"y":4} {"x":0,"y":0}`, buf.String())
+	point, err := codec.Unmarshal([]byte(`{"y":5}`))
+	should.NoError(err)
+	should.Equal(genericsPoint{Y: 5}, point)
+}
+
+func Benchmark_codec_decode(b *testing.B) {
+	codec := jsoniter.NewCodec[genericsPoint](jsoniter.ConfigDefault)
+	data := []byte(`{"x":1,"y":2}`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		codec.Unmarshal(data)
+	}
+}
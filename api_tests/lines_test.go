@@ -0,0 +1,98 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type logLine struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+func Test_lines_decoder(t *testing.T) {
+	should := require.New(t)
+	input := "{\"level\":\"info\",\"msg\":\"a\"}\n" +
+		"{\"level\":\"info\",\"msg\":\n" +
+		"\r\n" +
+		"{\"level\":1}\n" +
+		"{\"level\":\"warn\",\"msg\":\"b\"} {}\n" +
+		"{\"level\":\"error\",\"msg\":\"c\"}"
+	decoder := jsoniter.NewLinesDecoder(strings.NewReader(input))
+	var line logLine
+	should.NoError(decoder.Decode(&line))
+	should.Equal(logLine{"info", "a"}, line)
+	err := decoder.Decode(&line)
+	lineErr, isLineErr := err.(*jsoniter.LineError)
+	should.True(isLineErr, "%v", err)
+	should.Equal(2, lineErr.Line)
+	should.Contains(err.Error(), "line 2: ")
+	err = decoder.Decode(&line)
+	lineErr, isLineErr = err.(*jsoniter.LineError)
+	should.True(isLineErr, "%v", err)
+	should.Equal(4, lineErr.Line)
+	_, isDecodeErr := lineErr.Err.(*jsoniter.DecodeError)
+	should.True(isDecodeErr)
+	err = decoder.Decode(&line)
+	should.Contains(err.Error(), "line 5: LinesDecoder: there are bytes left after the value")
+	line = logLine{}
+	should.NoError(decoder.Decode(&line))
+	should.Equal(logLine{"error", "c"}, line)
+	should.Equal(6, decoder.Line())
+	should.Equal(io.EOF, decoder.Decode(&line))
+	should.Equal(io.EOF, decoder.Decode(&line))
+	should.NoError(decoder.Err())
+}
+
+func Test_lines_decoder_next(t *testing.T) {
+	should := require.New(t)
+	input := "{\"msg\":\"a\"}\n[\n{\"msg\":\"b\"}\n\n{\"msg\":\"c\"}\n"
+	decoder := jsoniter.NewLinesDecoder(strings.NewReader(input))
+	skipped := []int{}
+	decoder.OnLineError(func(err *jsoniter.LineError) {
+		skipped = append(skipped, err.Line)
+	})
+	msgs := []string{}
+	var line logLine
+	for decoder.Next(&line) {
+		msgs = append(msgs, line.Msg)
+	}
+	should.NoError(decoder.Err())
+	should.Equal([]string{"a", "b", "c"}, msgs)
+	should.Equal([]int{2}, skipped)
+}
+
+func Test_lines_decoder_long_lines(t *testing.T) {
+	should := require.New(t)
+	long := strings.Repeat("x", 10000)
+	input := `"` + long + "\"\n\"short\"\n"
+	decoder := jsoniter.NewLinesDecoder(strings.NewReader(input))
+	var str string
+	should.NoError(decoder.Decode(&str))
+	should.Equal(long, str)
+	should.NoError(decoder.Decode(&str))
+	should.Equal("short", str)
+
+	api := jsoniter.Config{MaxInputBytes: 100}.Froze()
+	decoder = api.NewLinesDecoder(strings.NewReader(input))
+	err := decoder.Decode(&str)
+	should.Contains(err.Error(), "line 1: loadMore: exceeded max input bytes of 100")
+	should.NoError(decoder.Decode(&str))
+	should.Equal("short", str)
+}
+
+func Test_lines_encoder(t *testing.T) {
+	should := require.New(t)
+	var buf bytes.Buffer
+	encoder := jsoniter.Config{IndentionStep: 2}.Froze().NewLinesEncoder(&buf)
+	should.NoError(encoder.Encode(logLine{"info", "a"}))
+	should.Error(encoder.Encode([]float64{1, math.NaN()}))
+	should.NoError(encoder.Encode([]int{1, 2}))
+	should.Equal("{\"level\":\"info\",\"msg\":\"a\"}\n[1,2]\n", buf.String())
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type mergePatchAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type mergePatchAudit struct {
+	Version int `json:"version"`
+}
+
+type mergePatchUser struct {
+	*mergePatchAudit
+	Name    string                        `json:"name"`
+	Age     int                           `json:"age"`
+	Tags    []string                      `json:"tags"`
+	Address mergePatchAddress             `json:"address"`
+	Manager *mergePatchUser               `json:"manager"`
+	Labels  map[string]string             `json:"labels"`
+	Extra   interface{}                   `json:"extra"`
+	Limits  map[string]*mergePatchAddress `json:"limits"`
+}
+
+func Test_merge_patch_only_overwrites_present_fields(t *testing.T) {
+	should := require.New(t)
+	user := mergePatchUser{Name: "John", Age: 42, Tags: []string{"a", "b"},
+		Address: mergePatchAddress{Street: "Main", City: "Paris"}}
+	should.NoError(jsoniter.MergePatch(&user, []byte(`{"age":43,"tags":["c"],"address":{"city":"Lyon"}}`)))
+	should.Equal("John", user.Name)
+	should.Equal(43, user.Age)
+	should.Equal([]string{"c"}, user.Tags)
+	should.Equal(mergePatchAddress{Street: "Main", City: "Lyon"}, user.Address)
+}
+
+func Test_merge_patch_null_zeroes_fields(t *testing.T) {
+	should := require.New(t)
+	user := mergePatchUser{Name: "John", Age: 42, Tags: []string{"a"},
+		Address: mergePatchAddress{Street: "Main", City: "Paris"}, Manager: &mergePatchUser{Name: "Jane"}}
+	should.NoError(jsoniter.MergePatch(&user, []byte(`{"age":null,"tags":null,"address":null,"manager":null}`)))
+	should.Equal(mergePatchUser{Name: "John"}, user)
+}
+
+func Test_merge_patch_nested_pointer_and_map(t *testing.T) {
+	should := require.New(t)
+	user := mergePatchUser{Manager: &mergePatchUser{Name: "Jane", Age: 50},
+		Labels: map[string]string{"a": "1", "b": "2"},
+		Limits: map[string]*mergePatchAddress{"x": {Street: "S", City: "C"}}}
+	should.NoError(jsoniter.MergePatch(&user, []byte(
+		`{"manager":{"age":51,"address":{"city":"Nice"}},"labels":{"a":null,"c":"3"},"limits":{"x":{"city":"D"}}}`)))
+	should.Equal(&mergePatchUser{Name: "Jane", Age: 51, Address: mergePatchAddress{City: "Nice"}}, user.Manager)
+	should.Equal(map[string]string{"b": "2", "c": "3"}, user.Labels)
+	should.Equal(&mergePatchAddress{Street: "S", City: "D"}, user.Limits["x"])
+	should.NoError(jsoniter.MergePatch(&user, []byte(`{"manager":{"manager":{"name":"Boss"}}}`)))
+	should.Equal("Boss", user.Manager.Manager.Name)
+}
+
+func Test_merge_patch_embedded_pointer_and_interface(t *testing.T) {
+	should := require.New(t)
+	user := mergePatchUser{Extra: map[string]interface{}{"a": "b", "c": map[string]interface{}{"d": "e", "f": "g"}}}
+	should.NoError(jsoniter.MergePatch(&user, []byte(`{"version":2,"extra":{"a":null,"c":{"f":null,"h":1}}}`)))
+	should.Equal(2, user.Version)
+	should.Equal(map[string]interface{}{"c": map[string]interface{}{"d": "e", "h": float64(1)}}, user.Extra)
+	should.NoError(jsoniter.MergePatch(&user, []byte(`{"extra":[1]}`)))
+	should.Equal([]interface{}{float64(1)}, user.Extra)
+}
+
+func Test_merge_patch_rfc7396_examples(t *testing.T) {
+	examples := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, example := range examples {
+		t.Run(example[0]+" "+example[1], func(t *testing.T) {
+			should := require.New(t)
+			var target, expected interface{}
+			should.NoError(jsoniter.UnmarshalFromString(example[0], &target))
+			should.NoError(jsoniter.UnmarshalFromString(example[2], &expected))
+			should.NoError(jsoniter.MergePatch(&target, []byte(example[1])))
+			should.Equal(expected, target)
+		})
+	}
+}
+
+func Test_merge_patch_errors(t *testing.T) {
+	should := require.New(t)
+	user := mergePatchUser{}
+	should.Error(jsoniter.MergePatch(user, []byte(`{}`)))
+	should.Error(jsoniter.MergePatch(&user, []byte(`{"age":"x"}`)))
+	should.Error(jsoniter.MergePatch(&user, []byte(`{"age":1}}`)))
+	api := jsoniter.Config{DisallowUnknownFields: true}.Froze()
+	should.Error(api.MergePatch(&user, []byte(`{"unknown":1}`)))
+	should.NoError(jsoniter.MergePatch(&user, []byte(`{"unknown":1}`)))
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type parallelItem struct {
+	ID   int      `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func parallelInput(n int, bad int) string {
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for i := 0; i < n; i++ {
+		if i != 0 {
+			sb.WriteString(",\n")
+		}
+		if i == bad {
+			fmt.Fprintf(&sb, `  {"id":%d, "name":{}}`, i)
+			continue
+		}
+		fmt.Fprintf(&sb, `  {"id":%d, "name":"item %d", "tags":["a", "b"]}`, i, i)
+	}
+	sb.WriteString("\n]")
+	return sb.String()
+}
+
+func Test_decode_array_parallel(t *testing.T) {
+	should := require.New(t)
+	input := parallelInput(3000, -1)
+	var expected []parallelItem
+	should.NoError(jsoniter.UnmarshalFromString(input, &expected))
+
+	decoded := make([]parallelItem, 3000)
+	indexes := []int{}
+	err := jsoniter.DecodeArrayParallel(strings.NewReader(input), 4, func() interface{} {
+		return &parallelItem{}
+	}, func(i int, v interface{}) {
+		decoded[i] = *v.(*parallelItem)
+		indexes = append(indexes, i)
+	})
+	should.NoError(err)
+	should.Equal(expected, decoded)
+	sort.Ints(indexes)
+	should.Len(indexes, 3000)
+	should.Equal(2999, indexes[2999])
+
+	decoded = decoded[:0]
+	err = jsoniter.DecodeArrayParallelOrdered(strings.NewReader(input), 4, func() interface{} {
+		return &parallelItem{}
+	}, func(i int, v interface{}) {
+		should.Equal(len(decoded), i)
+		decoded = append(decoded, *v.(*parallelItem))
+	})
+	should.NoError(err)
+	should.Equal(expected, decoded)
+
+	err = jsoniter.DecodeArrayParallel(strings.NewReader(`null`), 4, func() interface{} {
+		return &parallelItem{}
+	}, func(i int, v interface{}) {
+		should.Fail("no element expected")
+	})
+	should.NoError(err)
+}
+
+func Test_decode_array_parallel_errors(t *testing.T) {
+	should := require.New(t)
+	input := parallelInput(2000, 1500)
+	var sequential []parallelItem
+	expected, isDecodeErr := jsoniter.UnmarshalFromString(input, &sequential).(*jsoniter.DecodeError)
+	should.True(isDecodeErr)
+
+	sunk := 0
+	err := jsoniter.DecodeArrayParallelOrdered(strings.NewReader(input), 4, func() interface{} {
+		return &parallelItem{}
+	}, func(i int, v interface{}) {
+		sunk++
+	})
+	decodeErr, isDecodeErr := err.(*jsoniter.DecodeError)
+	should.True(isDecodeErr, "%v", err)
+	should.Equal(1500, sunk)
+	should.Equal("/1500/name", decodeErr.Path)
+	should.Equal(expected.Path, decodeErr.Path)
+	should.Equal(expected.Offset, decodeErr.Offset)
+	should.Equal(expected.Line, decodeErr.Line)
+	should.Equal(expected.Column, decodeErr.Column)
+
+	err = jsoniter.DecodeArrayParallel(strings.NewReader(`[{"id":1},{"id":2]`), 4, func() interface{} {
+		return &parallelItem{}
+	}, func(i int, v interface{}) {})
+	should.Error(err)
+	err = jsoniter.DecodeArrayParallel(strings.NewReader(`{}`), 4, func() interface{} {
+		return &parallelItem{}
+	}, func(i int, v interface{}) {})
+	should.Contains(err.Error(), "expect [ or n")
+}
+
+func Test_lines_decoder_parallel(t *testing.T) {
+	should := require.New(t)
+	var sb strings.Builder
+	for i := 1; i <= 1000; i++ {
+		if i == 500 {
+			sb.WriteString("{\"msg\":\n\n")
+			i++
+			continue
+		}
+		fmt.Fprintf(&sb, "{\"msg\":\"%d\"}\n", i)
+	}
+	decoder := jsoniter.NewLinesDecoder(strings.NewReader(sb.String()))
+	skipped := []int{}
+	decoder.OnLineError(func(err *jsoniter.LineError) {
+		skipped = append(skipped, err.Line)
+	})
+	lines := []int{}
+	err := decoder.DecodeParallelOrdered(3, func() interface{} {
+		return &logLine{}
+	}, func(line int, v interface{}) {
+		should.Equal(fmt.Sprint(line), v.(*logLine).Msg)
+		lines = append(lines, line)
+	})
+	should.NoError(err)
+	should.Equal([]int{500}, skipped)
+	should.Len(lines, 998)
+	should.Equal(499, lines[498])
+	should.Equal(502, lines[499])
+}
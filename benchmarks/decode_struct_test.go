@@ -0,0 +1,43 @@
+package test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/json-iterator/go"
+)
+
+type benchmarkItem struct {
+	Name  string   `json:"name"`
+	Price float64  `json:"price"`
+	Tags  []string `json:"tags"`
+}
+
+type benchmarkOrder struct {
+	ID    int             `json:"id"`
+	Items []benchmarkItem `json:"items"`
+}
+
+// the decoders of struct fields and slice elements remember where each value starts, for the
+// location of its errors
+func Benchmark_decode_struct_slice(b *testing.B) {
+	input := &bytes.Buffer{}
+	input.WriteString(`{"id":1,"items":[`)
+	for i := 0; i < 100; i++ {
+		if i != 0 {
+			input.WriteString(",")
+		}
+		input.WriteString(`{"name":"item` + strconv.Itoa(i) + `","price":1.5,"tags":["a","b"]}`)
+	}
+	input.WriteString(`]}`)
+	data := input.Bytes()
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var order benchmarkOrder
+		if err := jsoniter.Unmarshal(data, &order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/json-iterator/go"
+)
+
+// largeDocument is about 6 MB of records with strings, numbers, literals and nested containers.
+var largeDocument = func() []byte {
+	doc := &bytes.Buffer{}
+	doc.WriteString(`{"records":[`)
+	for i := 0; i < 20000; i++ {
+		if i != 0 {
+			doc.WriteString(",")
+		}
+		id := strconv.Itoa(i)
+		doc.WriteString(`{"id":` + id + `,"name":"record ` + id + `","price":` + id + `.25,` +
+			`"active":true,"parent":null,"tags":["alpha","beta","gamma"],` +
+			`"owner":{"login":"user` + id + `","url":"https://example.com/users/` + id + `",` +
+			`"scores":[1,2.5,-3e2,4],"note":"escaped \"quotes\" and \\ slashes"},` +
+			`"history":[{"at":1500000000,"by":"a"},{"at":1500000001,"by":"b"}]}`)
+	}
+	doc.WriteString(`],"last":{"id":"end"}}`)
+	return doc.Bytes()
+}()
+
+func benchmarkSkip(b *testing.B, api jsoniter.API) {
+	b.SetBytes(int64(len(largeDocument)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		iter := api.BorrowIterator(largeDocument)
+		iter.Skip()
+		if iter.Error != nil {
+			b.Fatal(iter.Error)
+		}
+		api.ReturnIterator(iter)
+	}
+}
+
+// the default config reads RFC 8259 without looking for the extensions of JSON5
+func Benchmark_skip_large_document(b *testing.B) {
+	benchmarkSkip(b, jsoniter.ConfigDefault)
+}
+
+func Benchmark_skip_large_document_json5(b *testing.B) {
+	benchmarkSkip(b, jsoniter.Config{AllowJSON5: true}.Froze())
+}
+
+// the index is not built for the input read once
+func Benchmark_skip_large_document_structural_index(b *testing.B) {
+	benchmarkSkip(b, jsoniter.Config{UseStructuralIndex: true}.Froze())
+}
+
+func benchmarkGet(b *testing.B, api jsoniter.API) {
+	b.SetBytes(int64(len(largeDocument)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if api.Get(largeDocument, "last", "id").ToString() != "end" {
+			b.Fatal("last not found")
+		}
+	}
+}
+
+func Benchmark_get_large_document(b *testing.B) {
+	benchmarkGet(b, jsoniter.ConfigDefault)
+}
+
+func Benchmark_get_large_document_structural_index(b *testing.B) {
+	benchmarkGet(b, jsoniter.Config{UseStructuralIndex: true}.Froze())
+}
+
+// benchmarkLookups looks up records spread over the document, the second one building the index
+func benchmarkLookups(b *testing.B, api jsoniter.API) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		records := api.Get(largeDocument, "records")
+		for n := 0; n < 20; n++ {
+			id := n * 997
+			if records.Get(id, "owner", "login").ToString() != "user"+strconv.Itoa(id) {
+				b.Fatal("record not found")
+			}
+		}
+	}
+}
+
+func Benchmark_lookups_large_document(b *testing.B) {
+	benchmarkLookups(b, jsoniter.ConfigDefault)
+}
+
+func Benchmark_lookups_large_document_structural_index(b *testing.B) {
+	benchmarkLookups(b, jsoniter.Config{UseStructuralIndex: true}.Froze())
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const generatedHeader = "// Code generated by jsoniter-gen. DO NOT EDIT."
+
+type generator struct {
+	typeNames  []string
+	tagKey     string
+	onlyTagged bool
+	fset       *token.FileSet
+	pkgName    string
+	// the type declarations and method names of the package, by type name
+	specs    map[string]*ast.TypeSpec
+	methods  map[string]map[string]bool
+	structs  map[string]*structDescription
+	typeVars []string
+	warnings []string
+	// the helpers the generated code calls
+	usesIsEmpty, usesWrite, usesField bool
+}
+
+// structDescription lists the fields of a generated struct, as describeStruct does.
+type structDescription struct {
+	name     string
+	bindings []*binding
+	encoders []*binding
+	decoders []*binding
+}
+
+// binding is a field of a struct, or of a struct it embeds.
+type binding struct {
+	levels    []int
+	path      []pathStep
+	names     []string
+	tagged    bool
+	omitempty bool
+	typ       ast.Expr
+}
+
+// pathStep is a struct field on the way to a binding, ptr when it is an embedded pointer.
+type pathStep struct {
+	name string
+	ptr  bool
+	elem string
+}
+
+func (gen *generator) parseDir(dir string, output string) error {
+	gen.fset = token.NewFileSet()
+	skipped, _ := filepath.Abs(output)
+	pkgs, err := parser.ParseDir(gen.fset, dir, func(info os.FileInfo) bool {
+		path, _ := filepath.Abs(filepath.Join(dir, info.Name()))
+		return !strings.HasSuffix(info.Name(), "_test.go") && path != skipped
+	}, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	if len(pkgs) != 1 {
+		return fmt.Errorf("expect one package in %s, found %d", dir, len(pkgs))
+	}
+	gen.specs = map[string]*ast.TypeSpec{}
+	gen.methods = map[string]map[string]bool{}
+	for pkgName, pkg := range pkgs {
+		gen.pkgName = pkgName
+		for _, file := range pkg.Files {
+			if len(file.Comments) != 0 && file.Comments[0].Text() == generatedHeader[3:]+"\n" {
+				continue
+			}
+			gen.collect(file)
+		}
+	}
+	return nil
+}
+
+func (gen *generator) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if typeSpec, isTypeSpec := spec.(*ast.TypeSpec); isTypeSpec {
+					gen.specs[typeSpec.Name.Name] = typeSpec
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) != 1 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, isStar := recv.(*ast.StarExpr); isStar {
+				recv = star.X
+			}
+			if ident, isIdent := recv.(*ast.Ident); isIdent {
+				if gen.methods[ident.Name] == nil {
+					gen.methods[ident.Name] = map[string]bool{}
+				}
+				gen.methods[ident.Name][decl.Name.Name] = true
+			}
+		}
+	}
+}
+
+// selectStructs describes the struct types to generate, leaving out the ones reflection must handle.
+func (gen *generator) selectStructs() error {
+	names := gen.typeNames
+	if names == nil {
+		for name, spec := range gen.specs {
+			if _, isStruct := spec.Type.(*ast.StructType); isStruct && ast.IsExported(name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+	gen.structs = map[string]*structDescription{}
+	for _, name := range names {
+		spec := gen.specs[name]
+		if spec == nil {
+			return fmt.Errorf("type %s not found in package %s", name, gen.pkgName)
+		}
+		structType, isStruct := spec.Type.(*ast.StructType)
+		if !isStruct || spec.Assign.IsValid() || typeParamsOf(spec) != nil {
+			return fmt.Errorf("type %s is not a struct", name)
+		}
+		if gen.hasMarshalMethods(name) {
+			gen.warnf("%s: left to reflection, it has its own marshal methods", name)
+			continue
+		}
+		bindings, err := gen.describeStruct(structType, map[string]bool{name: true})
+		if err != nil {
+			gen.warnf("%s: left to reflection, %v", name, err)
+			continue
+		}
+		gen.structs[name] = &structDescription{
+			name:     name,
+			bindings: bindings,
+			encoders: encodedBindings(bindings),
+			decoders: decodedBindings(bindings),
+		}
+	}
+	return nil
+}
+
+func (gen *generator) warnf(format string, args ...interface{}) {
+	gen.warnings = append(gen.warnings, fmt.Sprintf(format, args...))
+}
+
+func (gen *generator) hasMarshalMethods(name string) bool {
+	methods := gen.methods[name]
+	return methods["MarshalJSON"] || methods["UnmarshalJSON"] || methods["MarshalText"] || methods["UnmarshalText"]
+}
+
+// describeStruct lists the bindings of a struct, following the rules of jsoniter's describeStruct.
+func (gen *generator) describeStruct(structType *ast.StructType, embedding map[string]bool) ([]*binding, error) {
+	embeddedBindings := []*binding{}
+	bindings := []*binding{}
+	i := -1
+	for _, field := range structType.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		tagValue, hasTag := reflect.StructTag(tag).Lookup(gen.tagKey)
+		tagParts := strings.Split(tagValue, ",")
+		for _, option := range tagParts[1:] {
+			if option == "string" {
+				return nil, fmt.Errorf("the string option is not supported")
+			}
+		}
+		fieldNames := []string{}
+		for _, name := range field.Names {
+			fieldNames = append(fieldNames, name.Name)
+		}
+		anonymous := len(field.Names) == 0
+		if anonymous {
+			name, err := embeddedName(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			fieldNames = []string{name}
+		}
+		for _, fieldName := range fieldNames {
+			i++
+			if gen.onlyTagged && !hasTag && !anonymous {
+				continue
+			}
+			if tagValue == "-" || fieldName == "_" {
+				continue
+			}
+			if anonymous && tagParts[0] == "" {
+				embedded, isPtr, err := gen.embeddedStruct(field.Type)
+				if err != nil {
+					return nil, err
+				}
+				if embedded != nil {
+					if embedding[fieldName] {
+						return nil, fmt.Errorf("%s is embedded recursively", fieldName)
+					}
+					embedding[fieldName] = true
+					promoted, err := gen.describeStruct(embedded, embedding)
+					delete(embedding, fieldName)
+					if err != nil {
+						return nil, err
+					}
+					step := pathStep{name: fieldName, ptr: isPtr, elem: fieldName}
+					for _, binding := range promoted {
+						binding.levels = append([]int{i}, binding.levels...)
+						binding.path = append([]pathStep{step}, binding.path...)
+						embeddedBindings = append(embeddedBindings, binding)
+					}
+					continue
+				}
+			}
+			bindings = append(bindings, &binding{
+				levels:    []int{i},
+				path:      []pathStep{{name: fieldName}},
+				names:     calcFieldNames(fieldName, tagParts[0], tagValue),
+				tagged:    tagValue != "",
+				omitempty: hasOption(tagParts, "omitempty"),
+				typ:       field.Type,
+			})
+		}
+	}
+	allBindings := append(embeddedBindings, bindings...)
+	sort.SliceStable(allBindings, func(i, j int) bool {
+		left, right := allBindings[i].levels, allBindings[j].levels
+		for k := 0; ; k++ {
+			if left[k] != right[k] {
+				return left[k] < right[k]
+			}
+		}
+	})
+	return allBindings, nil
+}
+
+func hasOption(tagParts []string, option string) bool {
+	for _, tagPart := range tagParts[1:] {
+		if tagPart == option {
+			return true
+		}
+	}
+	return false
+}
+
+func calcFieldNames(originalFieldName string, tagProvidedFieldName string, wholeTag string) []string {
+	if wholeTag == "-" {
+		return []string{}
+	}
+	fieldNames := []string{originalFieldName}
+	if tagProvidedFieldName != "" {
+		fieldNames = []string{tagProvidedFieldName}
+	}
+	if unicode.IsLower(rune(originalFieldName[0])) || originalFieldName[0] == '_' {
+		return []string{}
+	}
+	return fieldNames
+}
+
+// embeddedName returns the name of the field of an embedded type.
+func embeddedName(typ ast.Expr) (string, error) {
+	if star, isStar := typ.(*ast.StarExpr); isStar {
+		typ = star.X
+	}
+	switch typ := typ.(type) {
+	case *ast.Ident:
+		return typ.Name, nil
+	case *ast.SelectorExpr:
+		return typ.Sel.Name, nil
+	}
+	return "", fmt.Errorf("can not embed %s", exprString(typ))
+}
+
+// embeddedStruct returns the struct promoting its fields, nil if the embedded type is not a struct.
+func (gen *generator) embeddedStruct(typ ast.Expr) (structType *ast.StructType, isPtr bool, err error) {
+	if star, isStar := typ.(*ast.StarExpr); isStar {
+		typ, isPtr = star.X, true
+	}
+	if selector, isSelector := typ.(*ast.SelectorExpr); isSelector {
+		return nil, false, fmt.Errorf("%s is embedded from another package", exprString(selector))
+	}
+	ident, isIdent := typ.(*ast.Ident)
+	if !isIdent {
+		return nil, false, nil
+	}
+	structType, _ = gen.underlying(ident).(*ast.StructType)
+	return structType, isPtr, nil
+}
+
+// underlying returns the underlying type of a type of the package, following the type names.
+func (gen *generator) underlying(typ ast.Expr) ast.Expr {
+	for seen := 0; seen < len(gen.specs); seen++ {
+		ident, isIdent := typ.(*ast.Ident)
+		if !isIdent || gen.specs[ident.Name] == nil {
+			return typ
+		}
+		typ = gen.specs[ident.Name].Type
+	}
+	return typ
+}
+
+// decodedBindings keeps the bindings decoded from each field name, as structFieldDecodersOf does.
+func decodedBindings(bindings []*binding) []*binding {
+	byName := map[string]*binding{}
+	for _, binding := range bindings {
+		for _, name := range binding.names {
+			old := byName[name]
+			if old == nil {
+				byName[name] = binding
+				continue
+			}
+			ignoreOld, ignoreNew := resolveConflictBinding(old, binding)
+			if ignoreOld {
+				delete(byName, name)
+			}
+			if !ignoreNew {
+				byName[name] = binding
+			}
+		}
+	}
+	decoded := []*binding{}
+	for _, binding := range bindings {
+		if len(binding.names) != 0 && byName[binding.names[0]] == binding {
+			decoded = append(decoded, binding)
+		}
+	}
+	return decoded
+}
+
+// encodedBindings keeps the bindings encoded in order, as encoderOfStruct does.
+func encodedBindings(bindings []*binding) []*binding {
+	type bindingTo struct {
+		binding *binding
+		ignored bool
+	}
+	ordered := []*bindingTo{}
+	for _, binding := range bindings {
+		for _, name := range binding.names {
+			new := &bindingTo{binding: binding}
+			for _, old := range ordered {
+				if old.binding.names[0] == name {
+					old.ignored, new.ignored = resolveConflictBinding(old.binding, new.binding)
+				}
+			}
+			ordered = append(ordered, new)
+		}
+	}
+	encoded := []*binding{}
+	for _, bindingTo := range ordered {
+		if !bindingTo.ignored {
+			encoded = append(encoded, bindingTo.binding)
+		}
+	}
+	return encoded
+}
+
+func resolveConflictBinding(old, new *binding) (ignoreOld, ignoreNew bool) {
+	if new.tagged != old.tagged {
+		return true, false
+	}
+	if len(old.levels) > len(new.levels) {
+		return true, false
+	} else if len(new.levels) > len(old.levels) {
+		return false, true
+	}
+	return true, true
+}
+
+func typeParamsOf(spec *ast.TypeSpec) interface{} {
+	field := reflect.ValueOf(spec).Elem().FieldByName("TypeParams")
+	if !field.IsValid() || field.IsNil() {
+		return nil
+	}
+	return field.Interface()
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
@@ -0,0 +1,954 @@
+// Code generated by jsoniter-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+func init() {
+	jsoniter.RegisterExtension(&jsoniterExtension{})
+}
+
+type jsoniterExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (extension *jsoniterExtension) DecorateEncoder(typ reflect2.Type, encoder jsoniter.ValEncoder) jsoniter.ValEncoder {
+	switch typ.Type1() {
+	case jsoniterType0.Type1():
+		return &jsoniterEncoderAddress{encoder}
+	case jsoniterType1.Type1():
+		return &jsoniterEncoderAudit{encoder}
+	case jsoniterType2.Type1():
+		return &jsoniterEncoderCustomer{encoder}
+	case jsoniterType3.Type1():
+		return &jsoniterEncoderEmpty{encoder}
+	case jsoniterType4.Type1():
+		return &jsoniterEncoderItem{encoder}
+	case jsoniterType5.Type1():
+		return &jsoniterEncoderOrder{encoder}
+	case jsoniterType6.Type1():
+		return &jsoniterEncoderVersioned{encoder}
+	}
+	return encoder
+}
+
+func (extension *jsoniterExtension) DecorateDecoder(typ reflect2.Type, decoder jsoniter.ValDecoder) jsoniter.ValDecoder {
+	switch typ.Type1() {
+	case jsoniterType0.Type1():
+		return &jsoniterDecoderAddress{decoder}
+	case jsoniterType1.Type1():
+		return &jsoniterDecoderAudit{decoder}
+	case jsoniterType2.Type1():
+		return &jsoniterDecoderCustomer{decoder}
+	case jsoniterType3.Type1():
+		return &jsoniterDecoderEmpty{decoder}
+	case jsoniterType4.Type1():
+		return &jsoniterDecoderItem{decoder}
+	case jsoniterType5.Type1():
+		return &jsoniterDecoderOrder{decoder}
+	case jsoniterType6.Type1():
+		return &jsoniterDecoderVersioned{decoder}
+	}
+	return decoder
+}
+
+type jsoniterEncoderAddress struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderAddress) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeAddress((*Address)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderAddress) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderAddress struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderAddress) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeAddress((*Address)(ptr), iter)
+}
+
+func jsoniterEncodeAddress(v *Address, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("street")
+	jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Street))
+	stream.WriteMore()
+	stream.WriteObjectField("city")
+	jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.City))
+	stream.WriteMore()
+	stream.WriteObjectField("name")
+	jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Name))
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeAddress(v *Address, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		switch jsoniterField(jsoniterFieldsAddress, key) {
+		case 0:
+			v.Street = iter.ReadString()
+		case 1:
+			v.City = iter.ReadString()
+		case 2:
+			v.Name = iter.ReadString()
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+var jsoniterFieldsAddress = map[string]int{
+	"street": 0,
+	"city":   1,
+	"name":   2,
+}
+
+type jsoniterEncoderAudit struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderAudit) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeAudit((*Audit)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderAudit) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderAudit struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderAudit) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeAudit((*Audit)(ptr), iter)
+}
+
+func jsoniterEncodeAudit(v *Audit, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	more := false
+	if v.CreatedBy != "" {
+		stream.WriteObjectField("created_by")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.CreatedBy))
+		more = true
+	}
+	if more {
+		stream.WriteMore()
+	}
+	stream.WriteObjectField("version")
+	stream.WriteInt(v.Version)
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeAudit(v *Audit, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		switch jsoniterField(jsoniterFieldsAudit, key) {
+		case 0:
+			v.CreatedBy = iter.ReadString()
+		case 1:
+			if !iter.ReadNil() {
+				v.Version = iter.ReadInt()
+			}
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+var jsoniterFieldsAudit = map[string]int{
+	"created_by": 0,
+	"version":    1,
+}
+
+type jsoniterEncoderCustomer struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderCustomer) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeCustomer((*Customer)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderCustomer) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderCustomer struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderCustomer) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeCustomer((*Customer)(ptr), iter)
+}
+
+func jsoniterEncodeCustomer(v *Customer, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("Name")
+	jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Name))
+	if v.Address != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("street")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Address.Street))
+	}
+	if v.Address != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("city")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Address.City))
+	}
+	if v.Address != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("name")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Address.Name))
+	}
+	if v.Email != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("email")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Email))
+	}
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeCustomer(v *Customer, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		switch jsoniterField(jsoniterFieldsCustomer, key) {
+		case 0:
+			v.Name = iter.ReadString()
+		case 1:
+			if v.Address == nil {
+				v.Address = new(Address)
+			}
+			v.Address.Street = iter.ReadString()
+		case 2:
+			if v.Address == nil {
+				v.Address = new(Address)
+			}
+			v.Address.City = iter.ReadString()
+		case 3:
+			if v.Address == nil {
+				v.Address = new(Address)
+			}
+			v.Address.Name = iter.ReadString()
+		case 4:
+			v.Email = iter.ReadString()
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+var jsoniterFieldsCustomer = map[string]int{
+	"Name":   0,
+	"street": 1,
+	"city":   2,
+	"name":   3,
+	"email":  4,
+}
+
+type jsoniterEncoderEmpty struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderEmpty) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeEmpty((*Empty)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderEmpty) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderEmpty struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderEmpty) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeEmpty((*Empty)(ptr), iter)
+}
+
+func jsoniterEncodeEmpty(v *Empty, stream *jsoniter.Stream) {
+	stream.WriteEmptyObject()
+}
+
+func jsoniterDecodeEmpty(v *Empty, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		iter.Skip()
+		return true
+	})
+}
+
+type jsoniterEncoderItem struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderItem) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeItem((*Item)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderItem) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderItem struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderItem) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeItem((*Item)(ptr), iter)
+}
+
+func jsoniterEncodeItem(v *Item, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("sku")
+	jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.SKU))
+	stream.WriteMore()
+	stream.WriteObjectField("qty")
+	stream.WriteInt(int(v.Quantity))
+	stream.WriteMore()
+	stream.WriteObjectField("price")
+	jsoniterWrite(stream, jsoniterType8, unsafe.Pointer(&v.Price))
+	if len(v.Options) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("options")
+		if v.Options == nil {
+			stream.WriteNil()
+		} else if len(v.Options) == 0 {
+			stream.WriteEmptyArray()
+		} else {
+			stream.WriteArrayStart()
+			for i0 := range v.Options {
+				if i0 > 0 {
+					stream.WriteMore()
+				}
+				if v.Options[i0] == nil {
+					stream.WriteNil()
+				} else {
+					jsoniterEncodeItem(v.Options[i0], stream)
+				}
+			}
+			stream.WriteArrayEnd()
+		}
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("weight")
+	if v.Weight == nil {
+		stream.WriteNil()
+	} else {
+		jsoniterWrite(stream, jsoniterType8, unsafe.Pointer(v.Weight))
+	}
+	if v.Related != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("related")
+		if v.Related == nil {
+			stream.WriteNil()
+		} else {
+			if *v.Related == nil {
+				stream.WriteNil()
+			} else if len(*v.Related) == 0 {
+				stream.WriteEmptyArray()
+			} else {
+				stream.WriteArrayStart()
+				for i0 := range *v.Related {
+					if i0 > 0 {
+						stream.WriteMore()
+					}
+					jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&(*v.Related)[i0]))
+				}
+				stream.WriteArrayEnd()
+			}
+		}
+	}
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeItem(v *Item, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		switch jsoniterField(jsoniterFieldsItem, key) {
+		case 0:
+			v.SKU = iter.ReadString()
+		case 1:
+			if !iter.ReadNil() {
+				v.Quantity = Quantity(iter.ReadInt())
+			}
+		case 2:
+			if !iter.ReadNil() {
+				v.Price = iter.ReadFloat64()
+			}
+		case 3:
+			if iter.ReadNil() {
+				v.Options = nil
+			} else {
+				s0 := v.Options[:0]
+				i0 := 0
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					if i0 < cap(s0) {
+						s0 = s0[:i0+1]
+					} else {
+						s0 = append(s0, *new(*Item))
+					}
+					if iter.ReadNil() {
+						s0[i0] = nil
+					} else {
+						if s0[i0] == nil {
+							s0[i0] = new(Item)
+						}
+						jsoniterDecodeItem(s0[i0], iter)
+					}
+					i0++
+					return true
+				})
+				if s0 == nil {
+					s0 = []*Item{}
+				}
+				v.Options = s0
+			}
+		case 4:
+			if iter.ReadNil() {
+				v.Weight = nil
+			} else {
+				if v.Weight == nil {
+					v.Weight = new(float64)
+				}
+				if !iter.ReadNil() {
+					*v.Weight = iter.ReadFloat64()
+				}
+			}
+		case 5:
+			if iter.ReadNil() {
+				v.Related = nil
+			} else {
+				if v.Related == nil {
+					v.Related = new([]string)
+				}
+				if iter.ReadNil() {
+					*v.Related = nil
+				} else {
+					s0 := (*v.Related)[:0]
+					i0 := 0
+					iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+						if i0 < cap(s0) {
+							s0 = s0[:i0+1]
+						} else {
+							s0 = append(s0, *new(string))
+						}
+						s0[i0] = iter.ReadString()
+						i0++
+						return true
+					})
+					if s0 == nil {
+						s0 = []string{}
+					}
+					*v.Related = s0
+				}
+			}
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+var jsoniterFieldsItem = map[string]int{
+	"sku":     0,
+	"qty":     1,
+	"price":   2,
+	"options": 3,
+	"weight":  4,
+	"related": 5,
+}
+
+type jsoniterEncoderOrder struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderOrder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeOrder((*Order)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderOrder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderOrder struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderOrder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeOrder((*Order)(ptr), iter)
+}
+
+func jsoniterEncodeOrder(v *Order, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	stream.WriteObjectField("id")
+	stream.WriteInt64(v.ID)
+	if v.Status != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("status")
+		jsoniterWrite(stream, jsoniterType9, unsafe.Pointer(&v.Status))
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("customer")
+	if v.Customer == nil {
+		stream.WriteNil()
+	} else {
+		jsoniterEncodeCustomer(v.Customer, stream)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("items")
+	if v.Items == nil {
+		stream.WriteNil()
+	} else if len(v.Items) == 0 {
+		stream.WriteEmptyArray()
+	} else {
+		stream.WriteArrayStart()
+		for i0 := range v.Items {
+			if i0 > 0 {
+				stream.WriteMore()
+			}
+			jsoniterEncodeItem(&v.Items[i0], stream)
+		}
+		stream.WriteArrayEnd()
+	}
+	if len(v.Tags) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("tags")
+		if v.Tags == nil {
+			stream.WriteNil()
+		} else if len(v.Tags) == 0 {
+			stream.WriteEmptyArray()
+		} else {
+			stream.WriteArrayStart()
+			for i0 := range v.Tags {
+				if i0 > 0 {
+					stream.WriteMore()
+				}
+				jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Tags[i0]))
+			}
+			stream.WriteArrayEnd()
+		}
+	}
+	if len(v.Matrix) != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("matrix")
+		if v.Matrix == nil {
+			stream.WriteNil()
+		} else if len(v.Matrix) == 0 {
+			stream.WriteEmptyArray()
+		} else {
+			stream.WriteArrayStart()
+			for i0 := range v.Matrix {
+				if i0 > 0 {
+					stream.WriteMore()
+				}
+				if v.Matrix[i0] == nil {
+					stream.WriteNil()
+				} else if len(v.Matrix[i0]) == 0 {
+					stream.WriteEmptyArray()
+				} else {
+					stream.WriteArrayStart()
+					for i1 := range v.Matrix[i0] {
+						if i1 > 0 {
+							stream.WriteMore()
+						}
+						jsoniterWrite(stream, jsoniterType8, unsafe.Pointer(&v.Matrix[i0][i1]))
+					}
+					stream.WriteArrayEnd()
+				}
+			}
+			stream.WriteArrayEnd()
+		}
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("codes")
+	if len(v.Codes) == 0 {
+		stream.WriteEmptyArray()
+	} else {
+		stream.WriteArrayStart()
+		for i0 := range v.Codes {
+			if i0 > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteUint8(v.Codes[i0])
+		}
+		stream.WriteArrayEnd()
+	}
+	if v.Notes != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("notes")
+		if v.Notes == nil {
+			stream.WriteNil()
+		} else {
+			jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(v.Notes))
+		}
+	}
+	if !jsoniterIsEmpty(stream, reflect2.TypeOfPtr(&v.Extra).Elem(), unsafe.Pointer(&v.Extra)) {
+		stream.WriteMore()
+		stream.WriteObjectField("extra")
+		stream.WriteVal(&v.Extra)
+	}
+	if !jsoniterIsEmpty(stream, reflect2.TypeOfPtr(&v.Raw).Elem(), unsafe.Pointer(&v.Raw)) {
+		stream.WriteMore()
+		stream.WriteObjectField("raw")
+		stream.WriteVal(&v.Raw)
+	}
+	if !jsoniterIsEmpty(stream, reflect2.TypeOfPtr(&v.Blob).Elem(), unsafe.Pointer(&v.Blob)) {
+		stream.WriteMore()
+		stream.WriteObjectField("blob")
+		stream.WriteVal(&v.Blob)
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("created_at")
+	stream.WriteVal(&v.CreatedAt)
+	if v.Discount != 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("discount")
+		jsoniterWrite(stream, jsoniterType10, unsafe.Pointer(&v.Discount))
+	}
+	if v.Paid {
+		stream.WriteMore()
+		stream.WriteObjectField("paid")
+		stream.WriteBool(v.Paid)
+	}
+	if v.Audit.CreatedBy != "" {
+		stream.WriteMore()
+		stream.WriteObjectField("created_by")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Audit.CreatedBy))
+	}
+	stream.WriteMore()
+	stream.WriteObjectField("version")
+	stream.WriteInt(v.Audit.Version)
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeOrder(v *Order, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		switch jsoniterField(jsoniterFieldsOrder, key) {
+		case 0:
+			if !iter.ReadNil() {
+				v.ID = iter.ReadInt64()
+			}
+		case 1:
+			v.Status = Status(iter.ReadString())
+		case 2:
+			if iter.ReadNil() {
+				v.Customer = nil
+			} else {
+				if v.Customer == nil {
+					v.Customer = new(Customer)
+				}
+				jsoniterDecodeCustomer(v.Customer, iter)
+			}
+		case 3:
+			if iter.ReadNil() {
+				v.Items = nil
+			} else {
+				s0 := v.Items[:0]
+				i0 := 0
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					if i0 < cap(s0) {
+						s0 = s0[:i0+1]
+					} else {
+						s0 = append(s0, *new(Item))
+					}
+					jsoniterDecodeItem(&s0[i0], iter)
+					i0++
+					return true
+				})
+				if s0 == nil {
+					s0 = []Item{}
+				}
+				v.Items = s0
+			}
+		case 4:
+			if iter.ReadNil() {
+				v.Tags = nil
+			} else {
+				s0 := v.Tags[:0]
+				i0 := 0
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					if i0 < cap(s0) {
+						s0 = s0[:i0+1]
+					} else {
+						s0 = append(s0, *new(string))
+					}
+					s0[i0] = iter.ReadString()
+					i0++
+					return true
+				})
+				if s0 == nil {
+					s0 = []string{}
+				}
+				v.Tags = s0
+			}
+		case 5:
+			if iter.ReadNil() {
+				v.Matrix = nil
+			} else {
+				s0 := v.Matrix[:0]
+				i0 := 0
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					if i0 < cap(s0) {
+						s0 = s0[:i0+1]
+					} else {
+						s0 = append(s0, *new([]float64))
+					}
+					if iter.ReadNil() {
+						s0[i0] = nil
+					} else {
+						s1 := s0[i0][:0]
+						i1 := 0
+						iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+							if i1 < cap(s1) {
+								s1 = s1[:i1+1]
+							} else {
+								s1 = append(s1, *new(float64))
+							}
+							if !iter.ReadNil() {
+								s1[i1] = iter.ReadFloat64()
+							}
+							i1++
+							return true
+						})
+						if s1 == nil {
+							s1 = []float64{}
+						}
+						s0[i0] = s1
+					}
+					i0++
+					return true
+				})
+				if s0 == nil {
+					s0 = [][]float64{}
+				}
+				v.Matrix = s0
+			}
+		case 6:
+			i0 := 0
+			iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+				if i0 >= len(v.Codes) {
+					iter.Skip()
+					return true
+				}
+				if !iter.ReadNil() {
+					v.Codes[i0] = iter.ReadUint8()
+				}
+				i0++
+				return true
+			})
+		case 7:
+			if iter.ReadNil() {
+				v.Notes = nil
+			} else {
+				if v.Notes == nil {
+					v.Notes = new(string)
+				}
+				*v.Notes = iter.ReadString()
+			}
+		case 8:
+			iter.ReadVal(&v.Extra)
+		case 9:
+			iter.ReadVal(&v.Raw)
+		case 10:
+			iter.ReadVal(&v.Blob)
+		case 11:
+			iter.ReadVal(&v.CreatedAt)
+		case 12:
+			if !iter.ReadNil() {
+				v.Discount = iter.ReadFloat32()
+			}
+		case 13:
+			if !iter.ReadNil() {
+				v.Paid = iter.ReadBool()
+			}
+		case 14:
+			v.Audit.CreatedBy = iter.ReadString()
+		case 15:
+			if !iter.ReadNil() {
+				v.Audit.Version = iter.ReadInt()
+			}
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+var jsoniterFieldsOrder = map[string]int{
+	"id":         0,
+	"status":     1,
+	"customer":   2,
+	"items":      3,
+	"tags":       4,
+	"matrix":     5,
+	"codes":      6,
+	"notes":      7,
+	"extra":      8,
+	"raw":        9,
+	"blob":       10,
+	"created_at": 11,
+	"discount":   12,
+	"paid":       13,
+	"created_by": 14,
+	"version":    15,
+}
+
+type jsoniterEncoderVersioned struct {
+	fallback jsoniter.ValEncoder
+}
+
+func (encoder *jsoniterEncoderVersioned) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if jsoniterEncodesByReflection(stream) {
+		encoder.fallback.Encode(ptr, stream)
+		return
+	}
+	jsoniterEncodeVersioned((*Versioned)(ptr), stream)
+}
+
+func (encoder *jsoniterEncoderVersioned) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.fallback.IsEmpty(ptr)
+}
+
+type jsoniterDecoderVersioned struct {
+	fallback jsoniter.ValDecoder
+}
+
+func (decoder *jsoniterDecoderVersioned) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if jsoniterDecodesByReflection(iter) {
+		decoder.fallback.Decode(ptr, iter)
+		return
+	}
+	jsoniterDecodeVersioned((*Versioned)(ptr), iter)
+}
+
+func jsoniterEncodeVersioned(v *Versioned, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	more := false
+	if v.Audit.CreatedBy != "" {
+		stream.WriteObjectField("created_by")
+		jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Audit.CreatedBy))
+		more = true
+	}
+	if more {
+		stream.WriteMore()
+	}
+	stream.WriteObjectField("version")
+	jsoniterWrite(stream, jsoniterType7, unsafe.Pointer(&v.Version))
+	stream.WriteObjectEnd()
+}
+
+func jsoniterDecodeVersioned(v *Versioned, iter *jsoniter.Iterator) {
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		switch jsoniterField(jsoniterFieldsVersioned, key) {
+		case 0:
+			v.Audit.CreatedBy = iter.ReadString()
+		case 1:
+			v.Version = iter.ReadString()
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+}
+
+var jsoniterFieldsVersioned = map[string]int{
+	"created_by": 0,
+	"version":    1,
+}
+
+var (
+	jsoniterType0  = reflect2.TypeOfPtr((*Address)(nil)).Elem()
+	jsoniterType1  = reflect2.TypeOfPtr((*Audit)(nil)).Elem()
+	jsoniterType2  = reflect2.TypeOfPtr((*Customer)(nil)).Elem()
+	jsoniterType3  = reflect2.TypeOfPtr((*Empty)(nil)).Elem()
+	jsoniterType4  = reflect2.TypeOfPtr((*Item)(nil)).Elem()
+	jsoniterType5  = reflect2.TypeOfPtr((*Order)(nil)).Elem()
+	jsoniterType6  = reflect2.TypeOfPtr((*Versioned)(nil)).Elem()
+	jsoniterType7  = reflect2.TypeOfPtr((*string)(nil)).Elem()
+	jsoniterType8  = reflect2.TypeOfPtr((*float64)(nil)).Elem()
+	jsoniterType9  = reflect2.TypeOfPtr((*Status)(nil)).Elem()
+	jsoniterType10 = reflect2.TypeOfPtr((*float32)(nil)).Elem()
+)
+
+func jsoniterEncodesByReflection(stream *jsoniter.Stream) bool {
+	cfg := stream.Pool().(jsoniter.API).Config()
+	return cfg.Canonical || (cfg.TagKey != "" && cfg.TagKey != "json") || cfg.OnlyTaggedField
+}
+
+func jsoniterDecodesByReflection(iter *jsoniter.Iterator) bool {
+	cfg := iter.Pool().(jsoniter.API).Config()
+	return cfg.CaseSensitive || cfg.DisallowUnknownFields || cfg.CollectAllErrors || (cfg.TagKey != "" && cfg.TagKey != "json") || cfg.OnlyTaggedField
+}
+
+func jsoniterIsEmpty(stream *jsoniter.Stream, typ reflect2.Type, ptr unsafe.Pointer) bool {
+	return stream.Pool().(jsoniter.API).EncoderOf(typ).IsEmpty(ptr)
+}
+
+func jsoniterWrite(stream *jsoniter.Stream, typ reflect2.Type, ptr unsafe.Pointer) {
+	stream.Pool().(jsoniter.API).EncoderOf(typ).Encode(ptr, stream)
+}
+
+func jsoniterField(fields map[string]int, key string) int {
+	if field, found := fields[key]; found {
+		return field
+	}
+	if field, found := fields[strings.ToLower(key)]; found {
+		return field
+	}
+	return -1
+}
@@ -0,0 +1,167 @@
+package example
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder() *Order {
+	notes := "<fragile>"
+	weight := 1.5
+	related := []string{"b"}
+	return &Order{
+		ID:     1,
+		Status: "paid",
+		Customer: &Customer{
+			Name:    "Ann",
+			Address: &Address{Street: "Main", City: "Oslo", Name: "home"},
+		},
+		Items: []Item{
+			{SKU: "a", Quantity: 2, Price: 0.1, Weight: &weight, Related: &related,
+				Options: []*Item{{SKU: "a1"}, nil}},
+			{SKU: "b"},
+		},
+		Tags:      []string{},
+		Matrix:    [][]float64{{1, 2.5}, nil, {}},
+		Codes:     [3]uint8{1, 2, 3},
+		Notes:     &notes,
+		Extra:     []interface{}{"x", 1.0},
+		Raw:       json.RawMessage(`{"k":true}`),
+		Blob:      []byte("blob"),
+		CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Discount:  0.25,
+		Internal:  "hidden",
+		Audit:     Audit{CreatedBy: "bot", Version: 3},
+	}
+}
+
+func Test_generated_codecs_encode_as_std(t *testing.T) {
+	api := jsoniter.ConfigCompatibleWithStandardLibrary
+	for _, val := range []interface{}{
+		newOrder(),
+		&Order{},
+		&Customer{Email: "a@b"},
+		&Versioned{Audit: Audit{CreatedBy: "x", Version: 1}, Version: "v2"},
+		&Empty{},
+		[]Priced{{Cents: 150}},
+	} {
+		should := require.New(t)
+		expected, err := json.Marshal(val)
+		should.NoError(err)
+		output, err := api.Marshal(val)
+		should.NoError(err)
+		should.Equal(string(expected), string(output))
+	}
+}
+
+func Test_generated_codecs_decode_as_std(t *testing.T) {
+	api := jsoniter.ConfigCompatibleWithStandardLibrary
+	inputs := []string{
+		`{"id":1,"status":"paid","customer":{"Name":"Ann","street":"Main","name":"home"},` +
+			`"items":[{"sku":"a","qty":2,"options":[null,{"SKU":"b"}],"weight":2.5,"related":["x"]},{"qty":null}],` +
+			`"tags":[],"matrix":[[1],null,[]],"codes":[1,2,3,4],"notes":"n","extra":[1],` +
+			`"raw":{"k":1},"blob":"YmxvYg==","created_at":"2020-01-02T03:04:05Z","paid":true,` +
+			`"created_by":"bot","version":3,"unknown":[1,{}]}`,
+		`{"customer":null,"items":null,"codes":[1],"notes":null}`,
+	}
+	for _, input := range inputs {
+		should := require.New(t)
+		var expected, actual Order
+		should.NoError(json.Unmarshal([]byte(input), &expected))
+		should.NoError(api.Unmarshal([]byte(input), &actual))
+		should.Equal(expected, actual)
+	}
+	should := require.New(t)
+	var expected, actual Versioned
+	input := `{"version":"v2","created_by":"x"}`
+	should.NoError(json.Unmarshal([]byte(input), &expected))
+	should.NoError(api.Unmarshal([]byte(input), &actual))
+	should.Equal(expected, actual)
+}
+
+func Test_generated_codecs_decode_into_existing_value(t *testing.T) {
+	should := require.New(t)
+	order := newOrder()
+	should.NoError(jsoniter.UnmarshalFromString(`{"items":[{"qty":5}],"customer":{"city":"Rome"}}`, order))
+	should.Len(order.Items, 1)
+	should.Equal("a", order.Items[0].SKU)
+	should.Equal(Quantity(5), order.Items[0].Quantity)
+	should.Equal("Rome", order.Customer.City)
+	should.Equal("Main", order.Customer.Street)
+}
+
+// reflectedOrder has the fields of Order without its generated codecs.
+type reflectedOrder Order
+
+func Test_generated_codecs_follow_config(t *testing.T) {
+	should := require.New(t)
+	order := &Order{Notes: new(string), Discount: 1.0 / 3}
+	*order.Notes = "<b>"
+	output, err := jsoniter.Config{EscapeHTML: false, MarshalFloatWith6Digits: true}.Froze().MarshalToString(order)
+	should.NoError(err)
+	should.Contains(output, `"notes":"<b>"`)
+	should.Contains(output, `"discount":0.333333`)
+}
+
+func Test_generated_codecs_decode_as_reflection(t *testing.T) {
+	inputs := []string{
+		`{"id":1,"Status":"paid","customer":{"Name":"a","name":"b","NAME":"c","City":"d"},` +
+			`"items":[{"SKU":"a","qty":1},{"sku":"b","Qty":"x","price":"y"}],"unknown":1,"version":2}`,
+		`{"ID":1,"items":[{"options":[{"qty":true}]}],"codes":[1,"x"]}`,
+		`{"id":1,"customer":{"name":"b"}}`,
+	}
+	for _, cfg := range []jsoniter.Config{
+		{},
+		{CaseSensitive: true},
+		{DisallowUnknownFields: true},
+		{CollectAllErrors: true},
+		{CaseSensitive: true, DisallowUnknownFields: true, CollectAllErrors: true},
+		{TagKey: "xml"},
+		{OnlyTaggedField: true},
+	} {
+		api := cfg.Froze()
+		for _, input := range inputs {
+			should := require.New(t)
+			var generated Order
+			var reflected reflectedOrder
+			generatedErr := api.UnmarshalFromString(input, &generated)
+			reflectedErr := api.UnmarshalFromString(input, &reflected)
+			should.Equal(reflected, reflectedOrder(generated), "%+v %s", cfg, input)
+			if generatedErr == nil || reflectedErr == nil || cfg != (jsoniter.Config{}) {
+				// the errors of the generated codecs have no Path, Struct nor Field
+				should.Equal(reflectedErr == nil, generatedErr == nil, "%+v %s", cfg, input)
+			}
+		}
+	}
+}
+
+func Test_generated_codecs_encode_as_reflection(t *testing.T) {
+	should := require.New(t)
+	for _, api := range []jsoniter.API{
+		jsoniter.ConfigDefault,
+		jsoniter.ConfigCanonical,
+		jsoniter.Config{TagKey: "xml"}.Froze(),
+		jsoniter.Config{OnlyTaggedField: true}.Froze(),
+	} {
+		generated, err := api.Marshal(newOrder())
+		should.NoError(err)
+		reflected, err := api.Marshal((*reflectedOrder)(newOrder()))
+		should.NoError(err)
+		should.Equal(string(reflected), string(generated))
+	}
+}
+
+func Test_generated_codecs_report_the_offset(t *testing.T) {
+	should := require.New(t)
+	var order Order
+	input := `{"items":[{},{"options":[{"qty":"x"}]}]}`
+	err := jsoniter.UnmarshalFromString(input, &order)
+	decodeErr, isDecodeErr := err.(*jsoniter.DecodeError)
+	should.True(isDecodeErr, "%v", err)
+	should.Equal("", decodeErr.Path)
+	should.Equal(int64(len(`{"items":[{},{"options":[{"qty":"`)), decodeErr.Offset)
+}
@@ -0,0 +1,76 @@
+// Package example holds the structs the tests of jsoniter-gen generate codecs for.
+package example
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//go:generate go run .. -type Order,Item,Customer,Address,Audit,Empty,Versioned,Priced
+
+type Status string
+
+type Quantity int
+
+type Order struct {
+	ID        int64           `json:"id"`
+	Status    Status          `json:"status,omitempty"`
+	Customer  *Customer       `json:"customer"`
+	Items     []Item          `json:"items"`
+	Tags      []string        `json:"tags,omitempty"`
+	Matrix    [][]float64     `json:"matrix,omitempty"`
+	Codes     [3]uint8        `json:"codes"`
+	Notes     *string         `json:"notes,omitempty"`
+	Extra     interface{}     `json:"extra,omitempty"`
+	Raw       json.RawMessage `json:"raw,omitempty"`
+	Blob      []byte          `json:"blob,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Discount  float32         `json:"discount,omitempty"`
+	Paid      bool            `json:"paid,omitempty"`
+	Internal  string          `json:"-"`
+	secret    string
+	Audit
+}
+
+type Item struct {
+	SKU      string    `json:"sku"`
+	Quantity Quantity  `json:"qty"`
+	Price    float64   `json:"price"`
+	Options  []*Item   `json:"options,omitempty"`
+	Weight   *float64  `json:"weight"`
+	Related  *[]string `json:"related,omitempty"`
+}
+
+type Customer struct {
+	Name string
+	*Address
+	Email string `json:"email,omitempty"`
+}
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+	Name   string `json:"name"`
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by,omitempty"`
+	Version   int    `json:"version"`
+}
+
+type Empty struct{}
+
+// Versioned has a field of both Audit and the struct itself, the shallower one wins.
+type Versioned struct {
+	Audit
+	Version string `json:"version"`
+}
+
+// Priced has its own marshal methods, it is left to reflection.
+type Priced struct {
+	Cents int
+}
+
+func (priced Priced) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(priced.Cents) / 100)
+}
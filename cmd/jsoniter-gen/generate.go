@@ -0,0 +1,460 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type refKind int
+
+const (
+	basicRef refKind = iota
+	ptrRef
+	sliceRef
+	arrayRef
+	structRef
+	delegateRef
+)
+
+// typeRef tells how the generated code reads and writes a type,
+// delegateRef being left to ReadVal and WriteVal.
+type typeRef struct {
+	kind  refKind
+	text  string
+	basic string
+	named bool
+	elem  *typeRef
+}
+
+var delegate = &typeRef{kind: delegateRef}
+
+var basicTypes = map[string]string{
+	"string": "string", "bool": "bool",
+	"int": "int", "int8": "int8", "int16": "int16", "int32": "int32", "int64": "int64", "rune": "int32",
+	"uint": "uint", "uint8": "uint8", "uint16": "uint16", "uint32": "uint32", "uint64": "uint64", "byte": "uint8",
+	"float32": "float32", "float64": "float64",
+}
+
+func (gen *generator) resolve(typ ast.Expr) *typeRef {
+	switch typ := typ.(type) {
+	case *ast.ParenExpr:
+		return gen.resolve(typ.X)
+	case *ast.Ident:
+		spec := gen.specs[typ.Name]
+		if spec == nil {
+			if basic := basicTypes[typ.Name]; basic != "" {
+				return &typeRef{kind: basicRef, text: typ.Name, basic: basic}
+			}
+			return delegate
+		}
+		if spec.Assign.IsValid() || typeParamsOf(spec) != nil || gen.hasMarshalMethods(typ.Name) {
+			return delegate
+		}
+		if gen.structs[typ.Name] != nil {
+			return &typeRef{kind: structRef, text: typ.Name}
+		}
+		if underlying := gen.resolve(spec.Type); underlying.kind == basicRef {
+			return &typeRef{kind: basicRef, text: typ.Name, basic: underlying.basic, named: true}
+		}
+	case *ast.StarExpr:
+		if elem := gen.resolve(typ.X); elem.kind != delegateRef {
+			return &typeRef{kind: ptrRef, text: exprString(typ), elem: elem}
+		}
+	case *ast.ArrayType:
+		elem := gen.resolve(typ.Elt)
+		if elem.kind == delegateRef {
+			return delegate
+		}
+		if typ.Len == nil {
+			// []byte is base64 encoded
+			if elem.kind == basicRef && elem.basic == "uint8" {
+				return delegate
+			}
+			return &typeRef{kind: sliceRef, text: exprString(typ), elem: elem}
+		}
+		switch typ.Len.(type) {
+		case *ast.BasicLit, *ast.Ident:
+			return &typeRef{kind: arrayRef, text: exprString(typ), elem: elem}
+		}
+	}
+	return delegate
+}
+
+// addressOf takes the address of an addressable expression, (*x) giving back x.
+func addressOf(x string) string {
+	if strings.HasPrefix(x, "(*") && strings.HasSuffix(x, ")") {
+		return x[2 : len(x)-1]
+	}
+	return "&" + x
+}
+
+// unparen drops the parentheses of (*x), needed only to index or take the address of x.
+func unparen(x string) string {
+	if strings.HasPrefix(x, "(*") && strings.HasSuffix(x, ")") {
+		return x[1 : len(x)-1]
+	}
+	return x
+}
+
+func (gen *generator) generate() ([]byte, error) {
+	if err := gen.selectStructs(); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name := range gen.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	body := &bytes.Buffer{}
+	if len(names) != 0 {
+		gen.writeExtension(body, names)
+	}
+	for _, name := range names {
+		desc := gen.structs[name]
+		gen.writeCodec(body, desc)
+		gen.writeEncoder(body, desc)
+		gen.writeDecoder(body, desc)
+	}
+	if len(gen.typeVars) != 0 {
+		fmt.Fprintf(body, "\nvar (\n")
+		for i, text := range gen.typeVars {
+			fmt.Fprintf(body, "jsoniterType%d = reflect2.TypeOfPtr((*%s)(nil)).Elem()\n", i, text)
+		}
+		fmt.Fprintf(body, ")\n")
+	}
+	if len(names) != 0 {
+		gen.writeFallbacks(body)
+	}
+	if gen.usesIsEmpty {
+		fmt.Fprintf(body, "\nfunc jsoniterIsEmpty(stream *jsoniter.Stream, typ reflect2.Type, ptr unsafe.Pointer) bool {\n")
+		fmt.Fprintf(body, "return stream.Pool().(jsoniter.API).EncoderOf(typ).IsEmpty(ptr)\n}\n")
+	}
+	if gen.usesWrite {
+		fmt.Fprintf(body, "\nfunc jsoniterWrite(stream *jsoniter.Stream, typ reflect2.Type, ptr unsafe.Pointer) {\n")
+		fmt.Fprintf(body, "stream.Pool().(jsoniter.API).EncoderOf(typ).Encode(ptr, stream)\n}\n")
+	}
+	if gen.usesField {
+		fmt.Fprintf(body, "\nfunc jsoniterField(fields map[string]int, key string) int {\n")
+		fmt.Fprintf(body, "if field, found := fields[key]; found {\nreturn field\n}\n")
+		fmt.Fprintf(body, "if field, found := fields[strings.ToLower(key)]; found {\nreturn field\n}\n")
+		fmt.Fprintf(body, "return -1\n}\n")
+	}
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "%s\n\npackage %s\n\n", generatedHeader, gen.pkgName)
+	if len(names) != 0 {
+		fmt.Fprintf(src, "import (\n")
+		if gen.usesField {
+			fmt.Fprintf(src, "\"strings\"\n")
+		}
+		fmt.Fprintf(src, "\"unsafe\"\n\njsoniter \"github.com/json-iterator/go\"\n")
+		fmt.Fprintf(src, "\"github.com/modern-go/reflect2\"\n")
+		fmt.Fprintf(src, ")\n")
+	}
+	src.Write(body.Bytes())
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v", err)
+	}
+	return formatted, nil
+}
+
+// typeVar names the variable holding the reflect2.Type of the type written text.
+func (gen *generator) typeVar(text string) string {
+	for i, typeVar := range gen.typeVars {
+		if typeVar == text {
+			return "jsoniterType" + strconv.Itoa(i)
+		}
+	}
+	gen.typeVars = append(gen.typeVars, text)
+	return "jsoniterType" + strconv.Itoa(len(gen.typeVars)-1)
+}
+
+// writeExtension writes the extension putting the generated codecs in place of the reflection
+// based ones, which it keeps to fall back to.
+func (gen *generator) writeExtension(w *bytes.Buffer, names []string) {
+	fmt.Fprintf(w, "func init() {\njsoniter.RegisterExtension(&jsoniterExtension{})\n}\n")
+	fmt.Fprintf(w, "\ntype jsoniterExtension struct {\njsoniter.DummyExtension\n}\n")
+	for _, kind := range []string{"Encoder", "Decoder"} {
+		value := strings.ToLower(kind)
+		fmt.Fprintf(w, "\nfunc (extension *jsoniterExtension) Decorate%s(typ reflect2.Type, %s jsoniter.Val%s) jsoniter.Val%s {\n",
+			kind, value, kind, kind)
+		fmt.Fprintf(w, "switch typ.Type1() {\n")
+		for _, name := range names {
+			fmt.Fprintf(w, "case %s.Type1():\n", gen.typeVar(name))
+			fmt.Fprintf(w, "return &jsoniter%s%s{%s}\n", kind, name, value)
+		}
+		fmt.Fprintf(w, "}\nreturn %s\n}\n", value)
+	}
+}
+
+// writeFallbacks writes the checks of the options of the config the generated codecs do not follow.
+func (gen *generator) writeFallbacks(w *bytes.Buffer) {
+	tagKey := fmt.Sprintf("cfg.TagKey != %s", strconv.Quote(gen.tagKey))
+	if gen.tagKey == "json" {
+		tagKey = `(cfg.TagKey != "" && ` + tagKey + ")"
+	}
+	onlyTagged := "cfg.OnlyTaggedField"
+	if gen.onlyTagged {
+		onlyTagged = "!" + onlyTagged
+	}
+	fmt.Fprintf(w, "\nfunc jsoniterEncodesByReflection(stream *jsoniter.Stream) bool {\n")
+	fmt.Fprintf(w, "cfg := stream.Pool().(jsoniter.API).Config()\n")
+	fmt.Fprintf(w, "return cfg.Canonical || %s || %s\n}\n", tagKey, onlyTagged)
+	fmt.Fprintf(w, "\nfunc jsoniterDecodesByReflection(iter *jsoniter.Iterator) bool {\n")
+	fmt.Fprintf(w, "cfg := iter.Pool().(jsoniter.API).Config()\n")
+	fmt.Fprintf(w, "return cfg.CaseSensitive || cfg.DisallowUnknownFields || cfg.CollectAllErrors || %s || %s\n}\n",
+		tagKey, onlyTagged)
+}
+
+// writeCodec writes the encoder and decoder of the struct, falling back to the reflection based ones
+// when the config has options they do not follow.
+func (gen *generator) writeCodec(w *bytes.Buffer, desc *structDescription) {
+	encoder, decoder := "jsoniterEncoder"+desc.name, "jsoniterDecoder"+desc.name
+	fmt.Fprintf(w, "\ntype %s struct {\nfallback jsoniter.ValEncoder\n}\n\n", encoder)
+	fmt.Fprintf(w, "func (encoder *%s) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {\n", encoder)
+	fmt.Fprintf(w, "if jsoniterEncodesByReflection(stream) {\nencoder.fallback.Encode(ptr, stream)\nreturn\n}\n")
+	fmt.Fprintf(w, "jsoniterEncode%s((*%s)(ptr), stream)\n}\n\n", desc.name, desc.name)
+	fmt.Fprintf(w, "func (encoder *%s) IsEmpty(ptr unsafe.Pointer) bool {\n", encoder)
+	fmt.Fprintf(w, "return encoder.fallback.IsEmpty(ptr)\n}\n")
+	fmt.Fprintf(w, "\ntype %s struct {\nfallback jsoniter.ValDecoder\n}\n\n", decoder)
+	fmt.Fprintf(w, "func (decoder *%s) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {\n", decoder)
+	fmt.Fprintf(w, "if jsoniterDecodesByReflection(iter) {\ndecoder.fallback.Decode(ptr, iter)\nreturn\n}\n")
+	fmt.Fprintf(w, "jsoniterDecode%s((*%s)(ptr), iter)\n}\n", desc.name, desc.name)
+}
+
+// writeEncoder writes the fields as structEncoder does.
+func (gen *generator) writeEncoder(w *bytes.Buffer, desc *structDescription) {
+	fmt.Fprintf(w, "\nfunc jsoniterEncode%s(v *%s, stream *jsoniter.Stream) {\n", desc.name, desc.name)
+	hasNames := false
+	for _, binding := range desc.bindings {
+		hasNames = hasNames || len(binding.names) != 0
+	}
+	if !hasNames {
+		fmt.Fprintf(w, "stream.WriteEmptyObject()\n}\n")
+		return
+	}
+	fmt.Fprintf(w, "stream.WriteObjectStart()\n")
+	// written is known until a field may be omitted, then it is kept in the variable more
+	known, written, declared := true, false, false
+	for i, binding := range desc.encoders {
+		x := "v." + fieldPath(binding)
+		ref := gen.resolve(binding.typ)
+		conditions := embeddedPtrConditions(binding)
+		if binding.omitempty {
+			if nonEmpty, needsHelper := nonEmptyCondition(x, ref); nonEmpty != "" {
+				conditions = append(conditions, nonEmpty)
+				gen.usesIsEmpty = gen.usesIsEmpty || needsHelper
+			}
+		}
+		hasNext := i < len(desc.encoders)-1
+		if len(conditions) != 0 && known && !written && hasNext && !declared {
+			fmt.Fprintf(w, "more := false\n")
+			declared = true
+		}
+		if len(conditions) != 0 {
+			fmt.Fprintf(w, "if %s {\n", strings.Join(conditions, " && "))
+		}
+		switch {
+		case known && written:
+			fmt.Fprintf(w, "stream.WriteMore()\n")
+		case !known:
+			fmt.Fprintf(w, "if more {\nstream.WriteMore()\n}\n")
+		}
+		fmt.Fprintf(w, "stream.WriteObjectField(%s)\n", strconv.Quote(binding.names[0]))
+		gen.encodeValue(w, x, ref, 0)
+		if len(conditions) != 0 {
+			if hasNext && !(known && written) {
+				fmt.Fprintf(w, "more = true\n")
+			}
+			fmt.Fprintf(w, "}\n")
+			known = known && written
+		} else {
+			known, written = true, true
+		}
+	}
+	fmt.Fprintf(w, "stream.WriteObjectEnd()\n}\n")
+}
+
+func fieldPath(binding *binding) string {
+	steps := []string{}
+	for _, step := range binding.path {
+		steps = append(steps, step.name)
+	}
+	return strings.Join(steps, ".")
+}
+
+// embeddedPtrConditions checks the embedded pointers holding the field are not nil.
+func embeddedPtrConditions(binding *binding) []string {
+	conditions := []string{}
+	for i, step := range binding.path[:len(binding.path)-1] {
+		if step.ptr {
+			steps := []string{}
+			for _, step := range binding.path[:i+1] {
+				steps = append(steps, step.name)
+			}
+			conditions = append(conditions, "v."+strings.Join(steps, ".")+" != nil")
+		}
+	}
+	return conditions
+}
+
+// nonEmptyCondition negates the IsEmpty of the encoder of ref, empty if it is never empty.
+func nonEmptyCondition(x string, ref *typeRef) (condition string, needsHelper bool) {
+	bare := unparen(x)
+	switch ref.kind {
+	case basicRef:
+		switch ref.basic {
+		case "string":
+			return bare + ` != ""`, false
+		case "bool":
+			return bare, false
+		}
+		return bare + " != 0", false
+	case ptrRef:
+		return bare + " != nil", false
+	case sliceRef, arrayRef:
+		return "len(" + bare + ") != 0", false
+	case delegateRef:
+		ptr := addressOf(x)
+		return fmt.Sprintf("!jsoniterIsEmpty(stream, reflect2.TypeOfPtr(%s).Elem(), unsafe.Pointer(%s))", ptr, ptr), true
+	}
+	return "", false
+}
+
+// basicWriters are the writers of the basic types the encoders of which do not depend on
+// the config, strings and floats being written with the encoders of the API.
+var basicWriters = map[string]string{
+	"bool": "WriteBool",
+	"int":  "WriteInt", "int8": "WriteInt8", "int16": "WriteInt16", "int32": "WriteInt32", "int64": "WriteInt64",
+	"uint": "WriteUint", "uint8": "WriteUint8", "uint16": "WriteUint16", "uint32": "WriteUint32", "uint64": "WriteUint64",
+}
+
+var basicReaders = map[string]string{
+	"string": "ReadString", "bool": "ReadBool",
+	"int": "ReadInt", "int8": "ReadInt8", "int16": "ReadInt16", "int32": "ReadInt32", "int64": "ReadInt64",
+	"uint": "ReadUint", "uint8": "ReadUint8", "uint16": "ReadUint16", "uint32": "ReadUint32", "uint64": "ReadUint64",
+	"float32": "ReadFloat32", "float64": "ReadFloat64",
+}
+
+// encodeValue writes x as the encoder of its type does, depth naming the loop variables.
+func (gen *generator) encodeValue(w *bytes.Buffer, x string, ref *typeRef, depth int) {
+	bare := unparen(x)
+	switch ref.kind {
+	case basicRef:
+		writer := basicWriters[ref.basic]
+		if writer == "" {
+			gen.usesWrite = true
+			fmt.Fprintf(w, "jsoniterWrite(stream, %s, unsafe.Pointer(%s))\n", gen.typeVar(ref.text), addressOf(x))
+			return
+		}
+		if ref.named {
+			bare = ref.basic + "(" + bare + ")"
+		}
+		fmt.Fprintf(w, "stream.%s(%s)\n", writer, bare)
+	case ptrRef:
+		fmt.Fprintf(w, "if %s == nil {\nstream.WriteNil()\n} else {\n", bare)
+		gen.encodeValue(w, "(*"+bare+")", ref.elem, depth)
+		fmt.Fprintf(w, "}\n")
+	case sliceRef, arrayRef:
+		if ref.kind == sliceRef {
+			fmt.Fprintf(w, "if %s == nil {\nstream.WriteNil()\n} else ", bare)
+		}
+		i := "i" + strconv.Itoa(depth)
+		fmt.Fprintf(w, "if len(%s) == 0 {\nstream.WriteEmptyArray()\n} else {\n", bare)
+		fmt.Fprintf(w, "stream.WriteArrayStart()\nfor %s := range %s {\n", i, bare)
+		fmt.Fprintf(w, "if %s > 0 {\nstream.WriteMore()\n}\n", i)
+		gen.encodeValue(w, x+"["+i+"]", ref.elem, depth+1)
+		fmt.Fprintf(w, "}\nstream.WriteArrayEnd()\n}\n")
+	case structRef:
+		fmt.Fprintf(w, "jsoniterEncode%s(%s, stream)\n", ref.text, addressOf(x))
+	default:
+		fmt.Fprintf(w, "stream.WriteVal(%s)\n", addressOf(x))
+	}
+}
+
+func (gen *generator) writeDecoder(w *bytes.Buffer, desc *structDescription) {
+	fmt.Fprintf(w, "\nfunc jsoniterDecode%s(v *%s, iter *jsoniter.Iterator) {\n", desc.name, desc.name)
+	fmt.Fprintf(w, "iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {\n")
+	if len(desc.decoders) == 0 {
+		fmt.Fprintf(w, "iter.Skip()\nreturn true\n})\n}\n")
+		return
+	}
+	gen.usesField = true
+	fmt.Fprintf(w, "switch jsoniterField(jsoniterFields%s, key) {\n", desc.name)
+	for i, binding := range desc.decoders {
+		fmt.Fprintf(w, "case %d:\n", i)
+		for j, step := range binding.path[:len(binding.path)-1] {
+			if step.ptr {
+				steps := []string{}
+				for _, step := range binding.path[:j+1] {
+					steps = append(steps, step.name)
+				}
+				ptr := "v." + strings.Join(steps, ".")
+				fmt.Fprintf(w, "if %s == nil {\n%s = new(%s)\n}\n", ptr, ptr, step.elem)
+			}
+		}
+		gen.decodeValue(w, "v."+fieldPath(binding), gen.resolve(binding.typ), 0)
+	}
+	fmt.Fprintf(w, "default:\niter.Skip()\n}\n")
+	fmt.Fprintf(w, "return true\n})\n}\n")
+	// the keys of the fields, then the lower case ones not taken, as the decoders of struct match them
+	fmt.Fprintf(w, "\nvar jsoniterFields%s = map[string]int{\n", desc.name)
+	keys := map[string]bool{}
+	for i, binding := range desc.decoders {
+		keys[binding.names[0]] = true
+		fmt.Fprintf(w, "%s: %d,\n", strconv.Quote(binding.names[0]), i)
+	}
+	for i, binding := range desc.decoders {
+		if key := strings.ToLower(binding.names[0]); !keys[key] {
+			keys[key] = true
+			fmt.Fprintf(w, "%s: %d,\n", strconv.Quote(key), i)
+		}
+	}
+	fmt.Fprintf(w, "}\n")
+}
+
+// decodeValue reads x as the decoder of its type does, depth naming the closure variables.
+func (gen *generator) decodeValue(w *bytes.Buffer, x string, ref *typeRef, depth int) {
+	bare := unparen(x)
+	switch ref.kind {
+	case basicRef:
+		read := "iter." + basicReaders[ref.basic] + "()"
+		if ref.named {
+			read = ref.text + "(" + read + ")"
+		}
+		if ref.basic == "string" {
+			fmt.Fprintf(w, "%s = %s\n", bare, read)
+		} else {
+			fmt.Fprintf(w, "if !iter.ReadNil() {\n%s = %s\n}\n", bare, read)
+		}
+	case ptrRef:
+		fmt.Fprintf(w, "if iter.ReadNil() {\n%s = nil\n} else {\n", bare)
+		fmt.Fprintf(w, "if %s == nil {\n%s = new(%s)\n}\n", bare, bare, ref.elem.text)
+		gen.decodeValue(w, "(*"+bare+")", ref.elem, depth)
+		fmt.Fprintf(w, "}\n")
+	case sliceRef:
+		s, i := "s"+strconv.Itoa(depth), "i"+strconv.Itoa(depth)
+		fmt.Fprintf(w, "if iter.ReadNil() {\n%s = nil\n} else {\n%s := %s[:0]\n", bare, s, x)
+		fmt.Fprintf(w, "%s := 0\niter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {\n", i)
+		fmt.Fprintf(w, "if %s < cap(%s) {\n%s = %s[:%s+1]\n} else {\n", i, s, s, s, i)
+		fmt.Fprintf(w, "%s = append(%s, *new(%s))\n}\n", s, s, ref.elem.text)
+		gen.decodeValue(w, s+"["+i+"]", ref.elem, depth+1)
+		fmt.Fprintf(w, "%s++\nreturn true\n})\n", i)
+		fmt.Fprintf(w, "if %s == nil {\n%s = %s{}\n}\n%s = %s\n}\n", s, s, ref.text, bare, s)
+	case arrayRef:
+		i := "i" + strconv.Itoa(depth)
+		fmt.Fprintf(w, "%s := 0\niter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {\n", i)
+		fmt.Fprintf(w, "if %s >= len(%s) {\niter.Skip()\nreturn true\n}\n", i, bare)
+		gen.decodeValue(w, x+"["+i+"]", ref.elem, depth+1)
+		fmt.Fprintf(w, "%s++\nreturn true\n})\n", i)
+	case structRef:
+		fmt.Fprintf(w, "jsoniterDecode%s(%s, iter)\n", ref.text, addressOf(x))
+	default:
+		fmt.Fprintf(w, "iter.ReadVal(%s)\n", addressOf(x))
+	}
+}
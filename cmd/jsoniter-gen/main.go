@@ -0,0 +1,87 @@
+// Command jsoniter-gen generates encoders and decoders for struct types, registered with
+// jsoniter.RegisterExtension so that they replace the reflection based ones, which they fall
+// back to when the config has options they do not follow.
+//
+// Usage:
+//
+//	jsoniter-gen [-type T1,T2] [-output file] [-tag json] [-only-tagged] [dir]
+//
+// It reads the package in dir, the current directory by default, and writes the codecs of
+// the named struct types, all the exported ones by default, to <package>_jsoniter.go.
+// With go generate, add to a file of the package:
+//
+//	//go:generate jsoniter-gen -type Order,Item
+//
+// Fields are named and skipped with the same tag rules as the reflection based codecs,
+// embedded structs included. Fields of types from other packages, maps and interfaces are
+// encoded with WriteVal and decoded with ReadVal. Types with a MarshalJSON, UnmarshalJSON,
+// MarshalText or UnmarshalText method, with a field tagged with the string option, or
+// embedding a type from another package are left to the reflection based codecs.
+//
+// The generated codecs only use the public API of jsoniter, and so do less than the
+// reflection based ones, as the help says.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const limitations = `The generated codecs write strings and floats with the encoders of the API, following
+Config.EscapeHTML and MarshalFloatWith6Digits, and read with the Iterator, following the limits.
+They leave the values to the reflection based codecs when the config sets Canonical,
+CaseSensitive, DisallowUnknownFields or CollectAllErrors, or a TagKey or OnlyTaggedField
+other than the -tag and -only-tagged they were generated with. Unlike the reflection based codecs:
+  - they ignore the other extensions, which only describe the structs to the reflection based codecs;
+  - their decode errors have the Offset, Line and Column of the error, but no Path, Struct or Field.
+`
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated struct types, all the exported ones when empty")
+	output := flag.String("output", "", "output file, <package>_jsoniter.go in dir when empty")
+	tagKey := flag.String("tag", "json", "struct tag key, as Config.TagKey")
+	onlyTagged := flag.Bool("only-tagged", false, "skip the fields without tag, as Config.OnlyTaggedField")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: jsoniter-gen [flags] [dir]\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n%s", limitations)
+	}
+	flag.Parse()
+	dir := "."
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	gen := &generator{tagKey: *tagKey, onlyTagged: *onlyTagged}
+	if *typeNames != "" {
+		gen.typeNames = strings.Split(*typeNames, ",")
+	}
+	if err := run(gen, dir, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "jsoniter-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(gen *generator, dir string, output string) error {
+	if err := gen.parseDir(dir, output); err != nil {
+		return err
+	}
+	if output == "" {
+		output = filepath.Join(dir, gen.pkgName+"_jsoniter.go")
+	}
+	src, err := gen.generate()
+	for _, warning := range gen.warnings {
+		fmt.Fprintf(os.Stderr, "jsoniter-gen: %s\n", warning)
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(output, src, 0644)
+}
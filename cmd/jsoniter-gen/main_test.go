@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_generated_example_is_up_to_date(t *testing.T) {
+	should := require.New(t)
+	gen := &generator{tagKey: "json", typeNames: []string{
+		"Order", "Item", "Customer", "Address", "Audit", "Empty", "Versioned", "Priced"}}
+	should.NoError(gen.parseDir("example", ""))
+	src, err := gen.generate()
+	should.NoError(err)
+	committed, err := ioutil.ReadFile(filepath.Join("example", "example_jsoniter.go"))
+	should.NoError(err)
+	should.Equal(string(committed), string(src), "run go generate in the example directory")
+	should.Equal([]string{"Priced: left to reflection, it has its own marshal methods"}, gen.warnings)
+}
+
+func Test_unsupported_structs_are_left_to_reflection(t *testing.T) {
+	should := require.New(t)
+	dir, err := ioutil.TempDir("", "jsoniter-gen")
+	should.NoError(err)
+	defer os.RemoveAll(dir)
+	should.NoError(ioutil.WriteFile(filepath.Join(dir, "model.go"), []byte(`package model
+
+import "time"
+
+type Quoted struct {
+	ID int64 `+"`json:\"id,string\"`"+`
+}
+
+type Stamped struct {
+	time.Time
+}
+
+type Node struct {
+	Name string
+	Next *Node
+}
+`), 0644))
+	gen := &generator{tagKey: "json"}
+	should.NoError(run(gen, dir, ""))
+	should.Equal([]string{
+		"Quoted: left to reflection, the string option is not supported",
+		"Stamped: left to reflection, time.Time is embedded from another package",
+	}, gen.warnings)
+	src, err := ioutil.ReadFile(filepath.Join(dir, "model_jsoniter.go"))
+	should.NoError(err)
+	should.Contains(string(src), "return &jsoniterDecoderNode{decoder}")
+	should.NotContains(string(src), "Quoted")
+	should.Error(run(&generator{tagKey: "json", typeNames: []string{"Missing"}}, dir, ""))
+}
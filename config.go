@@ -25,7 +25,77 @@ type Config struct {
 	ValidateJsonRawMessage        bool
 	ObjectFieldMustBeSimpleString bool
 	CaseSensitive                 bool
-}
+	// CollectAllErrors goes on decoding after a value of the wrong type, or a number a Go number
+	// can not hold, skipping it, and reports all of them at once as DecodeErrors. The entry of
+	// a map whose value is skipped is left out.
+	CollectAllErrors bool
+	// MaxDepth limits the nesting of arrays and objects, to 10000 when not set.
+	// The other limits are only enforced when set: MaxStringLength on the bytes of a string
+	// or object key once unescaped, MaxArrayElements on the elements of an array, MaxObjectKeys
+	// on the keys of an object, MaxInputBytes on all the input read by an Iterator or Decoder.
+	// Going over a limit reports a *LimitError. Skip built with the jsoniter_sloppy tag reads the
+	// arrays and objects it skips, instead of scanning them, when MaxStringLength,
+	// MaxArrayElements or MaxObjectKeys is set.
+	MaxDepth         int
+	MaxStringLength  int
+	MaxArrayElements int
+	MaxObjectKeys    int
+	MaxInputBytes    int
+	// UseStructuralIndex indexes the structure of the large arrays and objects of Any when they are
+	// read a second time, so that the lookups which follow, such as Get, Keys and Size, jump over
+	// the values they do not read. An input read once, as by Skip or API.Get, is scanned instead,
+	// building the index costing more than that. It is not used with MaxStringLength,
+	// MaxArrayElements or MaxObjectKeys.
+	UseStructuralIndex bool
+	// AllowJSON5 reads JSON5 as well as JSON, as all the options below, see json5.org.
+	AllowJSON5 bool
+	// AllowComments accepts // and /* */ comments wherever whitespace is.
+	AllowComments bool
+	// AllowTrailingCommas accepts a comma after the last element of an array or field of an object.
+	AllowTrailingCommas bool
+	// AllowSingleQuotes accepts strings in single quotes,
+	// and the escapes of JSON5: \', \v, \0, \xFF and escaped line breaks.
+	AllowSingleQuotes bool
+	// AllowUnquotedKeys accepts object keys which are identifiers, without quotes.
+	AllowUnquotedKeys bool
+	// AllowJSON5Numbers accepts hexadecimal numbers, Infinity, NaN, a leading +
+	// and a leading or trailing decimal point.
+	AllowJSON5Numbers bool
+	// Strict reads only what RFC 8259 allows, whatever the build tags: it rejects invalid UTF-8
+	// and lone surrogates in strings, duplicate keys in objects, numbers such as 01, 1. or .1,
+	// and Valid rejects bytes after the value. It turns off the Allow options above,
+	// and sets DuplicateKeys to DuplicateKeysError.
+	Strict bool
+	// DuplicateKeys tells which value of a key repeated in an object is decoded: by the struct
+	// and map decoders, ReadObjectCB and ReadMapCB, and so Any, Get and Document. With
+	// DuplicateKeysError, Valid rejects the repeated keys as well.
+	DuplicateKeys DuplicateKeyPolicy
+	// Canonical writes JSON as RFC 8785 (JCS) does, the same bytes for the same values:
+	// the keys of maps and the fields of structs are sorted by their UTF-16 code units, floats
+	// are written as ECMAScript does, strings are escaped no more than JSON requires and the
+	// raw JSON of RawMessage, Number, Marshaler and Any is read to be written canonically too.
+	// It turns off EscapeHTML, MarshalFloatWith6Digits and IndentionStep. As RFC 8785 reads all
+	// numbers as float64, a float32 is written as the float64 it converts to, and the integers
+	// beyond 2^53 as the nearest float64, so that canonical output is canonical once read again.
+	Canonical bool
+	// StandardLibraryErrors lets errors.As find the *json.SyntaxError and *json.UnmarshalTypeError
+	// of encoding/json in the errors reported, for code written against it.
+	// ConfigCompatibleWithStandardLibrary sets it.
+	StandardLibraryErrors bool
+}
+
+// DuplicateKeyPolicy tells what is done with a key read a second time in the same object.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysLastWins decodes the values of a repeated key in turn, the last one winning,
+	// as encoding/json does
+	DuplicateKeysLastWins DuplicateKeyPolicy = iota
+	// DuplicateKeysFirstWins skips the values of a key after the first one
+	DuplicateKeysFirstWins
+	// DuplicateKeysError reports a repeated key
+	DuplicateKeysError
+)
 
 // API the public interface of this package.
 // Primary Marshal and Unmarshal.
@@ -37,13 +107,22 @@ type API interface {
 	MarshalIndent(v interface{}, prefix, indent string) ([]byte, error)
 	UnmarshalFromString(str string, v interface{}) error
 	Unmarshal(data []byte, v interface{}) error
+	MergePatch(target interface{}, patch []byte) error
 	Get(data []byte, path ...interface{}) Any
+	Query(data []byte, expr string) ([]Any, error)
+	ParseDocument(data []byte) (*Document, error)
 	NewEncoder(writer io.Writer) *Encoder
 	NewDecoder(reader io.Reader) *Decoder
+	NewLinesEncoder(writer io.Writer) *LinesEncoder
+	NewLinesDecoder(reader io.Reader) *LinesDecoder
+	DecodeArrayParallel(reader io.Reader, workers int, newElem func() interface{}, sink func(i int, v interface{})) error
+	DecodeArrayParallelOrdered(reader io.Reader, workers int, newElem func() interface{}, sink func(i int, v interface{})) error
 	Valid(data []byte) bool
 	RegisterExtension(extension Extension)
 	DecoderOf(typ reflect2.Type) ValDecoder
 	EncoderOf(typ reflect2.Type) ValEncoder
+	// Config returns the Config the API was frozen from
+	Config() Config
 }
 
 // ConfigDefault the default API
@@ -56,6 +135,7 @@ var ConfigCompatibleWithStandardLibrary = Config{
 	EscapeHTML:             true,
 	SortMapKeys:            true,
 	ValidateJsonRawMessage: true,
+	StandardLibraryErrors:  true,
 }.Froze()
 
 // ConfigFastest marshals float with only 6 digits precision
@@ -65,6 +145,11 @@ var ConfigFastest = Config{
 	ObjectFieldMustBeSimpleString: true, // do not unescape object field
 }.Froze()
 
+// ConfigCanonical writes the canonical JSON of RFC 8785, to be signed or hashed
+var ConfigCanonical = Config{
+	Canonical: true,
+}.Froze()
+
 type frozenConfig struct {
 	configBeforeFrozen            Config
 	sortMapKeys                   bool
@@ -72,8 +157,29 @@ type frozenConfig struct {
 	objectFieldMustBeSimpleString bool
 	onlyTaggedField               bool
 	disallowUnknownFields         bool
+	collectAllErrors              bool
+	maxDepth                      int
+	maxStringLength               int
+	maxArrayElements              int
+	maxObjectKeys                 int
+	maxInputBytes                 int
+	useStructuralIndex            bool
+	allowComments                 bool
+	allowTrailingCommas           bool
+	allowSingleQuotes             bool
+	allowUnquotedKeys             bool
+	allowJSON5Numbers             bool
+	relaxed                       bool
+	blanksInput                   bool
+	skipsByReading                bool
+	strict                        bool
+	checkedNumbers                bool
+	duplicateKeys                 DuplicateKeyPolicy
+	canonical                     bool
+	compatible                    bool
 	decoderCache                  *concurrent.Map
 	encoderCache                  *concurrent.Map
+	mergePatcherCache             *concurrent.Map
 	encoderExtension              Extension
 	decoderExtension              Extension
 	extraExtensions               []Extension
@@ -85,6 +191,7 @@ type frozenConfig struct {
 func (cfg *frozenConfig) initCache() {
 	cfg.decoderCache = concurrent.NewMap()
 	cfg.encoderCache = concurrent.NewMap()
+	cfg.mergePatcherCache = concurrent.NewMap()
 }
 
 func (cfg *frozenConfig) addDecoderToCache(cacheKey uintptr, decoder ValDecoder) {
@@ -127,6 +234,12 @@ func addFrozenConfigToCache(cfg Config, frozenConfig *frozenConfig) {
 
 // Froze forge API from config
 func (cfg Config) Froze() API {
+	if cfg.Canonical {
+		cfg.EscapeHTML = false
+		cfg.MarshalFloatWith6Digits = false
+		cfg.IndentionStep = 0
+		cfg.SortMapKeys = true
+	}
 	api := &frozenConfig{
 		sortMapKeys:                   cfg.SortMapKeys,
 		indentionStep:                 cfg.IndentionStep,
@@ -134,7 +247,55 @@ func (cfg Config) Froze() API {
 		onlyTaggedField:               cfg.OnlyTaggedField,
 		disallowUnknownFields:         cfg.DisallowUnknownFields,
 		caseSensitive:                 cfg.CaseSensitive,
+		collectAllErrors:              cfg.CollectAllErrors,
+		maxDepth:                      cfg.MaxDepth,
+		maxStringLength:               cfg.MaxStringLength,
+		maxArrayElements:              cfg.MaxArrayElements,
+		maxObjectKeys:                 cfg.MaxObjectKeys,
+		maxInputBytes:                 cfg.MaxInputBytes,
+		duplicateKeys:                 cfg.DuplicateKeys,
+		compatible:                    cfg.StandardLibraryErrors,
+		canonical:                     cfg.Canonical,
+		// skipping by the index would not check the limits
+		useStructuralIndex: cfg.UseStructuralIndex &&
+			cfg.MaxStringLength <= 0 && cfg.MaxArrayElements <= 0 && cfg.MaxObjectKeys <= 0,
+	}
+	if api.maxDepth <= 0 {
+		api.maxDepth = defaultMaxDepth
+	}
+	if cfg.Strict {
+		api.strict = true
+		api.duplicateKeys = DuplicateKeysError
+		// the index does not check the UTF-8 of the strings
+		api.useStructuralIndex = false
+	} else {
+		api.allowComments = cfg.AllowJSON5 || cfg.AllowComments
+		api.allowTrailingCommas = cfg.AllowJSON5 || cfg.AllowTrailingCommas
+		api.allowSingleQuotes = cfg.AllowJSON5 || cfg.AllowSingleQuotes
+		api.allowUnquotedKeys = cfg.AllowJSON5 || cfg.AllowUnquotedKeys
+		api.allowJSON5Numbers = cfg.AllowJSON5 || cfg.AllowJSON5Numbers
 	}
+	api.relaxed = api.allowComments || api.allowTrailingCommas || api.allowSingleQuotes ||
+		api.allowUnquotedKeys || api.allowJSON5Numbers
+	if api.relaxed {
+		// the index only finds JSON
+		api.useStructuralIndex = false
+	}
+	// the readers of the Iterator are picked here once, see iter_json5.go
+	api.blanksInput = api.allowComments || api.allowTrailingCommas
+	if api.allowSingleQuotes || api.allowUnquotedKeys {
+		// the keys are read by ReadString, which reads them in single quotes or unquoted
+		api.objectFieldMustBeSimpleString = false
+	}
+	if api.duplicateKeys == DuplicateKeysError {
+		// skipping by the index would not check the keys
+		api.useStructuralIndex = false
+	}
+	// the numbers are read as strings to be checked
+	api.checkedNumbers = api.allowJSON5Numbers || api.strict
+	// Skip reads the arrays and objects it skips value by value, instead of jumping over them
+	api.skipsByReading = api.duplicateKeys == DuplicateKeysError || api.relaxed ||
+		api.maxStringLength > 0 || api.maxArrayElements > 0 || api.maxObjectKeys > 0
 	api.streamPool = &sync.Pool{
 		New: func() interface{} {
 			return NewStream(api, nil, 512)
@@ -219,6 +380,10 @@ func (cfg *frozenConfig) getTagKey() string {
 	return tagKey
 }
 
+func (cfg *frozenConfig) Config() Config {
+	return cfg.configBeforeFrozen
+}
+
 func (cfg *frozenConfig) RegisterExtension(extension Extension) {
 	cfg.extraExtensions = append(cfg.extraExtensions, extension)
 	copied := cfg.configBeforeFrozen
@@ -328,12 +493,12 @@ func (cfg *frozenConfig) UnmarshalFromString(str string, v interface{}) error {
 	c := iter.nextToken()
 	if c == 0 {
 		if iter.Error == io.EOF {
-			return nil
+			return iter.withCollectedErrors(nil)
 		}
-		return iter.Error
+		return iter.withCollectedErrors(iter.Error)
 	}
 	iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
-	return iter.Error
+	return iter.withCollectedErrors(iter.Error)
 }
 
 func (cfg *frozenConfig) Get(data []byte, path ...interface{}) Any {
@@ -349,12 +514,12 @@ func (cfg *frozenConfig) Unmarshal(data []byte, v interface{}) error {
 	c := iter.nextToken()
 	if c == 0 {
 		if iter.Error == io.EOF {
-			return nil
+			return iter.withCollectedErrors(nil)
 		}
-		return iter.Error
+		return iter.withCollectedErrors(iter.Error)
 	}
 	iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
-	return iter.Error
+	return iter.withCollectedErrors(iter.Error)
 }
 
 func (cfg *frozenConfig) NewEncoder(writer io.Writer) *Encoder {
@@ -371,5 +536,9 @@ func (cfg *frozenConfig) Valid(data []byte) bool {
 	iter := cfg.BorrowIterator(data)
 	defer cfg.ReturnIterator(iter)
 	iter.Skip()
-	return iter.Error == nil
+	if !cfg.strict {
+		return iter.Error == nil
+	}
+	// the value must be the whole input
+	return (iter.Error == nil || iter.Error == io.EOF) && iter.nextToken() == 0 && iter.Error == io.EOF
 }
@@ -0,0 +1,272 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ParseDocument parses data into a Document, see API.ParseDocument.
+func ParseDocument(data []byte) (*Document, error) {
+	return ConfigDefault.ParseDocument(data)
+}
+
+// Document is a JSON value parsed once into a tape: one entry per value, in the order of the input,
+// locating the value in data. The elements of an array are indexed in constant time, the fields of
+// an object looked up in a map built on the first lookup. The values are decoded on demand, from
+// where they are in data, which must not be modified while the Document is used.
+type Document struct {
+	cfg  *frozenConfig
+	data []byte
+	tape []tapeEntry
+	// the tape indexes of the elements and field values of the arrays and objects, and the keys of the fields
+	children []int
+	keys     []string
+	mutex    sync.Mutex
+	// the field values of the larger objects by key, built on first lookup
+	fields map[int]map[string]int
+}
+
+// tapeEntry is a value at data[start:end], whose elements or fields are children[first:first+count].
+type tapeEntry struct {
+	kind  ValueType
+	start int
+	end   int
+	first int
+	count int
+}
+
+// objects of at most documentScannedFields fields are searched without a map
+const documentScannedFields = 8
+
+// ParseDocument parses the JSON value of data, which must be the whole input as for Unmarshal.
+func (cfg *frozenConfig) ParseDocument(data []byte) (*Document, error) {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	builder := &documentBuilder{doc: &Document{cfg: cfg, data: data}}
+	builder.readValue(iter)
+	if iter.Error == nil && iter.nextToken() != 0 {
+		iter.ReportError("ParseDocument", "there are bytes left after the value")
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	return builder.doc, nil
+}
+
+// documentBuilder fills the tape of doc. The children of the arrays and objects being read
+// are stacked in children and keys, then moved to doc once all read.
+type documentBuilder struct {
+	doc      *Document
+	children []int
+	keys     []string
+}
+
+func (builder *documentBuilder) readValue(iter *Iterator) int {
+	doc := builder.doc
+	valueType := iter.WhatIsNext()
+	index := len(doc.tape)
+	doc.tape = append(doc.tape, tapeEntry{kind: valueType, start: iter.head})
+	mark := len(builder.children)
+	switch valueType {
+	case ArrayValue:
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			child := builder.readValue(iter)
+			builder.children = append(builder.children, child)
+			builder.keys = append(builder.keys, "")
+			return true
+		})
+	case ObjectValue:
+		iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+			child := builder.readValue(iter)
+			builder.children = append(builder.children, child)
+			builder.keys = append(builder.keys, field)
+			return true
+		})
+	case InvalidValue:
+		iter.ReportError("ParseDocument", "expect a value")
+		return index
+	default:
+		iter.Skip()
+	}
+	entry := &doc.tape[index]
+	entry.end = iter.head
+	entry.first = len(doc.children)
+	entry.count = len(builder.children) - mark
+	doc.children = append(doc.children, builder.children[mark:]...)
+	doc.keys = append(doc.keys, builder.keys[mark:]...)
+	builder.children = builder.children[:mark]
+	builder.keys = builder.keys[:mark]
+	return index
+}
+
+// Root returns the value of the whole document.
+func (doc *Document) Root() DocumentValue {
+	return DocumentValue{doc: doc}
+}
+
+// Path returns the value found by following path from the root, see DocumentValue.Path.
+func (doc *Document) Path(path ...interface{}) DocumentValue {
+	return doc.Root().Path(path...)
+}
+
+// fieldValue returns the tape index of the value of the field key of the object at index, -1 if there is none.
+// The last field wins when the key is repeated, as with Get, the others are not on the tape
+// with DuplicateKeysFirstWins.
+func (doc *Document) fieldValue(index int, key string) int {
+	entry := doc.tape[index]
+	if entry.count <= documentScannedFields {
+		for i := entry.first + entry.count - 1; i >= entry.first; i-- {
+			if doc.keys[i] == key {
+				return doc.children[i]
+			}
+		}
+		return -1
+	}
+	doc.mutex.Lock()
+	defer doc.mutex.Unlock()
+	fields := doc.fields[index]
+	if fields == nil {
+		fields = make(map[string]int, entry.count)
+		for i := entry.first; i < entry.first+entry.count; i++ {
+			fields[doc.keys[i]] = doc.children[i]
+		}
+		if doc.fields == nil {
+			doc.fields = map[int]map[string]int{}
+		}
+		doc.fields[index] = fields
+	}
+	if child, found := fields[key]; found {
+		return child
+	}
+	return -1
+}
+
+// DocumentValue is a value of a Document, or the error of a path which does not lead to a value.
+type DocumentValue struct {
+	doc   *Document
+	index int
+	err   error
+}
+
+func invalidDocumentValue(doc *Document, path []interface{}) DocumentValue {
+	return DocumentValue{doc: doc, index: -1, err: fmt.Errorf("%v not found", path)}
+}
+
+// Err returns the error of the path leading to the value, nil when it exists.
+func (value DocumentValue) Err() error {
+	return value.err
+}
+
+// ValueType returns the type of the value, InvalidValue when it does not exist.
+func (value DocumentValue) ValueType() ValueType {
+	if value.err != nil {
+		return InvalidValue
+	}
+	return value.doc.tape[value.index].kind
+}
+
+// Len returns the number of elements of an array or fields of an object, 0 for the other values.
+func (value DocumentValue) Len() int {
+	if value.err != nil {
+		return 0
+	}
+	return value.doc.tape[value.index].count
+}
+
+// Index returns the element i of an array.
+func (value DocumentValue) Index(i int) DocumentValue {
+	if value.err != nil {
+		return value
+	}
+	entry := value.doc.tape[value.index]
+	if entry.kind != ArrayValue || i < 0 || i >= entry.count {
+		return invalidDocumentValue(value.doc, []interface{}{i})
+	}
+	return DocumentValue{doc: value.doc, index: value.doc.children[entry.first+i]}
+}
+
+// Field returns the value of the field key of an object, the last one when the key is repeated.
+func (value DocumentValue) Field(key string) DocumentValue {
+	if value.err != nil {
+		return value
+	}
+	child := -1
+	if value.doc.tape[value.index].kind == ObjectValue {
+		child = value.doc.fieldValue(value.index, key)
+	}
+	if child == -1 {
+		return invalidDocumentValue(value.doc, []interface{}{key})
+	}
+	return DocumentValue{doc: value.doc, index: child}
+}
+
+// Keys returns the keys of the fields of an object, in the order of the input, a repeated key
+// as many times as it is found.
+func (value DocumentValue) Keys() []string {
+	if value.err != nil || value.doc.tape[value.index].kind != ObjectValue {
+		return nil
+	}
+	entry := value.doc.tape[value.index]
+	return append([]string(nil), value.doc.keys[entry.first:entry.first+entry.count]...)
+}
+
+// Path returns the value found by following path: a string is the key of a field, an int the index of an element.
+func (value DocumentValue) Path(path ...interface{}) DocumentValue {
+	for i, pathKeyObj := range path {
+		if value.err != nil {
+			return value
+		}
+		var next DocumentValue
+		switch pathKey := pathKeyObj.(type) {
+		case string:
+			next = value.Field(pathKey)
+		case int:
+			next = value.Index(pathKey)
+		default:
+			next = invalidDocumentValue(value.doc, nil)
+		}
+		if next.err != nil {
+			return invalidDocumentValue(value.doc, path[i:])
+		}
+		value = next
+	}
+	return value
+}
+
+// Raw returns the bytes of the value in the input.
+func (value DocumentValue) Raw() []byte {
+	if value.err != nil {
+		return nil
+	}
+	entry := value.doc.tape[value.index]
+	return value.doc.data[entry.start:entry.end]
+}
+
+// Decode decodes the value into obj with the decoder of its type, as Unmarshal would.
+// The errors are located in the whole input.
+func (value DocumentValue) Decode(obj interface{}) error {
+	if value.err != nil {
+		return value.err
+	}
+	cfg := value.doc.cfg
+	iter := cfg.BorrowIterator(value.doc.data)
+	defer cfg.ReturnIterator(iter)
+	iter.head = value.doc.tape[value.index].start
+	iter.ReadVal(obj)
+	err := iter.Error
+	if err == io.EOF {
+		err = nil
+	}
+	return iter.withCollectedErrors(err)
+}
+
+// Any returns the value as an Any.
+func (value DocumentValue) Any() Any {
+	if value.err != nil {
+		return &invalidAny{baseAny{}, value.err}
+	}
+	iter := value.doc.cfg.BorrowIterator(value.Raw())
+	defer value.doc.cfg.ReturnIterator(iter)
+	return iter.ReadAny()
+}
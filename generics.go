@@ -0,0 +1,93 @@
+//go:build go1.18
+// +build go1.18
+
+package jsoniter
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// Codec reads and writes values of type T, with the decoder and encoder of an API
+// looked up once instead of for every value, and without boxing the values into interface{}.
+type Codec[T any] struct {
+	cfg     *frozenConfig
+	typ     reflect2.Type
+	decoder ValDecoder
+	encoder ValEncoder
+}
+
+// NewCodec returns the Codec of T for api. Keep it to decode or encode many values.
+func NewCodec[T any](api API) *Codec[T] {
+	cfg := api.(*frozenConfig)
+	ptrType := reflect2.TypeOfPtr((*T)(nil))
+	return &Codec[T]{
+		cfg:     cfg,
+		typ:     ptrType.Elem(),
+		decoder: cfg.DecoderOf(ptrType),
+		encoder: cfg.EncoderOf(ptrType),
+	}
+}
+
+// Decode reads the next value from iter, as ReadVal does.
+func (codec *Codec[T]) Decode(iter *Iterator) T {
+	var val T
+	depth := iter.depth
+	start, failed := iter.offset(), iter.hasDecodeError()
+	codec.decoder.Decode(unsafe.Pointer(&val), iter)
+	if !failed && iter.hasDecodeError() {
+		iter.addErrorValue(codec.typ, start)
+	}
+	if iter.depth != depth {
+		iter.ReportError("Decode", "unexpected mismatched nesting")
+	}
+	return val
+}
+
+// Encode writes val to stream, as WriteVal does.
+func (codec *Codec[T]) Encode(stream *Stream, val T) {
+	codec.encoder.Encode(unsafe.Pointer(&val), stream)
+}
+
+// Unmarshal decodes data, as API.Unmarshal does.
+func (codec *Codec[T]) Unmarshal(data []byte) (T, error) {
+	iter := codec.cfg.BorrowIterator(data)
+	defer codec.cfg.ReturnIterator(iter)
+	val := codec.Decode(iter)
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return val, iter.withCollectedErrors(nil)
+		}
+		return val, iter.withCollectedErrors(iter.Error)
+	}
+	iter.ReportError("Unmarshal", "there are bytes left after unmarshal")
+	return val, iter.withCollectedErrors(iter.Error)
+}
+
+// Marshal encodes val, as API.Marshal does.
+func (codec *Codec[T]) Marshal(val T) ([]byte, error) {
+	stream := codec.cfg.BorrowStream(nil)
+	defer codec.cfg.ReturnStream(stream)
+	codec.Encode(stream, val)
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	result := stream.Buffer()
+	copied := make([]byte, len(result))
+	copy(copied, result)
+	return copied, nil
+}
+
+// UnmarshalAs decodes data into a value of type T, e.g. UnmarshalAs[[]int](ConfigDefault, data).
+// Use a Codec to decode many values of the same type.
+func UnmarshalAs[T any](api API, data []byte) (T, error) {
+	return NewCodec[T](api).Unmarshal(data)
+}
+
+// MarshalT encodes val of type T, the type being known at compile time rather than looked up from interface{}.
+func MarshalT[T any](api API, val T) ([]byte, error) {
+	return NewCodec[T](api).Marshal(val)
+}
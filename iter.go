@@ -77,8 +77,27 @@ type Iterator struct {
 	depth            int
 	captureStartedAt int
 	captured         []byte
-	Error            error
-	Attachment       interface{} // open for customized decoder
+	// errors collected with Config.CollectAllErrors
+	collectedErrors []*DecodeError
+	// input already discarded from buf, to locate errors in the whole input
+	bufOffset    int64
+	bufLine      int
+	bufLineStart int64
+	// input past Config.MaxInputBytes hidden by limitInput
+	inputLimited bool
+	// where Token is in the input, see iter_token.go
+	tokenState int
+	tokenStack []int
+	tokenDepth int
+	// the input of a relaxed config ends in a block comment, hidden as whitespace, see iter_json5.go
+	openComment bool
+	// the elements or keys read so far by ReadArray and ReadObject in each of the containers open
+	pulledCounts []int
+	// the index of the input with Config.UseStructuralIndex, see iter_structural.go
+	structural     *structuralIndex
+	structuralBase int
+	Error          error
+	Attachment     interface{} // open for customized decoder
 }
 
 // NewIterator creates an empty Iterator instance
@@ -95,19 +114,21 @@ func NewIterator(cfg API) *Iterator {
 
 // Parse creates an Iterator instance from io.Reader
 func Parse(cfg API, reader io.Reader, bufSize int) *Iterator {
-	return &Iterator{
+	iter := &Iterator{
 		cfg:    cfg.(*frozenConfig),
-		reader: reader,
+		reader: nil,
 		buf:    make([]byte, bufSize),
 		head:   0,
 		tail:   0,
 		depth:  0,
 	}
+	iter.reader = iter.relaxReader(reader)
+	return iter
 }
 
 // ParseBytes creates an Iterator instance from byte array
 func ParseBytes(cfg API, input []byte) *Iterator {
-	return &Iterator{
+	iter := &Iterator{
 		cfg:    cfg.(*frozenConfig),
 		reader: nil,
 		buf:    input,
@@ -115,6 +136,9 @@ func ParseBytes(cfg API, input []byte) *Iterator {
 		tail:   len(input),
 		depth:  0,
 	}
+	iter.relaxInput()
+	iter.limitInput()
+	return iter
 }
 
 // ParseString creates an Iterator instance from string
@@ -129,10 +153,17 @@ func (iter *Iterator) Pool() IteratorPool {
 
 // Reset reuse iterator instance by specifying another reader
 func (iter *Iterator) Reset(reader io.Reader) *Iterator {
-	iter.reader = reader
+	iter.reader = iter.relaxReader(reader)
 	iter.head = 0
 	iter.tail = 0
 	iter.depth = 0
+	iter.openComment = false
+	iter.pulledCounts = iter.pulledCounts[:0]
+	iter.collectedErrors = nil
+	iter.resetTokens()
+	iter.resetLocation()
+	iter.resetStructuralIndex()
+	iter.inputLimited = false
 	return iter
 }
 
@@ -143,12 +174,24 @@ func (iter *Iterator) ResetBytes(input []byte) *Iterator {
 	iter.head = 0
 	iter.tail = len(input)
 	iter.depth = 0
+	iter.openComment = false
+	iter.pulledCounts = iter.pulledCounts[:0]
+	iter.collectedErrors = nil
+	iter.resetTokens()
+	iter.resetLocation()
+	iter.resetStructuralIndex()
+	iter.relaxInput()
+	iter.limitInput()
 	return iter
 }
 
 // WhatIsNext gets ValueType of relatively next json element
 func (iter *Iterator) WhatIsNext() ValueType {
-	valueType := valueTypes[iter.nextToken()]
+	c := iter.nextToken()
+	valueType := valueTypes[c]
+	if valueType == InvalidValue && iter.cfg.relaxed {
+		valueType = iter.relaxedValueType(c)
+	}
 	iter.unreadByte()
 	return valueType
 }
@@ -221,8 +264,18 @@ func (iter *Iterator) ReportError(operation string, msg string) {
 		contextEnd = iter.tail
 	}
 	context := string(iter.buf[contextStart:contextEnd])
-	iter.Error = fmt.Errorf("%s: %s, error found in #%v byte of ...|%s|..., bigger context ...|%s|...",
-		operation, msg, iter.head-peekStart, parsing, context)
+	offset, line, column := iter.location()
+	iter.Error = &SyntaxError{
+		Operation:  operation,
+		Msg:        msg,
+		Offset:     offset,
+		Line:       line,
+		Column:     column,
+		parsing:    parsing,
+		parsingAt:  iter.head - peekStart,
+		context:    context,
+		compatible: iter.cfg.compatible,
+	}
 }
 
 // CurrentBuffer gets current buffer as string for debugging purpose
@@ -250,6 +303,20 @@ func (iter *Iterator) readByte() (ret byte) {
 }
 
 func (iter *Iterator) loadMore() bool {
+	if iter.inputLimited {
+		if iter.Error == nil {
+			iter.head = iter.tail
+			iter.reportLimitError("loadMore", "MaxInputBytes", iter.cfg.maxInputBytes)
+		}
+		return false
+	}
+	if iter.openComment {
+		if iter.Error == nil {
+			iter.head = iter.tail
+			iter.ReportError("skipComment", "comment not ended with */")
+		}
+		return false
+	}
 	if iter.reader == nil {
 		if iter.Error == nil {
 			iter.head = iter.tail
@@ -262,9 +329,14 @@ func (iter *Iterator) loadMore() bool {
 			iter.buf[iter.captureStartedAt:iter.tail]...)
 		iter.captureStartedAt = 0
 	}
+	bufOffset, bufLine, bufLineStart := iter.locationAfterBuffer()
 	for {
 		n, err := iter.reader.Read(iter.buf)
 		if n == 0 {
+			if err == errCommentNotEnded {
+				iter.openComment = true
+				return iter.loadMore()
+			}
 			if err != nil {
 				if iter.Error == nil {
 					iter.Error = err
@@ -272,8 +344,13 @@ func (iter *Iterator) loadMore() bool {
 				return false
 			}
 		} else {
+			iter.bufOffset, iter.bufLine, iter.bufLineStart = bufOffset, bufLine, bufLineStart
 			iter.head = 0
 			iter.tail = n
+			iter.limitInput()
+			if iter.tail == 0 {
+				return iter.loadMore()
+			}
 			return true
 		}
 	}
@@ -307,7 +384,12 @@ func (iter *Iterator) Read() interface{} {
 		arr := []interface{}{}
 		iter.ReadArrayCB(func(iter *Iterator) bool {
 			var elem interface{}
+			start, mark := iter.offset(), iter.errorMark()
 			iter.ReadVal(&elem)
+			if iter.hasErrorsSince(mark) {
+				iter.addErrorIndex(efaceType, len(arr), start, mark)
+				return !iter.hasDecodeError()
+			}
 			arr = append(arr, elem)
 			return true
 		})
@@ -316,7 +398,12 @@ func (iter *Iterator) Read() interface{} {
 		obj := map[string]interface{}{}
 		iter.ReadMapCB(func(Iter *Iterator, field string) bool {
 			var elem interface{}
+			start, mark := iter.offset(), iter.errorMark()
 			iter.ReadVal(&elem)
+			if iter.hasErrorsSince(mark) {
+				iter.addErrorElement(efaceType, field, start, mark)
+				return !iter.hasDecodeError()
+			}
 			obj[field] = elem
 			return true
 		})
@@ -327,15 +414,15 @@ func (iter *Iterator) Read() interface{} {
 	}
 }
 
-// limit maximum depth of nesting, as allowed by https://tools.ietf.org/html/rfc7159#section-9
-const maxDepth = 10000
+// default maximum depth of nesting, as allowed by https://tools.ietf.org/html/rfc7159#section-9
+const defaultMaxDepth = 10000
 
 func (iter *Iterator) incrementDepth() (success bool) {
 	iter.depth++
-	if iter.depth <= maxDepth {
+	if iter.depth <= iter.cfg.maxDepth {
 		return true
 	}
-	iter.ReportError("incrementDepth", "exceeded max depth")
+	iter.reportLimitError("incrementDepth", "MaxDepth", iter.cfg.maxDepth)
 	return false
 }
 
@@ -347,3 +434,73 @@ func (iter *Iterator) decrementDepth() (success bool) {
 	iter.ReportError("decrementDepth", "unexpected negative nesting")
 	return false
 }
+
+// openPulled enters the array or object whose [ or { ReadArray or ReadObject just read, with a
+// first element or key, so that the limits are enforced across the calls reading it.
+func (iter *Iterator) openPulled() bool {
+	if !iter.incrementDepth() {
+		return false
+	}
+	iter.pulledCounts = append(iter.pulledCounts, 1)
+	return true
+}
+
+// nextPulled counts the element or key after a comma of the array or object read by ReadArray
+// or ReadObject, telling if it is within the limits.
+func (iter *Iterator) nextPulled(operation string, array bool) bool {
+	if len(iter.pulledCounts) == 0 {
+		// not entered by ReadArray or ReadObject
+		return true
+	}
+	top := len(iter.pulledCounts) - 1
+	iter.pulledCounts[top]++
+	if array {
+		return iter.checkArrayElements(operation, iter.pulledCounts[top])
+	}
+	return iter.checkObjectKeys(operation, iter.pulledCounts[top])
+}
+
+// closePulled leaves the array or object whose ] or } ReadArray or ReadObject just read.
+func (iter *Iterator) closePulled() {
+	if len(iter.pulledCounts) == 0 {
+		return
+	}
+	iter.pulledCounts = iter.pulledCounts[:len(iter.pulledCounts)-1]
+	iter.decrementDepth()
+}
+
+// checkStringLength tells if a string of length bytes is within Config.MaxStringLength.
+func (iter *Iterator) checkStringLength(operation string, length int) bool {
+	if iter.cfg.maxStringLength <= 0 || length <= iter.cfg.maxStringLength {
+		return true
+	}
+	iter.reportLimitError(operation, "MaxStringLength", iter.cfg.maxStringLength)
+	return false
+}
+
+// checkArrayElements tells if an array may have count elements within Config.MaxArrayElements.
+func (iter *Iterator) checkArrayElements(operation string, count int) bool {
+	if iter.cfg.maxArrayElements <= 0 || count <= iter.cfg.maxArrayElements {
+		return true
+	}
+	iter.reportLimitError(operation, "MaxArrayElements", iter.cfg.maxArrayElements)
+	return false
+}
+
+// checkObjectKeys tells if an object may have count keys within Config.MaxObjectKeys.
+func (iter *Iterator) checkObjectKeys(operation string, count int) bool {
+	if iter.cfg.maxObjectKeys <= 0 || count <= iter.cfg.maxObjectKeys {
+		return true
+	}
+	iter.reportLimitError(operation, "MaxObjectKeys", iter.cfg.maxObjectKeys)
+	return false
+}
+
+// limitInput hides the input past Config.MaxInputBytes, loadMore then reports a *LimitError.
+func (iter *Iterator) limitInput() {
+	max := int64(iter.cfg.maxInputBytes)
+	iter.inputLimited = max > 0 && iter.bufOffset+int64(iter.tail) > max
+	if iter.inputLimited {
+		iter.tail = int(max - iter.bufOffset)
+	}
+}
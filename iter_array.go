@@ -11,13 +11,17 @@ func (iter *Iterator) ReadArray() (ret bool) {
 		c = iter.nextToken()
 		if c != ']' {
 			iter.unreadByte()
-			return true
+			return iter.openPulled()
+		}
+		if iter.incrementDepth() {
+			iter.decrementDepth()
 		}
 		return false
 	case ']':
+		iter.closePulled()
 		return false
 	case ',':
-		return true
+		return iter.nextPulled("ReadArray", true)
 	default:
 		iter.ReportError("ReadArray", "expect [ or , or ] or n, but found "+string([]byte{c}))
 		return
@@ -38,9 +42,11 @@ func (iter *Iterator) ReadArrayCB(callback func(*Iterator) bool) (ret bool) {
 				iter.decrementDepth()
 				return false
 			}
+			elements := 1
 			c = iter.nextToken()
 			for c == ',' {
-				if !callback(iter) {
+				elements++
+				if !iter.checkArrayElements("ReadArrayCB", elements) || !callback(iter) {
 					iter.decrementDepth()
 					return false
 				}
@@ -0,0 +1,48 @@
+package jsoniter
+
+import "fmt"
+
+// objectKeys are the keys of an object read so far, to find the duplicates with Config.DuplicateKeys.
+// The first keys are searched, the next put in a set.
+type objectKeys struct {
+	list []interface{}
+	set  map[interface{}]struct{}
+}
+
+const objectKeysScanned = 8
+
+// add remembers key, telling if it is the first time.
+func (keys *objectKeys) add(key interface{}) bool {
+	if keys.set == nil {
+		for _, seen := range keys.list {
+			if seen == key {
+				return false
+			}
+		}
+		if len(keys.list) < objectKeysScanned {
+			keys.list = append(keys.list, key)
+			return true
+		}
+		keys.set = make(map[interface{}]struct{}, 2*objectKeysScanned)
+		for _, seen := range keys.list {
+			keys.set[seen] = struct{}{}
+		}
+	}
+	if _, found := keys.set[key]; found {
+		return false
+	}
+	keys.set[key] = struct{}{}
+	return true
+}
+
+// duplicateKey tells if key, identified by id, was already read in the object of keys, its value to be skipped
+// with DuplicateKeysFirstWins. It reports key with DuplicateKeysError. It is not called with DuplicateKeysLastWins.
+func (iter *Iterator) duplicateKey(operation string, keys *objectKeys, id interface{}, key string) bool {
+	if keys.add(id) {
+		return false
+	}
+	if iter.cfg.duplicateKeys == DuplicateKeysError {
+		iter.ReportError(operation, fmt.Sprintf("duplicate key: %q", key))
+	}
+	return true
+}
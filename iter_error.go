@@ -0,0 +1,121 @@
+package jsoniter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SyntaxError is the error reported by Iterator when the input is not what a read operation expects.
+// Offset counts the bytes read from the whole input when the error was found,
+// Line and Column, both starting at 1, locate the last of them.
+type SyntaxError struct {
+	Operation string
+	Msg       string
+	Offset    int64
+	Line      int
+	Column    int
+	// the input around the error, for the message
+	parsing   string
+	parsingAt int
+	context   string
+	// reported with Config.StandardLibraryErrors, see As
+	compatible bool
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s, error found in #%v byte of ...|%s|..., bigger context ...|%s|...",
+		err.Operation, err.Msg, err.parsingAt, err.parsing, err.context)
+}
+
+// As lets errors.As find a *json.SyntaxError, for code written against encoding/json, when the
+// error is reported with Config.StandardLibraryErrors set. Only its Offset is set, encoding/json
+// not exporting its message.
+func (err *SyntaxError) As(target interface{}) bool {
+	if target, isStdError := target.(**json.SyntaxError); isStdError && err.compatible {
+		*target = &json.SyntaxError{Offset: err.Offset}
+		return true
+	}
+	return false
+}
+
+// LimitError is the error reported by Iterator when the input goes over one of the limits set in Config.
+type LimitError struct {
+	// Limit is the name of the Config field, e.g. MaxDepth
+	Limit  string
+	Max    int
+	Offset int64
+	Line   int
+	Column int
+	// err formats the message like a SyntaxError
+	err *SyntaxError
+}
+
+func (err *LimitError) Error() string {
+	return err.err.Error()
+}
+
+var limitDescriptions = map[string]string{
+	"MaxDepth":         "max depth",
+	"MaxStringLength":  "max string length",
+	"MaxArrayElements": "max array elements",
+	"MaxObjectKeys":    "max object keys",
+	"MaxInputBytes":    "max input bytes",
+}
+
+// reportLimitError records a *LimitError about the Config field limit, like ReportError.
+func (iter *Iterator) reportLimitError(operation string, limit string, max int) {
+	if iter.Error != nil && iter.Error != io.EOF {
+		return
+	}
+	iter.ReportError(operation, fmt.Sprintf("exceeded %s of %d", limitDescriptions[limit], max))
+	syntaxErr := iter.Error.(*SyntaxError)
+	iter.Error = &LimitError{
+		Limit:  limit,
+		Max:    max,
+		Offset: syntaxErr.Offset,
+		Line:   syntaxErr.Line,
+		Column: syntaxErr.Column,
+		err:    syntaxErr,
+	}
+}
+
+// offset returns the number of bytes read from the whole input.
+func (iter *Iterator) offset() int64 {
+	return iter.bufOffset + int64(iter.head)
+}
+
+// location returns the offset, plus the line and column of the last byte read.
+func (iter *Iterator) location() (offset int64, line int, column int) {
+	return iter.locationAt(iter.head)
+}
+
+// locationAt returns the location of buf[:head] in the whole input.
+func (iter *Iterator) locationAt(head int) (offset int64, line int, column int) {
+	offset = iter.bufOffset + int64(head)
+	read := iter.buf[:head]
+	line = iter.bufLine + bytes.Count(read, []byte{'\n'}) + 1
+	if lastNewLine := bytes.LastIndexByte(read, '\n'); lastNewLine != -1 {
+		return offset, line, head - lastNewLine - 1
+	}
+	return offset, line, int(offset - iter.bufLineStart)
+}
+
+// locationAfterBuffer returns what offset, line and lineStart become once buf is discarded,
+// to be computed before the next read overwrites it.
+func (iter *Iterator) locationAfterBuffer() (bufOffset int64, bufLine int, bufLineStart int64) {
+	discarded := iter.buf[:iter.tail]
+	bufLine, bufLineStart = iter.bufLine, iter.bufLineStart
+	if lastNewLine := bytes.LastIndexByte(discarded, '\n'); lastNewLine != -1 {
+		bufLine += bytes.Count(discarded, []byte{'\n'})
+		bufLineStart = iter.bufOffset + int64(lastNewLine) + 1
+	}
+	return iter.bufOffset + int64(iter.tail), bufLine, bufLineStart
+}
+
+func (iter *Iterator) resetLocation() {
+	iter.bufOffset = 0
+	iter.bufLine = 0
+	iter.bufLineStart = 0
+}
@@ -3,7 +3,9 @@ package jsoniter
 import (
 	"encoding/json"
 	"io"
+	"math"
 	"math/big"
+	"math/bits"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -30,6 +32,7 @@ func init() {
 	floatDigits['\t'] = endOfNumber
 	floatDigits['\n'] = endOfNumber
 	floatDigits['.'] = dotInNumber
+	initPowersOfTen128()
 }
 
 // ReadBigFloat read big.Float
@@ -68,6 +71,9 @@ func (iter *Iterator) ReadBigInt() (ret *big.Int) {
 
 //ReadFloat32 read float32
 func (iter *Iterator) ReadFloat32() (ret float32) {
+	if iter.cfg.checkedNumbers {
+		return float32(iter.readCheckedFloat(32))
+	}
 	c := iter.nextToken()
 	if c == '-' {
 		return -iter.readPositiveFloat32()
@@ -157,6 +163,14 @@ non_decimal_loop:
 }
 
 func (iter *Iterator) readNumberAsString() (ret string) {
+	if iter.cfg.checkedNumbers {
+		return iter.readCheckedNumber()
+	}
+	return iter.readNumberChars()
+}
+
+// readNumberChars reads the bytes which may be in a number, without checking its form.
+func (iter *Iterator) readNumberChars() (ret string) {
 	strBuf := [16]byte{}
 	str := strBuf[0:0]
 load_loop:
@@ -203,8 +217,32 @@ func (iter *Iterator) readFloat32SlowPath() (ret float32) {
 	return float32(val)
 }
 
+// readCheckedNumber reads a number of JSON5, or of JSON checked to have the form of RFC 8259.
+func (iter *Iterator) readCheckedNumber() string {
+	if iter.cfg.allowJSON5Numbers {
+		return iter.readJSON5Number()
+	}
+	return iter.readStrictNumber()
+}
+
+func (iter *Iterator) readCheckedFloat(bitSize int) float64 {
+	str := iter.readCheckedNumber()
+	if str == "" {
+		return 0
+	}
+	val, err := strconv.ParseFloat(str, bitSize)
+	if err != nil {
+		iter.ReportError("readCheckedFloat", err.Error())
+		return 0
+	}
+	return val
+}
+
 // ReadFloat64 read float64
 func (iter *Iterator) ReadFloat64() (ret float64) {
+	if iter.cfg.checkedNumbers {
+		return float64(iter.readCheckedFloat(64))
+	}
 	c := iter.nextToken()
 	if c == '-' {
 		return -iter.readPositiveFloat64()
@@ -297,6 +335,9 @@ non_decimal_loop:
 }
 
 func (iter *Iterator) readFloat64SlowPath() (ret float64) {
+	if val, ok := iter.readFloat64EiselLemire(); ok {
+		return val
+	}
 	str := iter.readNumberAsString()
 	if iter.Error != nil && iter.Error != io.EOF {
 		return
@@ -340,3 +381,174 @@ func validateFloat(str string) string {
 func (iter *Iterator) ReadNumber() (ret json.Number) {
 	return json.Number(iter.readNumberAsString())
 }
+
+// the powers of ten from 10^-348 to 10^347 as 128-bit mantissas, rounded down,
+// for Eisel-Lemire and the shortest formatting of floats
+const powersOfTen128Min = -348
+const powersOfTen128Max = 347
+
+var powersOfTen128 [powersOfTen128Max - powersOfTen128Min + 1][2]uint64
+
+// exactPowersOfTen are the powers of ten a float64 holds exactly
+var exactPowersOfTen = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22}
+
+func initPowersOfTen128() {
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	setPower := func(q int, pow *big.Int) {
+		shifted := new(big.Int)
+		if n := pow.BitLen(); n > 128 {
+			shifted.Rsh(pow, uint(n-128))
+		} else {
+			shifted.Lsh(pow, uint(128-n))
+		}
+		lo := new(big.Int).And(shifted, mask).Uint64()
+		hi := shifted.Rsh(shifted, 64).Uint64()
+		powersOfTen128[q-powersOfTen128Min] = [2]uint64{lo, hi}
+	}
+	ten := big.NewInt(10)
+	pow := big.NewInt(1)
+	for q := 0; q <= powersOfTen128Max; q++ {
+		setPower(q, pow)
+		pow.Mul(pow, ten)
+	}
+	pow.SetInt64(1)
+	for q := -1; q >= powersOfTen128Min; q-- {
+		pow.Mul(pow, ten)
+		// 2^(127+n) / 10^-q has 128 bits when 10^-q has n
+		inverse := new(big.Int).Lsh(big.NewInt(1), uint(127+pow.BitLen()))
+		setPower(q, inverse.Quo(inverse, pow))
+	}
+}
+
+// readFloat64EiselLemire reads the number in the buffer as readFloat64SlowPath does, without
+// strconv, when it has no more than 19 significant digits. It tells if it did, leaving strconv
+// the numbers not ended in the buffer, not well formed, or which it can not round for sure.
+func (iter *Iterator) readFloat64EiselLemire() (float64, bool) {
+	buf := iter.buf[iter.head:iter.tail]
+	i := 0
+	mantissa, digits, exp10 := uint64(0), 0, 0
+	if i < len(buf) && buf[i] == '0' {
+		i++
+	} else {
+		for ; i < len(buf) && buf[i] >= '0' && buf[i] <= '9'; i++ {
+			mantissa = mantissa*10 + uint64(buf[i]-'0')
+			digits++
+		}
+		if digits == 0 {
+			return 0, false
+		}
+	}
+	if i < len(buf) && buf[i] == '.' {
+		i++
+		start := i
+		for ; i < len(buf) && buf[i] >= '0' && buf[i] <= '9'; i++ {
+			exp10--
+			if mantissa == 0 && buf[i] == '0' {
+				continue
+			}
+			mantissa = mantissa*10 + uint64(buf[i]-'0')
+			digits++
+		}
+		if i == start {
+			return 0, false
+		}
+	}
+	if i < len(buf) && (buf[i] == 'e' || buf[i] == 'E') {
+		i++
+		negative := false
+		if i < len(buf) && (buf[i] == '+' || buf[i] == '-') {
+			negative = buf[i] == '-'
+			i++
+		}
+		start := i
+		exp := 0
+		for ; i < len(buf) && buf[i] >= '0' && buf[i] <= '9'; i++ {
+			if exp < 10000 {
+				exp = exp*10 + int(buf[i]-'0')
+			}
+		}
+		if i == start {
+			return 0, false
+		}
+		if negative {
+			exp = -exp
+		}
+		exp10 += exp
+	}
+	if i == len(buf) || digits > 19 {
+		return 0, false
+	}
+	switch buf[i] {
+	case '+', '-', '.', 'e', 'E', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return 0, false
+	}
+	val := float64(0)
+	switch {
+	case mantissa == 0:
+		// 0 whatever the exponent
+	case mantissa <= 1<<53 && exp10 >= -22 && exp10 <= 22:
+		// both exact, the result is rounded once
+		val = float64(mantissa)
+		if exp10 < 0 {
+			val /= exactPowersOfTen[-exp10]
+		} else {
+			val *= exactPowersOfTen[exp10]
+		}
+	default:
+		var ok bool
+		if val, ok = eiselLemire64(mantissa, exp10); !ok {
+			return 0, false
+		}
+	}
+	iter.head += i
+	return val, true
+}
+
+// eiselLemire64 returns the float64 nearest to mantissa*10^exp10, see
+// "Number Parsing at a Gigabyte per Second" by Daniel Lemire. It tells if the result is
+// rounded for sure, which it is not when halfway between two floats or out of the normal floats.
+func eiselLemire64(mantissa uint64, exp10 int) (float64, bool) {
+	if exp10 < powersOfTen128Min || exp10 > powersOfTen128Max {
+		return 0, false
+	}
+	pow := powersOfTen128[exp10-powersOfTen128Min]
+	// normalization
+	clz := bits.LeadingZeros64(mantissa)
+	mantissa <<= uint(clz)
+	retExp2 := uint64(217706*exp10>>16+64+1023) - uint64(clz)
+	// multiplication
+	xHi, xLo := bits.Mul64(mantissa, pow[1])
+	// wider approximation
+	if xHi&0x1ff == 0x1ff && xLo+mantissa < mantissa {
+		yHi, yLo := bits.Mul64(mantissa, pow[0])
+		mergedHi, mergedLo := xHi, xLo+yHi
+		if mergedLo < xLo {
+			mergedHi++
+		}
+		if mergedHi&0x1ff == 0x1ff && mergedLo+1 == 0 && yLo+mantissa < mantissa {
+			return 0, false
+		}
+		xHi, xLo = mergedHi, mergedLo
+	}
+	// shifting to 54 bits
+	msb := xHi >> 63
+	retMantissa := xHi >> (msb + 9)
+	retExp2 -= 1 ^ msb
+	// halfway ambiguity
+	if xLo == 0 && xHi&0x1ff == 0 && retMantissa&3 == 1 {
+		return 0, false
+	}
+	// from 54 to 53 bits
+	retMantissa += retMantissa & 1
+	retMantissa >>= 1
+	if retMantissa>>53 > 0 {
+		retMantissa >>= 1
+		retExp2++
+	}
+	// retExp2 is 0 for the subnormal floats, 0x7ff and above for the infinities
+	if retExp2-1 >= 0x7ff-1 {
+		return 0, false
+	}
+	return math.Float64frombits(retExp2<<52 | retMantissa&(1<<52-1)), true
+}
@@ -3,6 +3,7 @@ package jsoniter
 import (
 	"math"
 	"strconv"
+	"strings"
 )
 
 var intDigits []int8
@@ -39,6 +40,9 @@ func (iter *Iterator) ReadInt() int {
 
 // ReadInt8 read int8
 func (iter *Iterator) ReadInt8() (ret int8) {
+	if iter.cfg.checkedNumbers {
+		return int8(iter.readCheckedInt("ReadInt8", 8))
+	}
 	c := iter.nextToken()
 	if c == '-' {
 		val := iter.readUint32(iter.readByte())
@@ -58,6 +62,9 @@ func (iter *Iterator) ReadInt8() (ret int8) {
 
 // ReadUint8 read uint8
 func (iter *Iterator) ReadUint8() (ret uint8) {
+	if iter.cfg.checkedNumbers {
+		return uint8(iter.readCheckedUint("ReadUint8", 8))
+	}
 	val := iter.readUint32(iter.nextToken())
 	if val > math.MaxUint8 {
 		iter.ReportError("ReadUint8", "overflow: "+strconv.FormatInt(int64(val), 10))
@@ -68,6 +75,9 @@ func (iter *Iterator) ReadUint8() (ret uint8) {
 
 // ReadInt16 read int16
 func (iter *Iterator) ReadInt16() (ret int16) {
+	if iter.cfg.checkedNumbers {
+		return int16(iter.readCheckedInt("ReadInt16", 16))
+	}
 	c := iter.nextToken()
 	if c == '-' {
 		val := iter.readUint32(iter.readByte())
@@ -87,6 +97,9 @@ func (iter *Iterator) ReadInt16() (ret int16) {
 
 // ReadUint16 read uint16
 func (iter *Iterator) ReadUint16() (ret uint16) {
+	if iter.cfg.checkedNumbers {
+		return uint16(iter.readCheckedUint("ReadUint16", 16))
+	}
 	val := iter.readUint32(iter.nextToken())
 	if val > math.MaxUint16 {
 		iter.ReportError("ReadUint16", "overflow: "+strconv.FormatInt(int64(val), 10))
@@ -97,6 +110,9 @@ func (iter *Iterator) ReadUint16() (ret uint16) {
 
 // ReadInt32 read int32
 func (iter *Iterator) ReadInt32() (ret int32) {
+	if iter.cfg.checkedNumbers {
+		return int32(iter.readCheckedInt("ReadInt32", 32))
+	}
 	c := iter.nextToken()
 	if c == '-' {
 		val := iter.readUint32(iter.readByte())
@@ -116,6 +132,9 @@ func (iter *Iterator) ReadInt32() (ret int32) {
 
 // ReadUint32 read uint32
 func (iter *Iterator) ReadUint32() (ret uint32) {
+	if iter.cfg.checkedNumbers {
+		return uint32(iter.readCheckedUint("ReadUint32", 32))
+	}
 	return iter.readUint32(iter.nextToken())
 }
 
@@ -219,6 +238,9 @@ func (iter *Iterator) readUint32(c byte) (ret uint32) {
 
 // ReadInt64 read int64
 func (iter *Iterator) ReadInt64() (ret int64) {
+	if iter.cfg.checkedNumbers {
+		return int64(iter.readCheckedInt("ReadInt64", 64))
+	}
 	c := iter.nextToken()
 	if c == '-' {
 		val := iter.readUint64(iter.readByte())
@@ -238,6 +260,9 @@ func (iter *Iterator) ReadInt64() (ret int64) {
 
 // ReadUint64 read uint64
 func (iter *Iterator) ReadUint64() uint64 {
+	if iter.cfg.checkedNumbers {
+		return uint64(iter.readCheckedUint("ReadUint64", 64))
+	}
 	return iter.readUint64(iter.nextToken())
 }
 
@@ -340,7 +365,43 @@ func (iter *Iterator) readUint64(c byte) (ret uint64) {
 }
 
 func (iter *Iterator) assertInteger() {
-	if iter.head < iter.tail && iter.buf[iter.head] == '.' {
+	if iter.head < iter.tail && (iter.buf[iter.head] == '.' || iter.buf[iter.head] == 'e' || iter.buf[iter.head] == 'E') {
 		iter.ReportError("assertInteger", "can not decode float as int")
 	}
 }
+
+func (iter *Iterator) readCheckedInt(operation string, bitSize int) int64 {
+	str := iter.readCheckedInteger(operation)
+	if str == "" {
+		return 0
+	}
+	val, err := strconv.ParseInt(str, 10, bitSize)
+	if err != nil {
+		iter.ReportError(operation, "overflow: "+str)
+		return 0
+	}
+	return val
+}
+
+func (iter *Iterator) readCheckedUint(operation string, bitSize int) uint64 {
+	str := iter.readCheckedInteger(operation)
+	if str == "" {
+		return 0
+	}
+	val, err := strconv.ParseUint(str, 10, bitSize)
+	if err != nil {
+		iter.ReportError(operation, "overflow: "+str)
+		return 0
+	}
+	return val
+}
+
+// readCheckedInteger reads a number with readCheckedNumber, reporting it unless an integer.
+func (iter *Iterator) readCheckedInteger(operation string) string {
+	str := iter.readCheckedNumber()
+	if strings.ContainsAny(str, ".eEIN") {
+		iter.ReportError(operation, "can not decode float as int")
+		return ""
+	}
+	return str
+}
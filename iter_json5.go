@@ -0,0 +1,407 @@
+package jsoniter
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// The extensions of JSON accepted with Config.AllowJSON5 and the finer grained options, see json5.org.
+// The comments and the trailing commas are blanked from the input before the readers see it, the
+// Iterator picking the input once when it is reset. The readers only look for the other extensions
+// where strict JSON would be an error, strict JSON is read as fast as before.
+
+// errCommentNotEnded is returned by relaxedReader when the input ends in a block comment.
+var errCommentNotEnded = errors.New("comment not ended with */")
+
+// relaxInput blanks the comments and trailing commas of the input set by ResetBytes,
+// in a copy as the input belongs to the caller.
+func (iter *Iterator) relaxInput() {
+	if !iter.cfg.blanksInput {
+		return
+	}
+	buf := append([]byte(nil), iter.buf[:iter.tail]...)
+	cleaner := newRelaxedCleaner(iter.cfg)
+	cleaner.clean(buf, true)
+	iter.buf = buf
+	iter.openComment = cleaner.comment == '*'
+}
+
+// relaxReader returns reader, reading its comments and trailing commas blanked.
+func (iter *Iterator) relaxReader(reader io.Reader) io.Reader {
+	if !iter.cfg.blanksInput || reader == nil {
+		return reader
+	}
+	return &relaxedReader{reader: reader, cleaner: newRelaxedCleaner(iter.cfg)}
+}
+
+// relaxedCleaner replaces the comments and the trailing commas of the input with whitespace.
+// Every byte is kept in place, newlines included, so the offsets, lines and columns of errors
+// stay those of the input. The input may be cleaned in chunks, the cleaner remembering where it is.
+type relaxedCleaner struct {
+	comments bool
+	commas   bool
+	quotes   bool
+	// scanned is the length of the input cleaned so far
+	scanned int
+	// quote is the quote of the string being read, 0 out of strings
+	quote   byte
+	escaped bool
+	// comment is / in a line comment, * in a block comment, 0 out of comments
+	comment byte
+	star    bool
+	// last is the last byte out of strings and comments which is not whitespace
+	last byte
+	// comma is the comma which is trailing if a } or ] follows it, -1 if none
+	comma int
+}
+
+func newRelaxedCleaner(cfg *frozenConfig) relaxedCleaner {
+	return relaxedCleaner{
+		comments: cfg.allowComments,
+		commas:   cfg.allowTrailingCommas,
+		quotes:   cfg.allowSingleQuotes,
+		comma:    -1,
+	}
+}
+
+// clean blanks the comments and trailing commas of buf[cleaner.scanned:], and returns the
+// length of buf which is final. The rest waits for the bytes after buf to tell if it is a
+// comment or a trailing comma, unless atEOF.
+func (cleaner *relaxedCleaner) clean(buf []byte, atEOF bool) int {
+	i := cleaner.scanned
+	for ; i < len(buf); i++ {
+		c := buf[i]
+		switch {
+		case cleaner.comment == '/':
+			if c == '\n' {
+				cleaner.comment = 0
+			} else {
+				buf[i] = ' '
+			}
+		case cleaner.comment == '*':
+			if cleaner.star && c == '/' {
+				cleaner.comment = 0
+			}
+			cleaner.star = c == '*'
+			if c != '\n' {
+				buf[i] = ' '
+			}
+		case cleaner.quote != 0:
+			if cleaner.escaped {
+				cleaner.escaped = false
+			} else if c == '\\' {
+				cleaner.escaped = true
+			} else if c == cleaner.quote {
+				cleaner.quote = 0
+			}
+		case c == ' ' || c == '\n' || c == '\t' || c == '\r':
+		case c == '/' && cleaner.comments:
+			if i+1 == len(buf) && !atEOF {
+				// a comment or not, depending on the next byte
+				cleaner.scanned = i
+				return cleaner.final(i)
+			}
+			if i+1 < len(buf) && (buf[i+1] == '/' || buf[i+1] == '*') {
+				cleaner.comment = buf[i+1]
+				cleaner.star = false
+				buf[i], buf[i+1] = ' ', ' '
+				i++
+				continue
+			}
+			cleaner.token(buf, i, c)
+		default:
+			cleaner.token(buf, i, c)
+		}
+	}
+	cleaner.scanned = i
+	if atEOF {
+		cleaner.comma = -1
+		return len(buf)
+	}
+	return cleaner.final(len(buf))
+}
+
+// token takes c, at buf[i], out of strings and comments.
+func (cleaner *relaxedCleaner) token(buf []byte, i int, c byte) {
+	if cleaner.comma != -1 {
+		if c == '}' || c == ']' {
+			buf[cleaner.comma] = ' '
+		}
+		cleaner.comma = -1
+	}
+	// the comma only trails an element or a member, [,] and {,} are not valid
+	if c == ',' && cleaner.commas && cleaner.last != '[' && cleaner.last != '{' {
+		cleaner.comma = i
+	}
+	if c == '"' || c == '\'' && cleaner.quotes {
+		cleaner.quote = c
+	}
+	cleaner.last = c
+}
+
+// final returns the length of the input which is final when length of it has been scanned.
+func (cleaner *relaxedCleaner) final(length int) int {
+	if cleaner.comma != -1 {
+		return cleaner.comma
+	}
+	return length
+}
+
+// discard forgets the first n bytes of the input, handed over to the Iterator.
+func (cleaner *relaxedCleaner) discard(n int) {
+	cleaner.scanned -= n
+	if cleaner.comma != -1 {
+		cleaner.comma -= n
+	}
+}
+
+// relaxedReader reads the input of reader with its comments and trailing commas blanked.
+type relaxedReader struct {
+	reader  io.Reader
+	cleaner relaxedCleaner
+	// pending is read from reader but not handed over yet, its first final bytes being cleaned
+	pending []byte
+	final   int
+	err     error
+}
+
+func (reader *relaxedReader) Read(p []byte) (int, error) {
+	for reader.final == 0 {
+		if reader.err != nil {
+			return 0, reader.err
+		}
+		if len(p) == 0 {
+			return 0, nil
+		}
+		length := len(reader.pending)
+		if cap(reader.pending)-length < len(p) {
+			pending := make([]byte, length, 2*length+len(p))
+			copy(pending, reader.pending)
+			reader.pending = pending
+		}
+		n, err := reader.reader.Read(reader.pending[length : length+len(p)])
+		reader.pending = reader.pending[:length+n]
+		reader.final = reader.cleaner.clean(reader.pending, err != nil)
+		if err != nil {
+			reader.err = err
+			if err == io.EOF && reader.cleaner.comment == '*' {
+				reader.err = errCommentNotEnded
+			}
+		}
+	}
+	n := copy(p, reader.pending[:reader.final])
+	reader.pending = reader.pending[:copy(reader.pending, reader.pending[n:])]
+	reader.cleaner.discard(n)
+	reader.final -= n
+	return n, nil
+}
+
+// relaxedValueType returns the type of the values which only JSON5 starts with c.
+func (iter *Iterator) relaxedValueType(c byte) ValueType {
+	switch {
+	case c == '\'' && iter.cfg.allowSingleQuotes:
+		return StringValue
+	case isJSON5NumberStart(c) && iter.cfg.allowJSON5Numbers:
+		return NumberValue
+	}
+	return InvalidValue
+}
+
+func isJSON5NumberStart(c byte) bool {
+	switch c {
+	case '+', '.', 'I', 'N':
+		return true
+	}
+	return false
+}
+
+// isRelaxedKeyStart tells if c starts a key which only JSON5 allows.
+func (iter *Iterator) isRelaxedKeyStart(c byte) bool {
+	return c == '\'' && iter.cfg.allowSingleQuotes || isIdentifierStart(c) && iter.cfg.allowUnquotedKeys
+}
+
+// isIdentifierStart tells if c starts an unquoted key: a letter, _ or $, any byte of UTF-8 beyond ASCII.
+func isIdentifierStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c == '$' || c >= 0x80
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || '0' <= c && c <= '9'
+}
+
+// readUnquotedKey reads the identifier starting with c, just read by ReadString, which is a key
+// when a colon follows it. An identifier is no string value, but null is read as ReadString does.
+func (iter *Iterator) readUnquotedKey(c byte) string {
+	iter.unreadByte()
+	key := iter.readIdentifier()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return ""
+	}
+	next := iter.nextToken()
+	iter.unreadByte()
+	switch {
+	case next == ':':
+		return key
+	case key == "null":
+		return ""
+	}
+	iter.ReportError("ReadString", `expects " or n, but found `+string([]byte{c}))
+	return ""
+}
+
+func (iter *Iterator) readIdentifier() string {
+	var str []byte
+	for {
+		c := iter.readByte()
+		if c == 0 && iter.Error != nil {
+			break
+		}
+		if !isIdentifierPart(c) {
+			iter.unreadByte()
+			break
+		}
+		str = append(str, c)
+		if !iter.checkStringLength("readIdentifier", len(str)) {
+			return ""
+		}
+	}
+	return string(str)
+}
+
+// readSingleQuotedString reads the string whose opening ' was just read.
+func (iter *Iterator) readSingleQuotedString() string {
+	var str []byte
+	for {
+		c := iter.readByte()
+		if iter.Error != nil {
+			break
+		}
+		switch {
+		case c == '\'':
+			return string(str)
+		case c == '\\':
+			str = iter.readEscapedChar(iter.readByte(), str)
+			if iter.Error != nil {
+				return ""
+			}
+		case c < ' ':
+			iter.ReportError("readSingleQuotedString", "invalid control character found: "+strconv.Itoa(int(c)))
+			return ""
+		default:
+			str = append(str, c)
+		}
+		if !iter.checkStringLength("readSingleQuotedString", len(str)) {
+			return ""
+		}
+	}
+	iter.ReportError("readSingleQuotedString", "unexpected end of input")
+	return ""
+}
+
+// readRelaxedEscapedChar appends the escapes which only JSON5 allows, c being the byte after the \.
+func (iter *Iterator) readRelaxedEscapedChar(c byte, str []byte) ([]byte, bool) {
+	switch c {
+	case '\'':
+		return append(str, '\''), true
+	case 'v':
+		return append(str, '\v'), true
+	case '0':
+		return append(str, 0), true
+	case '\n', 0xe2:
+		// escaped line breaks, U+2028 and U+2029 starting with e2 in UTF-8
+		if c == 0xe2 && (iter.readByte() != 0x80 || (iter.readByte()|1) != 0xa9) {
+			return str, false
+		}
+		return str, true
+	case '\r':
+		if iter.readByte() != '\n' {
+			iter.unreadByte()
+		}
+		return str, true
+	case 'x':
+		hex := []byte{iter.readByte(), iter.readByte()}
+		b, err := strconv.ParseUint(string(hex), 16, 8)
+		if err != nil {
+			return str, false
+		}
+		return appendRune(str, rune(b)), true
+	}
+	return str, false
+}
+
+// skipRelaxedValue skips the value starting with c, just read, if only JSON5 allows it to start
+// with c. It tells if it did.
+func (iter *Iterator) skipRelaxedValue(c byte) bool {
+	switch {
+	case c == '\'' && iter.cfg.allowSingleQuotes:
+		iter.readSingleQuotedString()
+	case isJSON5NumberStart(c) && iter.cfg.allowJSON5Numbers:
+		iter.unreadByte()
+		iter.readJSON5Number()
+	default:
+		return false
+	}
+	return true
+}
+
+// readJSON5Number reads a number of JSON5 and returns it as a number of JSON,
+// or as Infinity, -Infinity or NaN which strconv.ParseFloat reads.
+func (iter *Iterator) readJSON5Number() string {
+	iter.nextToken()
+	iter.unreadByte()
+	var token []byte
+load_loop:
+	for {
+		for i := iter.head; i < iter.tail; i++ {
+			c := iter.buf[i]
+			if c == '+' || c == '-' || c == '.' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+				token = append(token, c)
+				continue
+			}
+			iter.head = i
+			break load_loop
+		}
+		iter.head = iter.tail
+		if !iter.loadMore() {
+			break
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF {
+		return ""
+	}
+	str := *(*string)(unsafe.Pointer(&token))
+	sign := ""
+	if len(str) > 0 && (str[0] == '+' || str[0] == '-') {
+		if str[0] == '-' {
+			sign = "-"
+		}
+		str = str[1:]
+	}
+	switch {
+	case str == "Infinity":
+		return sign + str
+	case str == "NaN":
+		return str
+	case strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X"):
+		val, err := strconv.ParseUint(str[2:], 16, 64)
+		if err != nil || strings.IndexByte(str, '_') != -1 {
+			iter.ReportError("readJSON5Number", "invalid hexadecimal number: "+sign+str)
+			return ""
+		}
+		return sign + strconv.FormatUint(val, 10)
+	}
+	if strings.HasPrefix(str, ".") {
+		str = "0" + str
+	}
+	if dot := strings.IndexByte(str, '.'); dot != -1 && (dot == len(str)-1 || str[dot+1] == 'e' || str[dot+1] == 'E') {
+		str = str[:dot] + str[dot+1:]
+	}
+	if str == "" || validateScalar([]byte(str), 0) != len(str) || str[0] < '0' || str[0] > '9' {
+		iter.ReportError("readJSON5Number", "invalid number: "+string(token))
+		return ""
+	}
+	return sign + str
+}
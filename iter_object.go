@@ -16,8 +16,11 @@ func (iter *Iterator) ReadObject() (ret string) {
 		return "" // null
 	case '{':
 		c = iter.nextToken()
-		if c == '"' {
+		if c == '"' || iter.cfg.relaxed && iter.isRelaxedKeyStart(c) {
 			iter.unreadByte()
+			if !iter.openPulled() {
+				return ""
+			}
 			field := iter.ReadString()
 			c = iter.nextToken()
 			if c != ':' {
@@ -26,11 +29,17 @@ func (iter *Iterator) ReadObject() (ret string) {
 			return field
 		}
 		if c == '}' {
+			if iter.incrementDepth() {
+				iter.decrementDepth()
+			}
 			return "" // end of object
 		}
 		iter.ReportError("ReadObject", `expect " after {, but found `+string([]byte{c}))
 		return
 	case ',':
+		if !iter.nextPulled("ReadObject", false) {
+			return ""
+		}
 		field := iter.ReadString()
 		c = iter.nextToken()
 		if c != ':' {
@@ -38,6 +47,7 @@ func (iter *Iterator) ReadObject() (ret string) {
 		}
 		return field
 	case '}':
+		iter.closePulled()
 		return "" // end of object
 	default:
 		iter.ReportError("ReadObject", fmt.Sprintf(`expect { or , or } or n, but found %s`, string([]byte{c})))
@@ -50,16 +60,31 @@ func (iter *Iterator) readFieldHash() int64 {
 	hash := int64(0x811c9dc5)
 	c := iter.nextToken()
 	if c != '"' {
+		if iter.cfg.relaxed && iter.isRelaxedKeyStart(c) {
+			iter.unreadByte()
+			hash = calcHash(iter.ReadString(), iter.cfg.caseSensitive)
+			if c = iter.nextToken(); c != ':' {
+				iter.ReportError("readFieldHash", `expect :, but found `+string([]byte{c}))
+				return 0
+			}
+			return hash
+		}
 		iter.ReportError("readFieldHash", `expect ", but found `+string([]byte{c}))
 		return 0
 	}
+	length := 0
 	for {
 		for i := iter.head; i < iter.tail; i++ {
 			// require ascii string and no escape
 			b := iter.buf[i]
 			if b == '\\' {
+				length += i - iter.head
 				iter.head = i
-				for _, b := range iter.readStringSlowPath() {
+				escaped := iter.readStringSlowPath()
+				if !iter.checkStringLength("readFieldHash", length+len(escaped)) {
+					return 0
+				}
+				for _, b := range escaped {
 					if 'A' <= b && b <= 'Z' && !iter.cfg.caseSensitive {
 						b += 'a' - 'A'
 					}
@@ -74,6 +99,9 @@ func (iter *Iterator) readFieldHash() int64 {
 				return hash
 			}
 			if b == '"' {
+				if !iter.checkStringLength("readFieldHash", length+i-iter.head) {
+					return 0
+				}
 				iter.head = i + 1
 				c = iter.nextToken()
 				if c != ':' {
@@ -88,6 +116,10 @@ func (iter *Iterator) readFieldHash() int64 {
 			hash ^= int64(b)
 			hash *= 0x1000193
 		}
+		length += iter.tail - iter.head
+		if !iter.checkStringLength("readFieldHash", length) {
+			return 0
+		}
 		if !iter.loadMore() {
 			iter.ReportError("readFieldHash", `incomplete field name`)
 			return 0
@@ -111,30 +143,41 @@ func calcHash(str string, caseSensitive bool) int64 {
 func (iter *Iterator) ReadObjectCB(callback func(*Iterator, string) bool) bool {
 	c := iter.nextToken()
 	var field string
+	var seen objectKeys
 	if c == '{' {
 		if !iter.incrementDepth() {
 			return false
 		}
 		c = iter.nextToken()
-		if c == '"' {
+		if c == '"' || iter.cfg.relaxed && iter.isRelaxedKeyStart(c) {
 			iter.unreadByte()
 			field = iter.ReadString()
 			c = iter.nextToken()
 			if c != ':' {
 				iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
 			}
-			if !callback(iter, field) {
+			if iter.cfg.duplicateKeys != DuplicateKeysLastWins && iter.duplicateKey("ReadObjectCB", &seen, field, field) {
+				iter.Skip()
+			} else if !callback(iter, field) {
 				iter.decrementDepth()
 				return false
 			}
+			keys := 1
 			c = iter.nextToken()
 			for c == ',' {
+				keys++
+				if !iter.checkObjectKeys("ReadObjectCB", keys) {
+					iter.decrementDepth()
+					return false
+				}
 				field = iter.ReadString()
 				c = iter.nextToken()
 				if c != ':' {
 					iter.ReportError("ReadObject", "expect : after object field, but found "+string([]byte{c}))
 				}
-				if !callback(iter, field) {
+				if iter.cfg.duplicateKeys != DuplicateKeysLastWins && iter.duplicateKey("ReadObjectCB", &seen, field, field) {
+					iter.Skip()
+				} else if !callback(iter, field) {
 					iter.decrementDepth()
 					return false
 				}
@@ -165,12 +208,13 @@ func (iter *Iterator) ReadObjectCB(callback func(*Iterator, string) bool) bool {
 // ReadMapCB read map with callback, the key can be any string
 func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 	c := iter.nextToken()
+	var seen objectKeys
 	if c == '{' {
 		if !iter.incrementDepth() {
 			return false
 		}
 		c = iter.nextToken()
-		if c == '"' {
+		if c == '"' || iter.cfg.relaxed && iter.isRelaxedKeyStart(c) {
 			iter.unreadByte()
 			field := iter.ReadString()
 			if iter.nextToken() != ':' {
@@ -178,19 +222,29 @@ func (iter *Iterator) ReadMapCB(callback func(*Iterator, string) bool) bool {
 				iter.decrementDepth()
 				return false
 			}
-			if !callback(iter, field) {
+			if iter.cfg.duplicateKeys != DuplicateKeysLastWins && iter.duplicateKey("ReadMapCB", &seen, field, field) {
+				iter.Skip()
+			} else if !callback(iter, field) {
 				iter.decrementDepth()
 				return false
 			}
+			keys := 1
 			c = iter.nextToken()
 			for c == ',' {
+				keys++
+				if !iter.checkObjectKeys("ReadMapCB", keys) {
+					iter.decrementDepth()
+					return false
+				}
 				field = iter.ReadString()
 				if iter.nextToken() != ':' {
 					iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
 					iter.decrementDepth()
 					return false
 				}
-				if !callback(iter, field) {
+				if iter.cfg.duplicateKeys != DuplicateKeysLastWins && iter.duplicateKey("ReadMapCB", &seen, field, field) {
+					iter.Skip()
+				} else if !callback(iter, field) {
 					iter.decrementDepth()
 					return false
 				}
@@ -71,6 +71,9 @@ func (iter *Iterator) stopCapture() []byte {
 // Skip skips a json object and positions to relatively the next json object
 func (iter *Iterator) Skip() {
 	c := iter.nextToken()
+	if iter.cfg.strict && iter.skipStrictValue(c) {
+		return
+	}
 	switch c {
 	case '"':
 		iter.skipString()
@@ -86,15 +89,45 @@ func (iter *Iterator) Skip() {
 	case '-', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		iter.skipNumber()
 	case '[':
-		iter.skipArray()
+		if iter.cfg.skipsByReading {
+			iter.skipElements()
+		} else if !iter.skipByIndex() {
+			iter.skipArray()
+		}
 	case '{':
-		iter.skipObject()
+		if iter.cfg.skipsByReading {
+			iter.skipFields()
+		} else if !iter.skipByIndex() {
+			iter.skipObject()
+		}
 	default:
+		if iter.cfg.relaxed && iter.skipRelaxedValue(c) {
+			return
+		}
 		iter.ReportError("Skip", fmt.Sprintf("do not know how to skip: %v", c))
 		return
 	}
 }
 
+// skipElements skips the array whose [ was just read by skipping each of its elements,
+// so that the keys of the objects within are all read, and the values of JSON5 by their readers.
+func (iter *Iterator) skipElements() {
+	iter.unreadByte()
+	iter.ReadArrayCB(func(iter *Iterator) bool {
+		iter.Skip()
+		return true
+	})
+}
+
+// skipFields skips the object whose { was just read by skipping each of its fields.
+func (iter *Iterator) skipFields() {
+	iter.unreadByte()
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		iter.Skip()
+		return true
+	})
+}
+
 func (iter *Iterator) skipFourBytes(b1, b2, b3, b4 byte) {
 	if iter.readByte() != b1 {
 		iter.ReportError("skipFourBytes", fmt.Sprintf("expect %s", string([]byte{b1, b2, b3, b4})))
@@ -2,7 +2,9 @@
 
 package jsoniter
 
-// sloppy but faster implementation, do not validate the input json
+// sloppy but faster implementation, do not validate the input json.
+// The arrays and objects are scanned for their end, only counting their depth for Config.MaxDepth.
+// Skip reads them instead when the other limits are set, see frozenConfig.skipsByReading.
 
 func (iter *Iterator) skipNumber() {
 	for {
@@ -21,48 +23,20 @@ func (iter *Iterator) skipNumber() {
 }
 
 func (iter *Iterator) skipArray() {
-	level := 1
-	if !iter.incrementDepth() {
-		return
-	}
-	for {
-		for i := iter.head; i < iter.tail; i++ {
-			switch iter.buf[i] {
-			case '"': // If inside string, skip it
-				iter.head = i + 1
-				iter.skipString()
-				i = iter.head - 1 // it will be i++ soon
-			case '[': // If open symbol, increase level
-				level++
-				if !iter.incrementDepth() {
-					return
-				}
-			case ']': // If close symbol, increase level
-				level--
-				if !iter.decrementDepth() {
-					return
-				}
-
-				// If we have returned to the original level, we're done
-				if level == 0 {
-					iter.head = i + 1
-					return
-				}
-			}
-		}
-		if !iter.loadMore() {
-			iter.ReportError("skipObject", "incomplete array")
-			return
-		}
-	}
+	iter.skipContainer()
 }
 
 func (iter *Iterator) skipObject() {
+	iter.skipContainer()
+}
+
+// skipContainer skips the array or object whose [ or { was just read, to the matching ] or }.
+// Both kinds of brackets are counted, for the depth of an array within an object and the other way.
+func (iter *Iterator) skipContainer() {
 	level := 1
 	if !iter.incrementDepth() {
 		return
 	}
-
 	for {
 		for i := iter.head; i < iter.tail; i++ {
 			switch iter.buf[i] {
@@ -70,12 +44,12 @@ func (iter *Iterator) skipObject() {
 				iter.head = i + 1
 				iter.skipString()
 				i = iter.head - 1 // it will be i++ soon
-			case '{': // If open symbol, increase level
+			case '[', '{': // If open symbol, increase level
 				level++
 				if !iter.incrementDepth() {
 					return
 				}
-			case '}': // If close symbol, increase level
+			case ']', '}': // If close symbol, decrease level
 				level--
 				if !iter.decrementDepth() {
 					return
@@ -89,13 +63,19 @@ func (iter *Iterator) skipObject() {
 			}
 		}
 		if !iter.loadMore() {
-			iter.ReportError("skipObject", "incomplete object")
+			iter.ReportError("skipContainer", "incomplete array or object")
 			return
 		}
 	}
 }
 
 func (iter *Iterator) skipString() {
+	if iter.cfg.maxStringLength > 0 {
+		// the string is read to know its length once unescaped
+		iter.unreadByte()
+		iter.ReadString()
+		return
+	}
 	for {
 		end, escaped := iter.findStringEnd()
 		if end == -1 {
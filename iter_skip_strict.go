@@ -39,6 +39,9 @@ func (iter *Iterator) trySkipNumber() bool {
 			switch c {
 			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 			default:
+				if iter.cfg.allowJSON5Numbers {
+					return false // a trailing dot of JSON5, read by ReadFloat64
+				}
 				iter.ReportError("validateNumber", `missing digit after dot`)
 				return true // already failed
 			}
@@ -69,6 +72,9 @@ func (iter *Iterator) trySkipString() bool {
 	for i := iter.head; i < iter.tail; i++ {
 		c := iter.buf[i]
 		if c == '"' {
+			if !iter.checkStringLength("trySkipString", i-iter.head) {
+				return true // already failed
+			}
 			iter.head = i + 1
 			return true // valid
 		} else if c == '\\' {
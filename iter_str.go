@@ -12,8 +12,14 @@ func (iter *Iterator) ReadString() (ret string) {
 		for i := iter.head; i < iter.tail; i++ {
 			c := iter.buf[i]
 			if c == '"' {
+				if !iter.checkStringLength("ReadString", i-iter.head) {
+					return
+				}
 				ret = string(iter.buf[iter.head:i])
 				iter.head = i + 1
+				if iter.cfg.strict && !iter.checkStrictString("ReadString", ret) {
+					return ""
+				}
 				return ret
 			} else if c == '\\' {
 				break
@@ -25,8 +31,15 @@ func (iter *Iterator) ReadString() (ret string) {
 		}
 		return iter.readStringSlowPath()
 	} else if c == 'n' {
+		if iter.cfg.allowUnquotedKeys {
+			return iter.readUnquotedKey(c)
+		}
 		iter.skipThreeBytes('u', 'l', 'l')
 		return ""
+	} else if c == '\'' && iter.cfg.allowSingleQuotes {
+		return iter.readSingleQuotedString()
+	} else if isIdentifierStart(c) && iter.cfg.allowUnquotedKeys {
+		return iter.readUnquotedKey(c)
 	}
 	iter.ReportError("ReadString", `expects " or n, but found `+string([]byte{c}))
 	return
@@ -38,14 +51,25 @@ func (iter *Iterator) readStringSlowPath() (ret string) {
 	for iter.Error == nil {
 		c = iter.readByte()
 		if c == '"' {
-			return string(str)
+			ret = string(str)
+			if iter.cfg.strict && !iter.checkStrictString("readStringSlowPath", ret) {
+				return ""
+			}
+			return ret
 		}
 		if c == '\\' {
 			c = iter.readByte()
 			str = iter.readEscapedChar(c, str)
+		} else if c < ' ' && iter.cfg.strict && iter.Error == nil {
+			iter.ReportError("readStringSlowPath",
+				fmt.Sprintf(`invalid control character found: %d`, c))
+			return
 		} else {
 			str = append(str, c)
 		}
+		if !iter.checkStringLength("readStringSlowPath", len(str)) {
+			return
+		}
 	}
 	iter.ReportError("readStringSlowPath", "unexpected end of input")
 	return
@@ -55,6 +79,9 @@ func (iter *Iterator) readEscapedChar(c byte, str []byte) []byte {
 	switch c {
 	case 'u':
 		r := iter.readU4()
+		if utf16.IsSurrogate(r) && iter.cfg.strict {
+			return iter.readSurrogatePair(r, str)
+		}
 		if utf16.IsSurrogate(r) {
 			c = iter.readByte()
 			if iter.Error != nil {
@@ -104,6 +131,11 @@ func (iter *Iterator) readEscapedChar(c byte, str []byte) []byte {
 	case 't':
 		str = append(str, '\t')
 	default:
+		if iter.cfg.allowSingleQuotes {
+			if relaxed, ok := iter.readRelaxedEscapedChar(c, str); ok {
+				return relaxed
+			}
+		}
 		iter.ReportError("readEscapedChar",
 			`invalid escape char after \`)
 		return nil
@@ -120,6 +152,9 @@ func (iter *Iterator) ReadStringAsSlice() (ret []byte) {
 			// require ascii string and no escape
 			// for: field name, base64, number
 			if iter.buf[i] == '"' {
+				if !iter.checkStringLength("ReadStringAsSlice", i-iter.head) {
+					return
+				}
 				// fast path: reuse the underlying buffer
 				ret = iter.buf[iter.head:i]
 				iter.head = i + 1
@@ -127,6 +162,9 @@ func (iter *Iterator) ReadStringAsSlice() (ret []byte) {
 			}
 		}
 		readLen := iter.tail - iter.head
+		if !iter.checkStringLength("ReadStringAsSlice", readLen) {
+			return
+		}
 		copied := make([]byte, readLen, readLen*2)
 		copy(copied, iter.buf[iter.head:iter.tail])
 		iter.head = iter.tail
@@ -136,8 +174,13 @@ func (iter *Iterator) ReadStringAsSlice() (ret []byte) {
 				return copied
 			}
 			copied = append(copied, c)
+			if !iter.checkStringLength("ReadStringAsSlice", len(copied)) {
+				return
+			}
 		}
 		return copied
+	} else if c == '\'' && iter.cfg.allowSingleQuotes {
+		return []byte(iter.readSingleQuotedString())
 	}
 	iter.ReportError("ReadStringAsSlice", `expects " or n, but found `+string([]byte{c}))
 	return
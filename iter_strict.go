@@ -0,0 +1,65 @@
+package jsoniter
+
+import (
+	"io"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The checks of Config.Strict, which reads only what RFC 8259 allows. Each reader of the Iterator
+// checks cfg.strict before calling them, JSON is read as fast as before without it.
+
+// skipStrictValue skips the value starting with c, just read, if it is a string or number,
+// which the other skips do not fully check. It tells if it did. The arrays and objects are
+// skipped value by value, as with DuplicateKeysError which Strict sets.
+func (iter *Iterator) skipStrictValue(c byte) bool {
+	switch c {
+	case '"':
+		iter.unreadByte()
+		iter.ReadString()
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		iter.unreadByte()
+		iter.readStrictNumber()
+	default:
+		return false
+	}
+	return true
+}
+
+// readStrictNumber reads a number, reporting it unless of the form
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func (iter *Iterator) readStrictNumber() string {
+	iter.nextToken()
+	iter.unreadByte()
+	str := iter.readNumberChars()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return ""
+	}
+	if str == "" || str[0] != '-' && (str[0] < '0' || str[0] > '9') || validateScalar([]byte(str), 0) != len(str) {
+		iter.ReportError("readStrictNumber", "invalid number: "+str)
+		return ""
+	}
+	return str
+}
+
+// checkStrictString reports str, just read, unless it is valid UTF-8.
+func (iter *Iterator) checkStrictString(operation string, str string) bool {
+	if utf8.ValidString(str) {
+		return true
+	}
+	iter.ReportError(operation, "invalid UTF-8 in string")
+	return false
+}
+
+// readSurrogatePair appends the character of the surrogate pair whose \u escape r was just read,
+// reporting r when it is not the first half of a pair followed by the second.
+func (iter *Iterator) readSurrogatePair(r rune, str []byte) []byte {
+	if r < 0xdc00 && iter.readByte() == '\\' && iter.readByte() == 'u' {
+		if combined := utf16.DecodeRune(r, iter.readU4()); combined != unicode.ReplacementChar {
+			return appendRune(str, combined)
+		}
+	}
+	iter.ReportError("readEscapedChar", "lone surrogate in string")
+	return nil
+}
@@ -0,0 +1,348 @@
+package jsoniter
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sort"
+)
+
+// structuralIndexMinBytes is the size of the smallest input worth a structural index
+const structuralIndexMinBytes = 16 * 1024
+
+// structuralIndex locates the structure of a whole JSON input, found in two stages in the spirit
+// of simdjson. Stage one flags the structural bytes of each block of 64 bytes at once, eight bytes
+// at a time. Stage two checks them against the JSON grammar, validating the strings, numbers and
+// literals, and matches the ends of the arrays and objects with their starts, so that skipping
+// any of them is a lookup. An input failing stage two gets no index, the reads report its errors.
+type structuralIndex struct {
+	// positions of { } [ ] : , of the opening quote of the strings and of the first byte of the other values
+	positions []uint32
+	// matches gives, for the { and [ of positions, the index in positions of their } or ]
+	matches []uint32
+	// depth is the deepest nesting of arrays and objects
+	depth int
+}
+
+// buildStructuralIndex indexes the input of iter when Config.UseStructuralIndex is set and the
+// whole input is in the buffer. Building the index costs more than scanning the input once,
+// so it is built for the inputs read more than once: the arrays and objects of Any read again.
+func (iter *Iterator) buildStructuralIndex() {
+	if iter.cfg.useStructuralIndex && iter.reader == nil && iter.tail >= structuralIndexMinBytes {
+		iter.structural = newStructuralIndex(iter.buf[:iter.tail], iter.cfg.maxDepth)
+		iter.structuralBase = 0
+	}
+}
+
+// useStructuralIndex gives iter the index of a larger input, its buffer being a copy of it from base.
+func (iter *Iterator) useStructuralIndex(index *structuralIndex, base int) {
+	iter.structural = index
+	iter.structuralBase = base
+}
+
+func (iter *Iterator) resetStructuralIndex() {
+	iter.structural = nil
+	iter.structuralBase = 0
+}
+
+// skipByIndex skips the array or object whose first byte was just read, telling if the index could.
+func (iter *Iterator) skipByIndex() bool {
+	index := iter.structural
+	if index == nil || iter.depth+index.depth > iter.cfg.maxDepth {
+		return false
+	}
+	start := uint32(iter.head - 1 + iter.structuralBase)
+	k := sort.Search(len(index.positions), func(i int) bool {
+		return index.positions[i] >= start
+	})
+	if k == len(index.positions) || index.positions[k] != start {
+		return false
+	}
+	iter.head = int(index.positions[index.matches[k]]) - iter.structuralBase + 1
+	return true
+}
+
+func newStructuralIndex(buf []byte, maxDepth int) *structuralIndex {
+	if uint64(len(buf)) > uint64(^uint32(0)) {
+		return nil
+	}
+	index := &structuralIndex{}
+	if !index.findStructurals(buf) || !index.validate(buf, maxDepth) {
+		return nil
+	}
+	return index
+}
+
+const (
+	swarOnes  = 0x0101010101010101
+	swarLows  = 0x7f7f7f7f7f7f7f7f
+	swarHighs = 0x8080808080808080
+)
+
+// swarEqual sets the high bit of the bytes of word equal to c.
+func swarEqual(word uint64, c byte) uint64 {
+	x := word ^ (swarOnes * uint64(c))
+	return ^(((x & swarLows) + swarLows) | x | swarLows)
+}
+
+// swarLess sets the high bit of the bytes of word less than c, c being at most 0x80.
+func swarLess(word uint64, c byte) uint64 {
+	return ^(((word & swarLows) + swarOnes*uint64(0x80-c)) | word) & swarHighs
+}
+
+// swarMask gathers the high bits of the bytes of word, the first byte giving the lowest bit.
+func swarMask(word uint64) uint64 {
+	return ((word >> 7) * 0x0102040810204080) >> 56
+}
+
+// prefixXor sets each bit to the xor of the bits up to it.
+func prefixXor(x uint64) uint64 {
+	x ^= x << 1
+	x ^= x << 2
+	x ^= x << 4
+	x ^= x << 8
+	x ^= x << 16
+	x ^= x << 32
+	return x
+}
+
+// findStructurals is stage one. It tells if the strings are all closed.
+func (index *structuralIndex) findStructurals(buf []byte) bool {
+	index.positions = make([]uint32, 0, len(buf)/8)
+	var padded [64]byte
+	// carried from a block to the next
+	var prevEscaped bool
+	var prevInString, prevOther uint64
+	for offset := 0; offset < len(buf); offset += 64 {
+		block := buf[offset:]
+		if len(block) < 64 {
+			copy(padded[:], block)
+			for i := len(block); i < 64; i++ {
+				padded[i] = ' '
+			}
+			block = padded[:]
+		}
+		var quotes, backslashes, structurals, whitespaces uint64
+		for w := uint(0); w < 8; w++ {
+			word := binary.LittleEndian.Uint64(block[w*8:])
+			quotes |= swarMask(swarEqual(word, '"')) << (w * 8)
+			backslashes |= swarMask(swarEqual(word, '\\')) << (w * 8)
+			structurals |= swarMask(swarEqual(word, '{')|swarEqual(word, '}')|swarEqual(word, '[')|
+				swarEqual(word, ']')|swarEqual(word, ':')|swarEqual(word, ',')) << (w * 8)
+			whitespaces |= swarMask(swarEqual(word, ' ')|swarEqual(word, '\t')|
+				swarEqual(word, '\n')|swarEqual(word, '\r')) << (w * 8)
+		}
+		var escaped uint64
+		if prevEscaped {
+			escaped = 1
+			backslashes &^= 1
+		}
+		prevEscaped = false
+		for backslashes != 0 {
+			i := uint(bits.TrailingZeros64(backslashes))
+			backslashes &^= 1 << i
+			if i == 63 {
+				prevEscaped = true
+				break
+			}
+			escaped |= 1 << (i + 1)
+			backslashes &^= 1 << (i + 1)
+		}
+		quotes &^= escaped
+		inString := prefixXor(quotes) ^ prevInString
+		prevInString = uint64(int64(inString) >> 63)
+		other := ^(whitespaces | structurals | quotes) &^ inString
+		starts := other &^ (other<<1 | prevOther)
+		prevOther = other >> 63
+		flagged := structurals&^inString | quotes&inString | starts
+		for flagged != 0 {
+			index.positions = append(index.positions, uint32(offset+bits.TrailingZeros64(flagged)))
+			flagged &= flagged - 1
+		}
+	}
+	return prevInString == 0
+}
+
+// the states of stage two
+const (
+	structuralValue = iota
+	structuralValueOrEnd
+	structuralKey
+	structuralKeyOrEnd
+	structuralColon
+	structuralCommaOrEnd
+)
+
+// validate is stage two.
+func (index *structuralIndex) validate(buf []byte, maxDepth int) bool {
+	positions := index.positions
+	index.matches = make([]uint32, len(positions))
+	stack := []uint32{}
+	state := structuralValue
+	for k, pos := range positions {
+		c := buf[pos]
+		switch c {
+		case '{', '[':
+			if state != structuralValue && state != structuralValueOrEnd {
+				return false
+			}
+			stack = append(stack, uint32(k))
+			if len(stack) > maxDepth {
+				return false
+			}
+			if len(stack) > index.depth {
+				index.depth = len(stack)
+			}
+			if c == '{' {
+				state = structuralKeyOrEnd
+			} else {
+				state = structuralValueOrEnd
+			}
+		case '}', ']':
+			// { and [ are two bytes before } and ]
+			if len(stack) == 0 || buf[positions[stack[len(stack)-1]]] != c-2 {
+				return false
+			}
+			if state != structuralCommaOrEnd &&
+				!(c == '}' && state == structuralKeyOrEnd) && !(c == ']' && state == structuralValueOrEnd) {
+				return false
+			}
+			index.matches[stack[len(stack)-1]] = uint32(k)
+			stack = stack[:len(stack)-1]
+			state = structuralCommaOrEnd
+		case ':':
+			if state != structuralColon {
+				return false
+			}
+			state = structuralValue
+		case ',':
+			if state != structuralCommaOrEnd || len(stack) == 0 {
+				return false
+			}
+			if buf[positions[stack[len(stack)-1]]] == '{' {
+				state = structuralKey
+			} else {
+				state = structuralValue
+			}
+		case '"':
+			end := validateString(buf, int(pos))
+			if end < 0 || (k+1 < len(positions) && int(positions[k+1]) < end) {
+				return false
+			}
+			switch state {
+			case structuralKey, structuralKeyOrEnd:
+				state = structuralColon
+			case structuralValue, structuralValueOrEnd:
+				state = structuralCommaOrEnd
+			default:
+				return false
+			}
+		default:
+			if state != structuralValue && state != structuralValueOrEnd {
+				return false
+			}
+			end := validateScalar(buf, int(pos))
+			if end < 0 {
+				return false
+			}
+			if end < len(buf) {
+				switch buf[end] {
+				case ' ', '\t', '\n', '\r', ',', ':', '{', '}', '[', ']', '"':
+				default:
+					return false
+				}
+			}
+			state = structuralCommaOrEnd
+		}
+	}
+	return state == structuralCommaOrEnd && len(stack) == 0
+}
+
+// validateString returns the end of the string starting at buf[pos], -1 if it is not valid.
+func validateString(buf []byte, pos int) int {
+	i := pos + 1
+	for {
+		for i+8 <= len(buf) {
+			word := binary.LittleEndian.Uint64(buf[i:])
+			if swarEqual(word, '"')|swarEqual(word, '\\')|swarLess(word, ' ') != 0 {
+				break
+			}
+			i += 8
+		}
+		if i >= len(buf) {
+			return -1
+		}
+		c := buf[i]
+		switch {
+		case c == '"':
+			return i + 1
+		case c == '\\':
+			if i+1 >= len(buf) {
+				return -1
+			}
+			switch buf[i+1] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				i += 2
+			case 'u':
+				if i+6 > len(buf) {
+					return -1
+				}
+				for _, h := range buf[i+2 : i+6] {
+					if hexDigits[h] == 255 {
+						return -1
+					}
+				}
+				i += 6
+			default:
+				return -1
+			}
+		case c < ' ':
+			return -1
+		default:
+			i++
+		}
+	}
+}
+
+// validateScalar returns the end of the number or literal starting at buf[pos], -1 if it is not valid.
+func validateScalar(buf []byte, pos int) int {
+	for _, literal := range [...]string{"true", "false", "null"} {
+		if buf[pos] == literal[0] {
+			if len(buf)-pos < len(literal) || string(buf[pos:pos+len(literal)]) != literal {
+				return -1
+			}
+			return pos + len(literal)
+		}
+	}
+	i := pos
+	if buf[i] == '-' {
+		i++
+	}
+	digits := func() int {
+		start := i
+		for i < len(buf) && buf[i] >= '0' && buf[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if i < len(buf) && buf[i] == '0' {
+		i++
+	} else if digits() == 0 {
+		return -1
+	}
+	if i < len(buf) && buf[i] == '.' {
+		i++
+		if digits() == 0 {
+			return -1
+		}
+	}
+	if i < len(buf) && (buf[i] == 'e' || buf[i] == 'E') {
+		i++
+		if i < len(buf) && (buf[i] == '+' || buf[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return -1
+		}
+	}
+	return i
+}
@@ -0,0 +1,131 @@
+package jsoniter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// naiveStructurals finds the positions of the structural index byte by byte.
+func naiveStructurals(buf []byte) []uint32 {
+	positions := []uint32{}
+	inString, escaped, prevOther := false, false, false
+	for i, c := range buf {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		other := false
+		switch c {
+		case '{', '}', '[', ']', ':', ',':
+			positions = append(positions, uint32(i))
+		case '"':
+			positions = append(positions, uint32(i))
+			inString = true
+		case ' ', '\t', '\n', '\r':
+		default:
+			other = true
+			if !prevOther {
+				positions = append(positions, uint32(i))
+			}
+		}
+		prevOther = other
+	}
+	return positions
+}
+
+func Test_swar(t *testing.T) {
+	should := require.New(t)
+	word := uint64(0)
+	input := []byte("a\"\\{ 0\x1f\xff")
+	for i := len(input) - 1; i >= 0; i-- {
+		word = word<<8 | uint64(input[i])
+	}
+	should.Equal(uint64(0x02), swarMask(swarEqual(word, '"')))
+	should.Equal(uint64(0x04), swarMask(swarEqual(word, '\\')))
+	should.Equal(uint64(0x52), swarMask(swarLess(word, '0')))
+	should.Equal(uint64(0x40), swarMask(swarLess(word, ' ')))
+	should.Equal(^uint64(0), prefixXor(1))
+	should.Equal(uint64(0x1e), prefixXor(0x22)&0xff)
+}
+
+func Test_structural_index(t *testing.T) {
+	should := require.New(t)
+	var sb strings.Builder
+	sb.WriteString(`{"items":[`)
+	for i := 0; i < 100; i++ {
+		if i != 0 {
+			sb.WriteString(",")
+		}
+		// the escapes and the strings cross the blocks of 64 bytes at many offsets
+		fmt.Fprintf(&sb, `{"id":%d,"name":"%s\"{[\\","ok":true,"n":null,"x":-1.5e3,"list":[]}`,
+			i, strings.Repeat("\\\\", i%7)+strings.Repeat("y", i%13))
+	}
+	sb.WriteString("]}")
+	input := []byte(sb.String())
+	index := newStructuralIndex(input, defaultMaxDepth)
+	should.NotNil(index)
+	should.Equal(naiveStructurals(input), index.positions)
+	should.Equal(4, index.depth)
+	should.Equal(uint32(len(index.positions)-1), index.matches[0])
+	should.Equal(byte(']'), input[index.positions[index.matches[3]]])
+
+	for _, input := range []string{
+		`{"a":1,}`, `[1 2]`, `{"a" 1}`, `[1]]`, `[1}`, `"abc`, `[01]`, `[1.]`, `[tru]`, `[nulll]`,
+		`{"a":"\x"}`, "[\"\t\"]", `1 2`, `[1,]`, `{1:2}`, `[-]`, `[1e]`, `{"a":1}x`,
+	} {
+		should.Nil(newStructuralIndex([]byte(input), defaultMaxDepth), input)
+	}
+	should.Nil(newStructuralIndex([]byte(`[[[]]]`), 2))
+	should.NotNil(newStructuralIndex([]byte(` [ "é\\" , -0 , 1E+2 , {} ] `), defaultMaxDepth))
+}
+
+func Test_skip_by_structural_index(t *testing.T) {
+	should := require.New(t)
+	element := `{"a":[1,"]"]}`
+	input := []byte(`[` + strings.Repeat(element+`,`, 2000) + `{}]`)
+	iter := ParseBytes(Config{UseStructuralIndex: true}.Froze(), input)
+	should.True(iter.ReadArray())
+	iter.Skip()
+	// reading the input once does not build the index
+	should.Nil(iter.structural)
+	should.Equal(1+len(element), iter.head)
+	iter.buildStructuralIndex()
+	should.NotNil(iter.structural)
+	should.True(iter.ReadArray())
+	iter.Skip()
+	should.Equal(2+2*len(element), iter.head)
+	should.NoError(iter.Error)
+
+	iter = ParseBytes(Config{UseStructuralIndex: true, MaxObjectKeys: 10}.Froze(), input)
+	iter.buildStructuralIndex()
+	iter.Skip()
+	should.Nil(iter.structural)
+	should.NoError(iter.Error)
+	iter = ParseBytes(Config{UseStructuralIndex: true, MaxDepth: 2}.Froze(), input)
+	iter.buildStructuralIndex()
+	iter.Skip()
+	should.Nil(iter.structural)
+}
+
+func Test_structural_index_of_any_read_again(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`[` + strings.Repeat(`{"a":[1,"]"]},`, 2000) + `{"b":2}]`)
+	any := Config{UseStructuralIndex: true}.Froze().Get(input).(*arrayLazyAny)
+	should.Equal(1, any.Get(1, "a", 0).ToInt())
+	should.Nil(any.index)
+	should.Equal(2, any.Get(2000, "b").ToInt())
+	should.NotNil(any.index)
+	object := any.Get(1999).(*objectLazyAny)
+	should.True(any.index == object.index)
+	should.Equal("]", object.Get("a", 1).ToString())
+}
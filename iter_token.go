@@ -0,0 +1,180 @@
+package jsoniter
+
+import (
+	"encoding/json"
+)
+
+// Token holds a value of one of these types, as json.Token does:
+//
+//	Delim, for the four JSON delimiters [ ] { }
+//	bool, for JSON booleans
+//	float64, for JSON numbers, or json.Number with Config.UseNumber
+//	string, for JSON string literals
+//	nil, for JSON null
+type Token = json.Token
+
+// Delim is a JSON array or object delimiter, one of [ ] { or }.
+type Delim = json.Delim
+
+// the states of Token, following the JSON grammar as json.Decoder does
+const (
+	tokenTopValue = iota
+	tokenArrayStart
+	tokenArrayValue
+	tokenArrayComma
+	tokenObjectStart
+	tokenObjectKey
+	tokenObjectColon
+	tokenObjectValue
+	tokenObjectComma
+)
+
+func (iter *Iterator) resetTokens() {
+	iter.tokenState = tokenTopValue
+	iter.tokenStack = iter.tokenStack[:0]
+	iter.tokenDepth = 0
+}
+
+// Token returns the next JSON token in the input stream, nil at the end of the input
+// or on error, see iter.Error.
+// Commas and colons are checked and skipped. Values may be read with ReadVal in between,
+// where Token would return a value or the opening delimiter of one.
+func (iter *Iterator) Token() Token {
+	for {
+		c := iter.nextToken()
+		switch c {
+		case 0:
+			return nil
+		case '[', '{':
+			if !iter.tokenValueAllowed(c) || !iter.incrementDepth() {
+				return nil
+			}
+			iter.tokenStack = append(iter.tokenStack, iter.tokenState)
+			if c == '[' {
+				iter.tokenState = tokenArrayStart
+			} else {
+				iter.tokenState = tokenObjectStart
+			}
+			iter.tokenDepth = iter.depth
+			return Delim(c)
+		case ']', '}':
+			if (c == ']' && iter.tokenState != tokenArrayStart && iter.tokenState != tokenArrayComma) ||
+				(c == '}' && iter.tokenState != tokenObjectStart && iter.tokenState != tokenObjectComma) {
+				iter.ReportError("Token", "unexpected "+string([]byte{c}))
+				return nil
+			}
+			iter.tokenState = iter.tokenStack[len(iter.tokenStack)-1]
+			iter.tokenStack = iter.tokenStack[:len(iter.tokenStack)-1]
+			iter.decrementDepth()
+			iter.tokenDepth = iter.depth
+			iter.tokenValueEnd()
+			return Delim(c)
+		case ':':
+			if iter.tokenState != tokenObjectColon {
+				iter.ReportError("Token", "unexpected :")
+				return nil
+			}
+			iter.tokenState = tokenObjectValue
+		case ',':
+			switch iter.tokenState {
+			case tokenArrayComma:
+				iter.tokenState = tokenArrayValue
+			case tokenObjectComma:
+				iter.tokenState = tokenObjectKey
+			default:
+				iter.ReportError("Token", "unexpected ,")
+				return nil
+			}
+		default:
+			if (c == '"' || iter.cfg.relaxed && iter.isRelaxedKeyStart(c)) &&
+				(iter.tokenState == tokenObjectStart || iter.tokenState == tokenObjectKey) {
+				iter.unreadByte()
+				key := iter.ReadString()
+				iter.tokenState = tokenObjectColon
+				return key
+			}
+			if !iter.tokenValueAllowed(c) {
+				return nil
+			}
+			iter.unreadByte()
+			token := iter.readTokenValue()
+			iter.tokenValueEnd()
+			return token
+		}
+	}
+}
+
+// InputOffset returns the input stream byte offset of the current iterator position,
+// the end of the last token or value read.
+func (iter *Iterator) InputOffset() int64 {
+	return iter.offset()
+}
+
+func (iter *Iterator) tokenValueAllowed(c byte) bool {
+	switch iter.tokenState {
+	case tokenTopValue, tokenArrayStart, tokenArrayValue, tokenObjectValue:
+		return true
+	case tokenObjectStart, tokenObjectKey:
+		iter.ReportError("Token", "expect object key, but found "+string([]byte{c}))
+	case tokenObjectColon:
+		iter.ReportError("Token", "expect :, but found "+string([]byte{c}))
+	default:
+		iter.ReportError("Token", "expect , or end of container, but found "+string([]byte{c}))
+	}
+	return false
+}
+
+func (iter *Iterator) tokenValueEnd() {
+	switch iter.tokenState {
+	case tokenArrayStart, tokenArrayValue:
+		iter.tokenState = tokenArrayComma
+	case tokenObjectValue:
+		iter.tokenState = tokenObjectComma
+	}
+}
+
+// tokenPrepareForDecode skips the comma or colon before a value read between tokens.
+func (iter *Iterator) tokenPrepareForDecode() bool {
+	switch iter.tokenState {
+	case tokenArrayComma:
+		if c := iter.nextToken(); c != ',' {
+			iter.ReportError("Token", "expect , before array element, but found "+string([]byte{c}))
+			return false
+		}
+		iter.tokenState = tokenArrayValue
+	case tokenObjectColon:
+		if c := iter.nextToken(); c != ':' {
+			iter.ReportError("Token", "expect : before object value, but found "+string([]byte{c}))
+			return false
+		}
+		iter.tokenState = tokenObjectValue
+	case tokenObjectStart, tokenObjectKey, tokenObjectComma:
+		iter.ReportError("Token", "expect object key, not a value")
+		return false
+	}
+	return true
+}
+
+// isTokenLevel tells if values are read between tokens, rather than inside a decoder.
+func (iter *Iterator) isTokenLevel() bool {
+	return iter.tokenState != tokenTopValue && iter.depth == iter.tokenDepth
+}
+
+func (iter *Iterator) readTokenValue() Token {
+	switch iter.WhatIsNext() {
+	case StringValue:
+		return iter.ReadString()
+	case NumberValue:
+		if iter.cfg.configBeforeFrozen.UseNumber {
+			return iter.ReadNumber()
+		}
+		return iter.ReadFloat64()
+	case BoolValue:
+		return iter.ReadBool()
+	case NilValue:
+		iter.ReadNil()
+		return nil
+	}
+	iter.ReportError("Token", "expect value, but found "+string([]byte{iter.nextToken()}))
+	return nil
+}
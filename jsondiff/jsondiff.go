@@ -0,0 +1,225 @@
+// Package jsondiff lists the differences between two JSON documents, such as the expected and
+// actual payloads of a contract test.
+//
+// Objects are compared member by member whatever the order of their keys, arrays element by
+// element, and numbers by value: 1.0 is no change from 1. Each difference is a Change at a
+// RFC 6901 JSON Pointer, and Report renders them for a reader, laid out as a unified diff.
+package jsondiff
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/json-iterator/go"
+	"github.com/json-iterator/go/patch"
+)
+
+var cfg = jsoniter.ConfigDefault
+
+// Kind is what happened to a value.
+type Kind int
+
+const (
+	// Added is a value only in the second document.
+	Added Kind = iota
+	// Removed is a value only in the first document.
+	Removed
+	// Changed is a value which differs between the documents.
+	Changed
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "Kind(" + strconv.Itoa(int(kind)) + ")"
+}
+
+// Change is a difference at Path, a JSON Pointer.
+// Old is nil for an Added value, New for a Removed one.
+type Change struct {
+	Kind Kind
+	Path string
+	Old  jsoniter.Any
+	New  jsoniter.Any
+}
+
+func (change Change) String() string {
+	switch change.Kind {
+	case Added:
+		return fmt.Sprintf("added %s: %s", change.Path, compact(change.New))
+	case Removed:
+		return fmt.Sprintf("removed %s: %s", change.Path, compact(change.Old))
+	}
+	return fmt.Sprintf("changed %s: %s to %s", change.Path, compact(change.Old), compact(change.New))
+}
+
+// Options are the differences to tolerate.
+type Options struct {
+	// IgnorePaths are the JSON Pointers of the values not compared, with all they hold.
+	// A token * matches any key or index, such as in /items/*/updatedAt.
+	IgnorePaths []string
+	// FloatEpsilon is the greatest difference between two numbers read as the same.
+	FloatEpsilon float64
+}
+
+// Diff returns the changes turning the document a into b.
+func Diff(a, b []byte, options Options) ([]Change, error) {
+	var raw jsoniter.RawMessage
+	if err := cfg.Unmarshal(a, &raw); err != nil {
+		return nil, err
+	}
+	if err := cfg.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return DiffAny(cfg.Get(a), cfg.Get(b), options)
+}
+
+// DiffAny returns the changes turning the value a into b, or an error if either is nil or invalid.
+func DiffAny(a, b jsoniter.Any, options Options) ([]Change, error) {
+	for _, val := range []jsoniter.Any{a, b} {
+		if val == nil {
+			return nil, errors.New("nil value")
+		}
+		if val.ValueType() == jsoniter.InvalidValue {
+			return nil, fmt.Errorf("invalid value: %v", val.LastError())
+		}
+	}
+	differ := &differ{options: options, changes: []Change{}}
+	for _, ignored := range options.IgnorePaths {
+		tokens, err := patch.ParsePointer(ignored)
+		if err != nil {
+			return nil, err
+		}
+		differ.ignored = append(differ.ignored, tokens)
+	}
+	differ.diffValue([]string{}, a, b)
+	return differ.changes, nil
+}
+
+type differ struct {
+	options Options
+	ignored [][]string
+	changes []Change
+}
+
+func (differ *differ) diffValue(path []string, a, b jsoniter.Any) {
+	if differ.isIgnored(path) {
+		return
+	}
+	typeA, typeB := a.ValueType(), b.ValueType()
+	switch {
+	case typeA == jsoniter.ObjectValue && typeB == jsoniter.ObjectValue:
+		differ.diffObject(path, a, b)
+	case typeA == jsoniter.ArrayValue && typeB == jsoniter.ArrayValue:
+		differ.diffArray(path, a, b)
+	case typeA == jsoniter.NumberValue && typeB == jsoniter.NumberValue && differ.options.FloatEpsilon > 0:
+		if math.Abs(a.ToFloat64()-b.ToFloat64()) > differ.options.FloatEpsilon {
+			differ.add(Changed, path, a, b)
+		}
+	default:
+		if !jsoniter.Equal(a, b) {
+			differ.add(Changed, path, a, b)
+		}
+	}
+}
+
+func (differ *differ) diffObject(path []string, a, b jsoniter.Any) {
+	keysA, membersA := readMembers(a)
+	keysB, membersB := readMembers(b)
+	for _, key := range keysA {
+		if _, found := membersB[key]; !found {
+			if keyPath := child(path, key); !differ.isIgnored(keyPath) {
+				differ.add(Removed, keyPath, membersA[key], nil)
+			}
+		}
+	}
+	for _, key := range keysB {
+		keyPath := child(path, key)
+		valueA, found := membersA[key]
+		if !found {
+			if !differ.isIgnored(keyPath) {
+				differ.add(Added, keyPath, nil, membersB[key])
+			}
+			continue
+		}
+		differ.diffValue(keyPath, valueA, membersB[key])
+	}
+}
+
+func (differ *differ) diffArray(path []string, a, b jsoniter.Any) {
+	elementsA, elementsB := readElements(a), readElements(b)
+	for i := 0; i < len(elementsA) || i < len(elementsB); i++ {
+		indexPath := child(path, strconv.Itoa(i))
+		switch {
+		case i >= len(elementsB):
+			if !differ.isIgnored(indexPath) {
+				differ.add(Removed, indexPath, elementsA[i], nil)
+			}
+		case i >= len(elementsA):
+			if !differ.isIgnored(indexPath) {
+				differ.add(Added, indexPath, nil, elementsB[i])
+			}
+		default:
+			differ.diffValue(indexPath, elementsA[i], elementsB[i])
+		}
+	}
+}
+
+func (differ *differ) add(kind Kind, path []string, before, after jsoniter.Any) {
+	differ.changes = append(differ.changes, Change{kind, patch.FormatPointer(path...), before, after})
+}
+
+func (differ *differ) isIgnored(path []string) bool {
+	for _, ignored := range differ.ignored {
+		if len(ignored) != len(path) {
+			continue
+		}
+		matched := true
+		for i, token := range ignored {
+			if token != "*" && token != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// readMembers returns the keys of object in order, a repeated key once with its last value.
+func readMembers(object jsoniter.Any) ([]string, map[string]jsoniter.Any) {
+	keys := []string{}
+	members := map[string]jsoniter.Any{}
+	object.ForEach(func(key string, value jsoniter.Any) bool {
+		if _, found := members[key]; !found {
+			keys = append(keys, key)
+		}
+		members[key] = value
+		return true
+	})
+	return keys, members
+}
+
+func readElements(array jsoniter.Any) []jsoniter.Any {
+	elements := []jsoniter.Any{}
+	array.ForEachIndex(func(index int, element jsoniter.Any) bool {
+		elements = append(elements, element)
+		return true
+	})
+	return elements
+}
+
+// child returns a new path, never sharing the backing array of its parent.
+func child(path []string, token string) []string {
+	return append(path[:len(path):len(path)], token)
+}
@@ -0,0 +1,94 @@
+package jsondiff
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_diff(t *testing.T) {
+	should := require.New(t)
+	changes, err := Diff(
+		[]byte(`{"id":1,"name":"a","tags":["x","y","z"],"owner":{"id":7,"at":"t1"},"a/b":{"~c":1}}`),
+		[]byte(`{ "tags" : ["x","w"], "name" : "a", "id" : 1.0, "owner" : {"id":7,"at":"t2"}, "a/b":{"~c":[]}, "new":null }`),
+		Options{})
+	should.NoError(err)
+	texts := []string{}
+	for _, change := range changes {
+		texts = append(texts, change.String())
+	}
+	should.Equal([]string{
+		`changed /tags/1: "y" to "w"`,
+		`removed /tags/2: "z"`,
+		`changed /owner/at: "t1" to "t2"`,
+		`changed /a~1b/~0c: 1 to []`,
+		`added /new: null`,
+	}, texts)
+	should.Equal(Removed, changes[1].Kind)
+	should.Nil(changes[1].New)
+	should.Equal("z", changes[1].Old.ToString())
+
+	changes, err = Diff([]byte(`{"a":[1]}`), []byte(`"a"`), Options{})
+	should.NoError(err)
+	should.Equal([]Change{{Changed, "", changes[0].Old, changes[0].New}}, changes)
+	changes, err = Diff([]byte(`[{"a":1}]`), []byte(`[{"a":1.0}]`), Options{})
+	should.NoError(err)
+	should.Empty(changes)
+
+	_, err = Diff([]byte(`{}`), []byte(`{"a":`), Options{})
+	should.Error(err)
+	_, err = Diff([]byte(`{}`), []byte(`{}`), Options{IgnorePaths: []string{"a"}})
+	should.Error(err)
+	_, err = DiffAny(jsoniter.Get([]byte(`{}`), "a"), jsoniter.Get([]byte(`{}`)), Options{})
+	should.Error(err)
+}
+
+func Test_diff_options(t *testing.T) {
+	should := require.New(t)
+	a := []byte(`{"items":[{"id":1,"at":"t1","price":9.99},{"id":2,"at":"t1","price":5}],"at":"t1"}`)
+	b := []byte(`{"items":[{"id":1,"at":"t2","price":9.990001},{"id":2,"at":"t3","price":5.5}],"version":2}`)
+	changes, err := Diff(a, b, Options{IgnorePaths: []string{"/items/*/at", "/at", "/version"}, FloatEpsilon: 1e-3})
+	should.NoError(err)
+	should.Len(changes, 1)
+	should.Equal(`changed /items/1/price: 5 to 5.5`, changes[0].String())
+	changes, err = Diff(a, b, Options{IgnorePaths: []string{""}})
+	should.NoError(err)
+	should.Empty(changes)
+	changes, err = DiffAny(jsoniter.Get(a, "items", 0), jsoniter.Get(b, "items", 0), Options{})
+	should.NoError(err)
+	should.Len(changes, 2)
+}
+
+func Test_report(t *testing.T) {
+	should := require.New(t)
+	changes, err := Diff([]byte(`{"a":{"b":[1,2]},"c":true}`), []byte(`{"a":{"b":[1,{"d":[],"e":{}}]},"f":"g"}`), Options{})
+	should.NoError(err)
+	should.Equal(`--- expected
++++ actual
+@@ removed /c @@
+-true
+@@ changed /a/b/1 @@
+-2
++{
++  "d": [],
++  "e": {}
++}
+@@ added /f @@
++"g"
+`, Report(changes, "expected", "actual"))
+	changes, err = Diff([]byte(`[1]`), []byte(`[ 1 ]`), Options{})
+	should.NoError(err)
+	should.Equal("", Report(changes, "a", "b"))
+	changes, err = Diff([]byte(`1`), []byte(`[ 1, [ ] ]`), Options{})
+	should.NoError(err)
+	should.Equal("--- a\n+++ b\n@@ changed (root) @@\n-1\n+[\n+  1,\n+  []\n+]\n", Report(changes, "a", "b"))
+}
+
+func Test_diff_nil_value(t *testing.T) {
+	should := require.New(t)
+	_, err := DiffAny(nil, jsoniter.Get([]byte(`1`)), Options{})
+	should.Error(err)
+	_, err = DiffAny(jsoniter.Get([]byte(`1`)), nil, Options{})
+	should.Error(err)
+}
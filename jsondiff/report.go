@@ -0,0 +1,103 @@
+package jsondiff
+
+import (
+	"strings"
+
+	"github.com/json-iterator/go"
+)
+
+var indentedCfg = jsoniter.Config{IndentionStep: 2}.Froze()
+
+// Report renders changes for a reader, in the layout of a unified diff without being one: there
+// are no line numbers to apply it with. It starts with the lines "--- from" and "+++ to", then
+// each change has a header "@@ <kind> <path> @@", the path being (root) for the whole document,
+// followed by the old value indented on lines starting with - and the new one on lines starting
+// with +. It is empty without any change.
+func Report(changes []Change, from, to string) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("--- " + from + "\n+++ " + to + "\n")
+	for _, change := range changes {
+		path := change.Path
+		if path == "" {
+			path = "(root)"
+		}
+		builder.WriteString("@@ " + change.Kind.String() + " " + path + " @@\n")
+		if change.Old != nil {
+			writeLines(&builder, "-", format(indentedCfg, change.Old))
+		}
+		if change.New != nil {
+			writeLines(&builder, "+", format(indentedCfg, change.New))
+		}
+	}
+	return builder.String()
+}
+
+func writeLines(builder *strings.Builder, prefix string, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		builder.WriteString(prefix + line + "\n")
+	}
+}
+
+// compact returns val as JSON on one line, without the whitespaces of its input.
+func compact(val jsoniter.Any) string {
+	return format(cfg, val)
+}
+
+// format writes val again with api, as the Stream of api indents it.
+func format(api jsoniter.API, val jsoniter.Any) string {
+	raw, err := cfg.Marshal(val)
+	if err != nil {
+		return val.ToString()
+	}
+	iter := cfg.BorrowIterator(raw)
+	defer cfg.ReturnIterator(iter)
+	stream := api.BorrowStream(nil)
+	defer api.ReturnStream(stream)
+	writeValue(stream, iter)
+	return string(stream.Buffer())
+}
+
+func writeValue(stream *jsoniter.Stream, iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.ObjectValue:
+		empty := true
+		iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+			if empty {
+				stream.WriteObjectStart()
+			} else {
+				stream.WriteMore()
+			}
+			empty = false
+			stream.WriteObjectField(field)
+			writeValue(stream, iter)
+			return true
+		})
+		if empty {
+			stream.WriteEmptyObject()
+		} else {
+			stream.WriteObjectEnd()
+		}
+	case jsoniter.ArrayValue:
+		empty := true
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			if empty {
+				stream.WriteArrayStart()
+			} else {
+				stream.WriteMore()
+			}
+			empty = false
+			writeValue(stream, iter)
+			return true
+		})
+		if empty {
+			stream.WriteEmptyArray()
+		} else {
+			stream.WriteArrayEnd()
+		}
+	default:
+		stream.Write(iter.SkipAndReturnBytes())
+	}
+}
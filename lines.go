@@ -0,0 +1,231 @@
+package jsoniter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// NewLinesDecoder returns a decoder of JSON Lines, also known as NDJSON, read from reader.
+func NewLinesDecoder(reader io.Reader) *LinesDecoder {
+	return ConfigDefault.NewLinesDecoder(reader)
+}
+
+// NewLinesEncoder returns an encoder of JSON Lines, also known as NDJSON, written to writer.
+func NewLinesEncoder(writer io.Writer) *LinesEncoder {
+	return ConfigDefault.NewLinesEncoder(writer)
+}
+
+// LineError is the error reported for a line of JSON Lines which can not be decoded.
+// The line is skipped, the next one can still be decoded.
+type LineError struct {
+	// Line is the number of the line, starting at 1
+	Line int
+	// Err is the error reported while decoding the line, located within the line
+	Err error
+}
+
+func (err *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", err.Line, err.Err)
+}
+
+// Unwrap returns Err, for errors.Is and errors.As.
+func (err *LineError) Unwrap() error {
+	return err.Err
+}
+
+// LinesDecoder reads and decodes JSON Lines: one JSON value per line, the blank lines being skipped.
+// Each line is decoded on its own, so that a bad line does not stop the decoding of the next ones.
+// Config.MaxInputBytes limits the bytes of each line.
+type LinesDecoder struct {
+	reader *bufio.Reader
+	iter   *Iterator
+	// the current line when longer than the buffer of reader
+	long    []byte
+	line    int
+	err     error
+	onError func(err *LineError)
+}
+
+func (cfg *frozenConfig) NewLinesDecoder(reader io.Reader) *LinesDecoder {
+	return &LinesDecoder{
+		reader: bufio.NewReaderSize(reader, 4096),
+		iter:   NewIterator(cfg),
+	}
+}
+
+// Decode decodes the next line into obj. It returns a *LineError when the line can not be
+// decoded, io.EOF at the end of the input, or the error of the reader.
+func (decoder *LinesDecoder) Decode(obj interface{}) error {
+	if decoder.err != nil {
+		return decoder.err
+	}
+	for {
+		line, err := decoder.readLine()
+		if err != nil {
+			decoder.err = err
+			return err
+		}
+		if isBlankLine(line) {
+			continue
+		}
+		return decoder.decodeLine(line, obj)
+	}
+}
+
+// Next decodes the next line into obj, skipping the lines which can not be decoded,
+// see OnLineError. It returns false at the end of the input or on error, see Err.
+func (decoder *LinesDecoder) Next(obj interface{}) bool {
+	for {
+		err := decoder.Decode(obj)
+		if err == nil {
+			return true
+		}
+		lineErr, isLineErr := err.(*LineError)
+		if !isLineErr {
+			return false
+		}
+		if decoder.onError != nil {
+			decoder.onError(lineErr)
+		}
+	}
+}
+
+// OnLineError sets the handler of the lines skipped by Next.
+func (decoder *LinesDecoder) OnLineError(handler func(err *LineError)) {
+	decoder.onError = handler
+}
+
+// Err returns the error which stopped Next, nil at the end of the input.
+func (decoder *LinesDecoder) Err() error {
+	if decoder.err == io.EOF {
+		return nil
+	}
+	return decoder.err
+}
+
+// Line returns the number of the line last read, starting at 1.
+func (decoder *LinesDecoder) Line() int {
+	return decoder.line
+}
+
+// readLine returns the next line, valid until the next call.
+func (decoder *LinesDecoder) readLine() ([]byte, error) {
+	decoder.long = decoder.long[:0]
+	maxInputBytes := decoder.iter.cfg.maxInputBytes
+	for {
+		slice, err := decoder.reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			// past MaxInputBytes, the rest of the line is dropped, decoding reports the limit
+			if maxInputBytes <= 0 || len(decoder.long) <= maxInputBytes {
+				decoder.long = append(decoder.long, slice...)
+			}
+			continue
+		}
+		if err != nil && (err != io.EOF || len(slice)+len(decoder.long) == 0) {
+			return nil, err
+		}
+		decoder.line++
+		if len(decoder.long) == 0 {
+			return slice, nil
+		}
+		return append(decoder.long, slice...), nil
+	}
+}
+
+func isBlankLine(line []byte) bool {
+	for _, c := range line {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (decoder *LinesDecoder) decodeLine(line []byte, obj interface{}) error {
+	err := decodeWhole(decoder.iter, line, obj, "LinesDecoder")
+	if err != nil {
+		return &LineError{Line: decoder.line, Err: err}
+	}
+	return nil
+}
+
+// DecodeParallel decodes the remaining lines on workers goroutines, GOMAXPROCS when workers
+// is not positive. Each line is decoded into a new value from newElem, given with its line number
+// to sink as soon as it is decoded. sink is called from the calling goroutine, one line at a time.
+// The lines which can not be decoded are skipped, see OnLineError. It returns the error of the reader.
+func (decoder *LinesDecoder) DecodeParallel(workers int, newElem func() interface{}, sink func(line int, v interface{})) error {
+	return decoder.decodeParallel(workers, false, newElem, sink)
+}
+
+// DecodeParallelOrdered is DecodeParallel, giving the lines to sink in the order of the input.
+func (decoder *LinesDecoder) DecodeParallelOrdered(workers int, newElem func() interface{}, sink func(line int, v interface{})) error {
+	return decoder.decodeParallel(workers, true, newElem, sink)
+}
+
+func (decoder *LinesDecoder) decodeParallel(workers int, ordered bool, newElem func() interface{}, sink func(line int, v interface{})) error {
+	pd := decoder.iter.cfg.newParallelDecoder(workers, ordered, "LinesDecoder", newElem, sink)
+	pd.onError = func(elem *parallelElement) bool {
+		if decoder.onError != nil {
+			decoder.onError(&LineError{Line: elem.index, Err: elem.err})
+		}
+		return true
+	}
+	return pd.run(func() error {
+		if decoder.err != nil {
+			return decoder.Err()
+		}
+		for {
+			line, err := decoder.readLine()
+			if err != nil {
+				decoder.err = err
+				return decoder.Err()
+			}
+			if isBlankLine(line) {
+				continue
+			}
+			batch := pd.nextBatch()
+			if batch == nil {
+				return nil
+			}
+			start := len(batch.data)
+			batch.data = append(batch.data, line...)
+			batch.elements = append(batch.elements, parallelElement{index: decoder.line, start: start, end: len(batch.data)})
+		}
+	})
+}
+
+// LinesEncoder writes JSON Lines: one JSON value per line, without indention.
+type LinesEncoder struct {
+	writer io.Writer
+	stream *Stream
+}
+
+func (cfg *frozenConfig) NewLinesEncoder(writer io.Writer) *LinesEncoder {
+	if cfg.indentionStep != 0 {
+		config := cfg.configBeforeFrozen
+		config.IndentionStep = 0
+		cfg = config.frozeWithCacheReuse(cfg.extraExtensions)
+	}
+	return &LinesEncoder{
+		writer: writer,
+		stream: NewStream(cfg, nil, 512),
+	}
+}
+
+// Encode writes val as a line. Nothing is written when val can not be encoded,
+// the next values can still be encoded.
+func (encoder *LinesEncoder) Encode(val interface{}) error {
+	stream := encoder.stream
+	stream.Reset(nil)
+	stream.Error = nil
+	stream.WriteVal(val)
+	if stream.Error != nil {
+		return stream.Error
+	}
+	stream.writeByte('\n')
+	_, err := encoder.writer.Write(stream.Buffer())
+	return err
+}
@@ -0,0 +1,154 @@
+package misc_tests
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+// the numbers of RFC 8785 appendix B, by their IEEE 754 bits
+var canonicalNumbers = []struct {
+	bits     uint64
+	expected string
+}{
+	{0x0000000000000000, "0"},
+	{0x8000000000000000, "0"},
+	{0x0000000000000001, "5e-324"},
+	{0x8000000000000001, "-5e-324"},
+	{0x7fefffffffffffff, "1.7976931348623157e+308"},
+	{0xffefffffffffffff, "-1.7976931348623157e+308"},
+	{0x4340000000000000, "9007199254740992"},
+	{0xc340000000000000, "-9007199254740992"},
+	{0x4430000000000000, "295147905179352830000"},
+	{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+	{0x44b52d02c7e14af6, "1e+23"},
+	{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+	{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+	{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+	{0x444b1ae4d6e2ef50, "1e+21"},
+	{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+	{0x3eb0c6f7a0b5ed8d, "0.000001"},
+	{0x41b3de4355555553, "333333333.3333332"},
+	{0x41b3de4355555554, "333333333.33333325"},
+	{0x41b3de4355555555, "333333333.3333333"},
+	{0x41b3de4355555556, "333333333.3333334"},
+	{0x41b3de4355555557, "333333333.33333343"},
+	{0xbecbf647612f3696, "-0.0000033333333333333333"},
+	{0x43143ff3c1cb0959, "1424953923781206.2"},
+}
+
+func Test_canonical_numbers(t *testing.T) {
+	for _, testCase := range canonicalNumbers {
+		should := require.New(t)
+		output, err := jsoniter.ConfigCanonical.MarshalToString(math.Float64frombits(testCase.bits))
+		should.NoError(err)
+		should.Equal(testCase.expected, output, "%x", testCase.bits)
+	}
+	should := require.New(t)
+	_, err := jsoniter.ConfigCanonical.MarshalToString(math.NaN())
+	should.Error(err)
+	_, err = jsoniter.ConfigCanonical.MarshalToString(math.Inf(1))
+	should.Error(err)
+	// float32 and the integers beyond 2^53 are written as the float64 they read back as
+	output, err := jsoniter.ConfigCanonical.MarshalToString([]float32{0.1, 1e21, 1.5e-7, -2})
+	should.NoError(err)
+	should.Equal(`[0.10000000149011612,1.0000000200408773e+21,1.500000053056283e-7,-2]`, output)
+	output, err = jsoniter.ConfigCanonical.MarshalToString([]interface{}{int64(1) << 60, json.Number("1.50"), jsoniter.Number("2E3")})
+	should.NoError(err)
+	should.Equal(`[1152921504606847000,1.5,2000]`, output)
+	output, err = jsoniter.ConfigCanonical.MarshalToString([]interface{}{
+		int64(9007199254740992), int64(9007199254740993), int64(-9007199254740993), uint64(18446744073709551615), 42})
+	should.NoError(err)
+	should.Equal(`[9007199254740992,9007199254740992,-9007199254740992,18446744073709552000,42]`, output)
+	// canonical output is canonical once read again
+	for _, val := range []interface{}{
+		[]float32{0.1, 3.4e38, 1e-45}, []int64{math.MaxInt64, math.MinInt64, 1<<53 + 1}, []uint64{math.MaxUint64},
+	} {
+		output, err := jsoniter.ConfigCanonical.Marshal(val)
+		should.NoError(err)
+		again, err := jsoniter.ConfigCanonical.Marshal(jsoniter.RawMessage(output))
+		should.NoError(err)
+		should.Equal(string(output), string(again))
+	}
+}
+
+type canonicalMarshaler struct{}
+
+func (canonicalMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{ "b": 1.50, "a": ["x", 1E2] }`), nil
+}
+
+func Test_canonical(t *testing.T) {
+	should := require.New(t)
+	// the samples of RFC 8785 sections 3.2.2 and 3.2.3
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`
+	output, err := jsoniter.ConfigCanonical.MarshalToString(jsoniter.RawMessage(input))
+	should.NoError(err)
+	should.Equal(expected, output)
+	output, err = jsoniter.ConfigCanonical.MarshalToString(jsoniter.ConfigCanonical.Get([]byte(input)))
+	should.NoError(err)
+	should.Equal(expected, output)
+	input = `{
+		"\u20ac": "Euro Sign",
+		"\r": "Carriage Return",
+		"\ufb33": "Hebrew Letter Dalet With Dagesh",
+		"1": "One",
+		"\ud83d\ude00": "Emoji: Grinning Face",
+		"\u0080": "Control",
+		"\u00f6": "Latin Small Letter O With Diaeresis"
+	}`
+	expected = "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\"," +
+		"\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\"," +
+		"\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"
+	output, err = jsoniter.ConfigCanonical.MarshalToString(json.RawMessage(input))
+	should.NoError(err)
+	should.Equal(expected, output)
+
+	type embedded struct {
+		Zeta  string `json:"zeta"`
+		Alpha int    `json:"alpha"`
+	}
+	type document struct {
+		Signature string              `json:"sig"`
+		Emoji     string              "json:\"\U0001f600\""
+		Dalet     string              "json:\"\ufb33\""
+		Payload   canonicalMarshaler  `json:"payload"`
+		Text      string              `json:"text"`
+		Raw       jsoniter.RawMessage `json:"raw"`
+		embedded
+	}
+	output, err = jsoniter.ConfigCanonical.MarshalToString(document{
+		Signature: "", Emoji: "<&>", Dalet: "\u2028\x7f", Text: "\b\f\x01",
+		Raw: jsoniter.RawMessage(`[ {"y":1,"x":2} ]`), embedded: embedded{"z", 1},
+	})
+	should.NoError(err)
+	should.Equal(`{"alpha":1,"payload":{"a":["x",100],"b":1.5},"raw":[{"x":2,"y":1}],"sig":"",`+
+		`"text":"\b\f\u0001","zeta":"z","`+"\U0001f600\":\"<&>\",\"\ufb33\":\"\u2028\x7f\"}", output)
+	indented, err := jsoniter.ConfigCanonical.MarshalIndent(embedded{"z", 1}, "", "  ")
+	should.NoError(err)
+	should.Equal(`{"alpha":1,"zeta":"z"}`, string(indented))
+
+	_, err = jsoniter.ConfigCanonical.MarshalToString("ab\xff")
+	should.Error(err)
+	should.Contains(err.Error(), "at byte 2")
+	_, err = jsoniter.ConfigCanonical.MarshalToString(jsoniter.RawMessage(`[1,`))
+	should.Error(err)
+	_, err = jsoniter.ConfigCanonical.MarshalToString(jsoniter.RawMessage(`1 2`))
+	should.Error(err)
+}
+
+func Test_canonical_map(t *testing.T) {
+	should := require.New(t)
+	output, err := jsoniter.ConfigCanonical.MarshalToString(map[string]float64{"\ufb33": 1e21, "\U0001f600": 0.5, "b": 1})
+	should.NoError(err)
+	should.Equal("{\"b\":1,\"\U0001f600\":0.5,\"\ufb33\":1e+21}", output)
+}
@@ -0,0 +1,164 @@
+package misc_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checked_stream_inserts_commas(t *testing.T) {
+	should := require.New(t)
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 64)
+	stream.SetChecked(true)
+	stream.WriteObjectStart()
+	stream.WriteObjectField("a")
+	stream.WriteArrayStart()
+	stream.WriteInt(1)
+	stream.WriteString("b")
+	stream.WriteMore()
+	stream.WriteEmptyObject()
+	stream.WriteArrayEnd()
+	stream.WriteObjectField("c")
+	stream.WriteNil()
+	stream.WriteMore()
+	stream.WriteObjectField("d")
+	stream.WriteRaw(`{"e":1}`)
+	stream.WriteObjectEnd()
+	should.NoError(stream.Error)
+	should.NoError(stream.Flush())
+	should.Equal(`{"a":[1,"b",{}],"c":null,"d":{"e":1}}`, string(stream.Buffer()))
+
+	stream = jsoniter.NewStream(jsoniter.Config{IndentionStep: 2}.Froze(), nil, 64)
+	stream.SetChecked(true)
+	stream.WriteArrayStart()
+	stream.WriteTrue()
+	stream.WriteFloat64(1.5)
+	stream.WriteArrayEnd()
+	should.NoError(stream.Error)
+	should.Equal("[\n  true,\n  1.5\n]", string(stream.Buffer()))
+}
+
+type checkedStreamItem struct {
+	Name  string          `json:"name"`
+	Count int             `json:"count,string"`
+	Data  []byte          `json:"data"`
+	Num   json.Number     `json:"num"`
+	Raw   json.RawMessage `json:"raw"`
+	Next  *checkedStreamItem
+	Tags  []string `json:"tags,omitempty"`
+	Empty struct{} `json:"empty"`
+}
+
+func Test_checked_stream_with_encoders(t *testing.T) {
+	should := require.New(t)
+	val := []checkedStreamItem{
+		{Name: "a", Count: 1, Data: []byte("x"), Raw: json.RawMessage(`[1]`),
+			Next: &checkedStreamItem{Name: "b", Tags: []string{"c", "d"}}},
+		{Num: "1.5", Raw: json.RawMessage(`{}`)},
+	}
+	for _, api := range []jsoniter.API{jsoniter.ConfigDefault, jsoniter.Config{IndentionStep: 2}.Froze()} {
+		expected, err := api.Marshal(val)
+		should.NoError(err)
+		var buf bytes.Buffer
+		stream := jsoniter.NewStream(api, &buf, 64)
+		stream.SetChecked(true)
+		stream.WriteVal(val)
+		should.NoError(stream.Flush())
+		should.Equal(string(expected), buf.String())
+	}
+}
+
+func Test_checked_stream_rejects_invalid_writes(t *testing.T) {
+	testcases := []struct {
+		name  string
+		write func(stream *jsoniter.Stream)
+		err   string
+	}{
+		{"value as object field", func(stream *jsoniter.Stream) {
+			stream.WriteObjectStart()
+			stream.WriteInt(1)
+		}, "checked stream: unexpected value, expect object field or }"},
+		{"object field in array", func(stream *jsoniter.Stream) {
+			stream.WriteArrayStart()
+			stream.WriteObjectField("a")
+		}, "checked stream: unexpected object field, expect value or ]"},
+		{"object field without value", func(stream *jsoniter.Stream) {
+			stream.WriteObjectStart()
+			stream.WriteObjectField("a")
+			stream.WriteObjectField("b")
+		}, "checked stream: unexpected object field, expect value"},
+		{"wrong end", func(stream *jsoniter.Stream) {
+			stream.WriteArrayStart()
+			stream.WriteObjectStart()
+			stream.WriteArrayEnd()
+		}, "checked stream: unexpected ], expect object field or }"},
+		{"missing value", func(stream *jsoniter.Stream) {
+			stream.WriteObjectStart()
+			stream.WriteObjectField("a")
+			stream.WriteObjectEnd()
+		}, "checked stream: unexpected }, expect value"},
+		{"comma twice", func(stream *jsoniter.Stream) {
+			stream.WriteArrayStart()
+			stream.WriteInt(1)
+			stream.WriteMore()
+			stream.WriteMore()
+		}, "checked stream: unexpected ,, expect value"},
+		{"trailing comma", func(stream *jsoniter.Stream) {
+			stream.WriteArrayStart()
+			stream.WriteInt(1)
+			stream.WriteMore()
+			stream.WriteArrayEnd()
+		}, "checked stream: unexpected ], expect value"},
+		{"end at top", func(stream *jsoniter.Stream) {
+			stream.WriteObjectEnd()
+		}, "checked stream: unexpected }, expect value"},
+		{"second value", func(stream *jsoniter.Stream) {
+			stream.WriteInt(1)
+			stream.WriteArrayStart()
+		}, "checked stream: unexpected [, expect end of document"},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name, func(t *testing.T) {
+			should := require.New(t)
+			stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 64)
+			stream.SetChecked(true)
+			testcase.write(stream)
+			should.EqualError(stream.Error, testcase.err)
+		})
+	}
+}
+
+func Test_checked_stream_skips_rejected_writes(t *testing.T) {
+	should := require.New(t)
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 64)
+	stream.SetChecked(true)
+	stream.WriteObjectStart()
+	stream.WriteString("a")
+	should.Error(stream.Error)
+	should.Equal("{", string(stream.Buffer()))
+}
+
+func Test_checked_stream_flush(t *testing.T) {
+	should := require.New(t)
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 64)
+	stream.SetChecked(true)
+	should.NoError(stream.Flush())
+	stream.WriteArrayStart()
+	stream.WriteObjectStart()
+	should.EqualError(stream.Flush(), "checked stream: unexpected end of document, expect object field or }")
+	stream.WriteObjectField("a")
+	should.EqualError(stream.Flush(), "checked stream: unexpected end of document, expect value")
+	stream.WriteInt(1)
+	stream.WriteObjectEnd()
+	stream.WriteArrayEnd()
+	should.NoError(stream.Flush())
+	should.Equal(`[{"a":1}]`, buf.String())
+
+	stream.SetChecked(false)
+	stream.WriteArrayStart()
+	should.NoError(stream.Flush())
+}
@@ -0,0 +1,101 @@
+package misc_tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_duplicate_keys(t *testing.T) {
+	type account struct {
+		Name  string `json:"name"`
+		Admin bool   `json:"admin"`
+	}
+	input := `{"name":"guest","admin":false,"extra":1,"ADMIN":true,"extra":2,"name":"root"}`
+	nested := `{"user":` + input + `}`
+	testCases := []struct {
+		policy  jsoniter.DuplicateKeyPolicy
+		account account
+		fields  map[string]interface{}
+		counts  map[string]int
+		valid   bool
+	}{
+		{jsoniter.DuplicateKeysLastWins, account{"root", true},
+			map[string]interface{}{"name": "root", "admin": false, "extra": float64(2), "ADMIN": true},
+			map[string]int{"name": 0, "admin": 0, "extra": 2, "ADMIN": 0}, true},
+		{jsoniter.DuplicateKeysFirstWins, account{"guest", false},
+			map[string]interface{}{"name": "guest", "admin": false, "extra": float64(1), "ADMIN": true},
+			map[string]int{"name": 0, "admin": 0, "extra": 1, "ADMIN": 0}, true},
+	}
+	for _, testCase := range testCases {
+		should := require.New(t)
+		api := jsoniter.Config{DuplicateKeys: testCase.policy}.Froze()
+		var val account
+		should.NoError(api.UnmarshalFromString(input, &val))
+		should.Equal(testCase.account, val)
+		var fields map[string]interface{}
+		should.NoError(api.UnmarshalFromString(input, &fields))
+		should.Equal(testCase.fields, fields)
+		var counts map[string]int
+		should.NoError(api.UnmarshalFromString(`{"name":0,"admin":0,"extra":1,"ADMIN":0,"extra":2,"name":0}`, &counts))
+		should.Equal(testCase.counts, counts)
+		var generic interface{}
+		should.NoError(api.UnmarshalFromString(nested, &generic))
+		should.Equal(testCase.fields, generic.(map[string]interface{})["user"])
+		should.Equal(testCase.account.Name, api.Get([]byte(nested), "user", "name").ToString())
+		any := api.Get([]byte(nested), "user")
+		should.Equal(testCase.account.Name, any.Get("name").ToString())
+		should.Equal(testCase.fields["extra"], any.Get("extra").GetInterface())
+		should.Equal(testCase.valid, api.Valid([]byte(nested)))
+		doc, err := api.ParseDocument([]byte(nested))
+		should.NoError(err)
+		should.Equal(`"`+testCase.account.Name+`"`, string(doc.Path("user", "name").Raw()))
+	}
+
+	should := require.New(t)
+	api := jsoniter.Config{DuplicateKeys: jsoniter.DuplicateKeysError}.Froze()
+	var val account
+	err := api.UnmarshalFromString(input, &val)
+	should.Error(err)
+	should.Contains(err.Error(), `duplicate key: "ADMIN"`)
+	should.Error(api.UnmarshalFromString(`{"name":"a","name":"b"}`, &val))
+	should.NoError(api.UnmarshalFromString(`{"name":"a","admin":true}`, &val))
+	var fields map[string]interface{}
+	should.Error(api.UnmarshalFromString(input, &fields))
+	var counts map[string]int
+	should.Error(api.UnmarshalFromString(`{"a":1,"b":2,"a":3}`, &counts))
+	var generic interface{}
+	should.Error(api.UnmarshalFromString(nested, &generic))
+	should.False(api.Valid([]byte(nested)))
+	should.False(api.Valid([]byte(`[{"a":[{"b":1,"b":2}]}]`)))
+	should.True(api.Valid([]byte(`[{"a":[{"b":1},{"b":2}]}]`)))
+	should.Equal(jsoniter.InvalidValue, api.Get([]byte(nested), "user", "admin").ValueType())
+	iter := jsoniter.ParseString(api, nested)
+	iter.ReadAny()
+	should.Error(iter.Error)
+	_, err = api.ParseDocument([]byte(nested))
+	should.Error(err)
+	// without duplicates, the keys of an object are found in a set past the first ones
+	many := `{"k0":0,"k1":1,"k2":2,"k3":3,"k4":4,"k5":5,"k6":6,"k7":7,"k8":8,"k9":9`
+	should.True(api.Valid([]byte(many + `}`)))
+	should.False(api.Valid([]byte(many + `,"k9":9}`)))
+}
+
+func Test_duplicate_keys_lookup_errors(t *testing.T) {
+	should := require.New(t)
+	// an error past the key found leaves its value
+	should.Equal(1, jsoniter.Get([]byte(`{"s":1,"big":1e400}`), "s").ToInt())
+	should.Equal(2, jsoniter.Get([]byte(`{"s":1,"s":2,"x":tru}`), "s").ToInt())
+	// an error before it is reported, not taken for a missing key
+	any := jsoniter.Get([]byte(`{"x":tru,"s":1}`), "s")
+	should.Equal(jsoniter.InvalidValue, any.ValueType())
+	should.NotContains(any.LastError().Error(), "not found")
+	should.Contains(jsoniter.Get([]byte(`{"x":1}`), "s").LastError().Error(), "not found")
+	api := jsoniter.Config{DuplicateKeys: jsoniter.DuplicateKeysError}.Froze()
+	should.Contains(api.Get([]byte(`{"s":1,"s":2}`), "s").LastError().Error(), "duplicate key")
+	// a value read from a reader is looked up as well
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, strings.NewReader(`{"s":1,"t":[true],"s":2}`), 4)
+	should.Equal(2, iter.ReadAny().Get("s").ToInt())
+}
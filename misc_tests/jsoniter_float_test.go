@@ -3,6 +3,8 @@ package misc_tests
 import (
 	"encoding/json"
 	"math"
+	"math/rand"
+	"strconv"
 	"testing"
 
 	"github.com/json-iterator/go"
@@ -98,6 +100,119 @@ func Test_encode_nan(t *testing.T) {
 	should.Error(err)
 }
 
+func Test_float64_shortest_round_trip(t *testing.T) {
+	should := require.New(t)
+	random := rand.New(rand.NewSource(1))
+	values := []float64{0, math.Copysign(0, -1), 5e-324, math.MaxFloat64, 1e21, 1e-7, 0.1, 1 << 53, 123456789012345678}
+	for i := 0; i < 100000; i++ {
+		switch i % 3 {
+		case 0:
+			values = append(values, math.Float64frombits(random.Uint64()))
+		case 1:
+			values = append(values, float64(random.Int63n(1<<uint(random.Intn(63))))/math.Pow10(random.Intn(12)))
+		case 2:
+			values = append(values, math.Ldexp(1, random.Intn(2098)-1074))
+		}
+	}
+	for _, val := range values {
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			continue
+		}
+		format := byte('f')
+		if abs := math.Abs(val); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+			format = 'e'
+		}
+		output, err := jsoniter.Marshal(val)
+		should.NoError(err)
+		should.Equal(strconv.FormatFloat(val, format, -1, 64), string(output))
+		var decoded float64
+		should.NoError(jsoniter.Unmarshal(output, &decoded))
+		should.Equal(math.Float64bits(val), math.Float64bits(decoded), string(output))
+	}
+}
+
+func Test_read_float64_as_strconv(t *testing.T) {
+	should := require.New(t)
+	random := rand.New(rand.NewSource(2))
+	inputs := []string{"4.9e-324", "2.2250738585072011e-308", "9007199254740993", "0.1e1", "1E+2",
+		"0.000000000000000000000000000001", "18446744073709551615", "7.2057594037927933e16", "1e-350"}
+	for i := 0; i < 100000; i++ {
+		mantissa := strconv.FormatUint(random.Uint64()>>uint(random.Intn(64)), 10)
+		if dot := random.Intn(len(mantissa) + 1); dot > 0 && dot < len(mantissa) {
+			mantissa = mantissa[:dot] + "." + mantissa[dot:]
+		}
+		inputs = append(inputs, mantissa+"e"+strconv.Itoa(random.Intn(700)-350))
+	}
+	for _, input := range inputs {
+		expected, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			continue
+		}
+		iter := jsoniter.ParseString(jsoniter.ConfigDefault, "["+input+","+input+"]")
+		should.True(iter.ReadArray())
+		should.Equal(expected, iter.ReadFloat64(), input)
+		should.True(iter.ReadArray())
+		should.Equal(expected, iter.ReadFloat64(), input)
+		should.False(iter.ReadArray())
+		should.NoError(iter.Error)
+	}
+	var val float64
+	should.Error(jsoniter.UnmarshalFromString(`1e400`, &val))
+	should.Error(jsoniter.UnmarshalFromString(`[1.5e]`, &val))
+	should.Error(jsoniter.UnmarshalFromString(`[1.e5]`, &val))
+}
+
+func floatArray(precise bool) []float64 {
+	random := rand.New(rand.NewSource(3))
+	values := make([]float64, 1024)
+	for i := range values {
+		if precise {
+			values[i] = random.Float64() * math.Pow10(random.Intn(20)-10)
+		} else {
+			values[i] = float64(random.Intn(10000000)) / math.Pow10(random.Intn(7))
+		}
+	}
+	return values
+}
+
+func Benchmark_jsoniter_float64_array(b *testing.B) {
+	for _, precise := range []bool{false, true} {
+		values := floatArray(precise)
+		input, _ := jsoniter.Marshal(values)
+		b.Run("encode/precise="+strconv.FormatBool(precise), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				jsoniter.Marshal(values)
+			}
+		})
+		b.Run("decode/precise="+strconv.FormatBool(precise), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				jsoniter.Unmarshal(input, &values)
+			}
+		})
+	}
+}
+
+func Benchmark_json_float64_array(b *testing.B) {
+	for _, precise := range []bool{false, true} {
+		values := floatArray(precise)
+		input, _ := json.Marshal(values)
+		b.Run("encode/precise="+strconv.FormatBool(precise), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				json.Marshal(values)
+			}
+		})
+		b.Run("decode/precise="+strconv.FormatBool(precise), func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				json.Unmarshal(input, &values)
+			}
+		})
+	}
+}
+
 func Benchmark_jsoniter_float(b *testing.B) {
 	b.ReportAllocs()
 	input := []byte(`1.1123,`)
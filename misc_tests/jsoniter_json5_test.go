@@ -0,0 +1,174 @@
+package misc_tests
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+const json5Input = `// the settings of the service
+{
+	name: 'api \'v2\'',
+	/* ports to listen on,
+	   the first one is public */
+	ports: [0x1F90, +8443, 9000,],
+	ratio: .5,
+	limit: 10.,
+	max: Infinity,
+	'quoted': "line \
+break \x41",
+	"labels": {env: 'prod', $tier: "web",},
+}
+`
+
+type json5Settings struct {
+	Name   string            `json:"name"`
+	Ports  []int             `json:"ports"`
+	Ratio  float64           `json:"ratio"`
+	Limit  float32           `json:"limit"`
+	Max    float64           `json:"max"`
+	Quoted string            `json:"quoted"`
+	Labels map[string]string `json:"labels"`
+}
+
+func Test_json5(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	var settings json5Settings
+	should.NoError(api.UnmarshalFromString(json5Input, &settings))
+	should.Equal("api 'v2'", settings.Name)
+	should.Equal([]int{8080, 8443, 9000}, settings.Ports)
+	should.Equal(0.5, settings.Ratio)
+	should.Equal(float32(10), settings.Limit)
+	should.True(math.IsInf(settings.Max, 1))
+	should.Equal("line break A", settings.Quoted)
+	should.Equal(map[string]string{"env": "prod", "$tier": "web"}, settings.Labels)
+	should.True(api.Valid([]byte(json5Input)))
+	should.Error(jsoniter.UnmarshalFromString(json5Input, &settings))
+	should.False(jsoniter.Valid([]byte(json5Input)))
+
+	var generic map[string]interface{}
+	should.NoError(api.UnmarshalFromString(json5Input, &generic))
+	should.Equal("api 'v2'", generic["name"])
+	should.Equal([]interface{}{float64(8080), float64(8443), float64(9000)}, generic["ports"])
+	should.Equal("prod", api.Get([]byte(json5Input), "labels", "env").ToString())
+	should.Equal(8443, api.Get([]byte(json5Input), "ports", 1).ToInt())
+	should.Equal(0.5, api.Get([]byte(json5Input)).Get("ratio").ToFloat64())
+
+	var ints struct {
+		A int8
+		B uint16
+		C int64
+		D float64
+	}
+	should.NoError(api.UnmarshalFromString(`{A: -0x80, B: +0xffff, C: -12, D: NaN}`, &ints))
+	should.Equal(int8(-128), ints.A)
+	should.Equal(uint16(0xffff), ints.B)
+	should.Equal(int64(-12), ints.C)
+	should.True(math.IsNaN(ints.D))
+	should.Contains(api.UnmarshalFromString(`{A: 0x80}`, &ints).Error(), "overflow")
+	should.Contains(api.UnmarshalFromString(`{A: 1.5}`, &ints).Error(), "can not decode float as int")
+	should.Contains(api.UnmarshalFromString(`{D: 0x}`, &ints).Error(), "invalid hexadecimal number")
+	should.Contains(api.UnmarshalFromString(`{D: 01}`, &ints).Error(), "invalid number")
+	should.Contains(api.UnmarshalFromString(`{A: 1 /* open`, &ints).Error(), "comment not ended with */")
+	should.Error(api.UnmarshalFromString(`[1,,]`, &generic))
+	for _, input := range []string{`[,]`, `{,}`, `{A:[,]}`, `[ /* none */ , ]`, `{A:1,B:{,}}`} {
+		should.False(api.Valid([]byte(input)), input)
+		should.Error(api.UnmarshalFromString(input, &generic), input)
+		should.Error(api.UnmarshalFromString(input, &ints), input)
+		var slice []int
+		should.Error(api.UnmarshalFromString(input, &slice), input)
+	}
+	should.True(api.Valid([]byte(`{A:[1,],B:{c:1,},}`)))
+}
+
+func Test_json5_from_reader(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	for _, bufSize := range []int{1, 2, 3, 7, 512} {
+		// one byte at a time, for the comments and commas to be cut between the reads
+		iter := jsoniter.Parse(api, iotest.OneByteReader(strings.NewReader(json5Input)), bufSize)
+		var settings json5Settings
+		iter.ReadVal(&settings)
+		should.NoError(iter.Error, bufSize)
+		should.Equal([]int{8080, 8443, 9000}, settings.Ports, bufSize)
+		should.Equal(map[string]string{"env": "prod", "$tier": "web"}, settings.Labels, bufSize)
+	}
+	iter := jsoniter.Parse(api, iotest.OneByteReader(strings.NewReader(`[1, /* open`)), 4)
+	iter.Skip()
+	should.Contains(iter.Error.Error(), "comment not ended with */")
+	var list []interface{}
+	should.NoError(api.NewDecoder(strings.NewReader(`[1, 2 /* two */, ] // end`)).Decode(&list))
+	should.Equal([]interface{}{float64(1), float64(2)}, list)
+	should.Error(api.NewDecoder(strings.NewReader(`[1, 2 / 3]`)).Decode(&list))
+
+	var keyed map[string]int
+	should.NoError(api.UnmarshalFromString(`{nullable: 1, null: 2, 'a': 3, "b": 4}`, &keyed))
+	should.Equal(map[string]int{"nullable": 1, "null": 2, "a": 3, "b": 4}, keyed)
+	var text struct {
+		A string
+		B string
+	}
+	should.NoError(api.UnmarshalFromString(`{A: null, B: "x"}`, &text))
+	should.Equal("x", text.B)
+	should.Error(api.UnmarshalFromString(`{A: nullable}`, &text))
+	should.Error(api.UnmarshalFromString(`{A: b}`, &text))
+	should.Equal("a//b", api.Get([]byte("{a: 'a//b', // c\n b: \"/*\"}"), "a").ToString())
+	should.Equal("/*", api.Get([]byte("{a: 'a//b', // c\n b: \"/*\"}"), "b").ToString())
+}
+
+func Test_json5_options(t *testing.T) {
+	should := require.New(t)
+	var val interface{}
+	comments := jsoniter.Config{AllowComments: true}.Froze()
+	should.NoError(comments.UnmarshalFromString("[1, // one\n 2 /* two */] // end", &val))
+	should.Equal([]interface{}{float64(1), float64(2)}, val)
+	should.Error(comments.UnmarshalFromString(`[1, 2,]`, &val))
+	should.Error(comments.UnmarshalFromString(`[1, 2 / 3]`, &val))
+
+	commas := jsoniter.Config{AllowTrailingCommas: true}.Froze()
+	should.NoError(commas.UnmarshalFromString(`{"a": [1, 2, ], }`, &val))
+	should.Equal(map[string]interface{}{"a": []interface{}{float64(1), float64(2)}}, val)
+	should.Error(commas.UnmarshalFromString(`{'a': 1}`, &val))
+
+	quotes := jsoniter.Config{AllowSingleQuotes: true}.Froze()
+	should.NoError(quotes.UnmarshalFromString(`{'a': 'say "hi"'}`, &val))
+	should.Equal(map[string]interface{}{"a": `say "hi"`}, val)
+	should.Error(quotes.UnmarshalFromString(`{a: 1}`, &val))
+
+	keys := jsoniter.Config{AllowUnquotedKeys: true}.Froze()
+	var keyed struct {
+		FirstName string `json:"first_name"`
+	}
+	should.NoError(keys.UnmarshalFromString(`{first_name: "x"}`, &keyed))
+	should.Equal("x", keyed.FirstName)
+	should.Error(keys.UnmarshalFromString(`{"a": b}`, &val))
+	should.Error(keys.UnmarshalFromString(`{1a: 2}`, &val))
+
+	numbers := jsoniter.Config{AllowJSON5Numbers: true}.Froze()
+	should.NoError(numbers.UnmarshalFromString(`[0xA, -Infinity, .25, 3.e1]`, &val))
+	should.Equal(float64(10), val.([]interface{})[0])
+	should.True(math.IsInf(val.([]interface{})[1].(float64), -1))
+	should.Equal([]interface{}{0.25, float64(30)}, val.([]interface{})[2:])
+	should.Error(numbers.UnmarshalFromString(`[1,]`, &val))
+}
+
+func Test_json5_token(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{AllowJSON5: true}.Froze()
+	iter := jsoniter.ParseString(api, `{a: 'b', /* c */ d: [0x10,],}`)
+	tokens := []interface{}{}
+	for {
+		token := iter.Token()
+		if token == nil {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	should.Equal([]interface{}{jsoniter.Delim('{'), "a", "b", "d", jsoniter.Delim('['),
+		float64(16), jsoniter.Delim(']'), jsoniter.Delim('}')}, tokens)
+}
@@ -0,0 +1,150 @@
+package misc_tests
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type limitedItem struct {
+	Name string `json:"name"`
+}
+
+type limitedOrder struct {
+	Items []limitedItem       `json:"items"`
+	Tags  map[string]string   `json:"tags"`
+	Codes [2]int              `json:"codes"`
+	Extra map[string][]string `json:"extra"`
+}
+
+func limitErrorOf(err error) *jsoniter.LimitError {
+	if decodeErr, isDecodeErr := err.(*jsoniter.DecodeError); isDecodeErr {
+		err = decodeErr.Err
+	}
+	limitErr, _ := err.(*jsoniter.LimitError)
+	return limitErr
+}
+
+func Test_limits(t *testing.T) {
+	testcases := []struct {
+		name   string
+		config jsoniter.Config
+		within string
+		over   string
+	}{
+		{"MaxDepth", jsoniter.Config{MaxDepth: 3},
+			`{"extra":{"a":["b"]}}`, `{"items":[{"x":{}}]}`},
+		{"MaxStringLength", jsoniter.Config{MaxStringLength: 5},
+			`{"items":[{"name":"abcde"}],"tags":{"aéb":"x"}}`, `{"items":[{"name":"abcdef"}]}`},
+		{"MaxStringLength", jsoniter.Config{MaxStringLength: 5},
+			`{"tags":{"abc":"\n\n\n\n\n"}}`, `{"tags":{"abc":"abcd\n\n"}}`},
+		{"MaxStringLength", jsoniter.Config{MaxStringLength: 4},
+			`{"name":1}`, `{"items":1}`},
+		{"MaxArrayElements", jsoniter.Config{MaxArrayElements: 2},
+			`{"items":[{},{}],"codes":[1,2],"extra":{"a":["b","c"]}}`, `{"codes":[1,2,3]}`},
+		{"MaxArrayElements", jsoniter.Config{MaxArrayElements: 2},
+			`{"extra":{"a":["b","c"]}}`, `{"items":[{},{},{}]}`},
+		{"MaxObjectKeys", jsoniter.Config{MaxObjectKeys: 2},
+			`{"items":[{"name":"a","x":1}],"tags":{"a":"b"}}`, `{"tags":{"a":"b","c":"d","e":"f"}}`},
+		{"MaxObjectKeys", jsoniter.Config{MaxObjectKeys: 2},
+			`{"items":[],"tags":{}}`, `{"items":[],"tags":{},"codes":[]}`},
+		{"MaxInputBytes", jsoniter.Config{MaxInputBytes: 12},
+			`{"items":[]}`, `{"items":[ ]}`},
+	}
+	for _, testcase := range testcases {
+		t.Run(testcase.name+" "+testcase.over, func(t *testing.T) {
+			should := require.New(t)
+			api := testcase.config.Froze()
+			var order limitedOrder
+			should.NoError(api.UnmarshalFromString(testcase.within, &order))
+			var any interface{}
+			should.NoError(api.UnmarshalFromString(testcase.within, &any))
+			should.True(api.Valid([]byte(testcase.within)))
+
+			err := api.UnmarshalFromString(testcase.over, &order)
+			limitErr := limitErrorOf(err)
+			should.NotNil(limitErr, "%v", err)
+			should.Equal(testcase.name, limitErr.Limit)
+			err = api.UnmarshalFromString(testcase.over, &any)
+			should.NotNil(limitErrorOf(err), "%v", err)
+			should.False(api.Valid([]byte(testcase.over)))
+			iter := jsoniter.ParseString(api, testcase.over)
+			iter.ReadAny()
+			iter.WhatIsNext()
+			should.NotNil(limitErrorOf(iter.Error), "%v", iter.Error)
+
+			iter = jsoniter.ParseString(api, testcase.within)
+			pullValue(iter)
+			should.True(iter.Error == nil || iter.Error == io.EOF, "%v", iter.Error)
+			iter = jsoniter.ParseString(api, testcase.over)
+			pullValue(iter)
+			should.NotNil(limitErrorOf(iter.Error), "%v", iter.Error)
+		})
+	}
+}
+
+// pullValue reads the next value with ReadArray and ReadObject.
+func pullValue(iter *jsoniter.Iterator) {
+	switch iter.WhatIsNext() {
+	case jsoniter.ArrayValue:
+		for iter.ReadArray() {
+			pullValue(iter)
+		}
+	case jsoniter.ObjectValue:
+		for field := iter.ReadObject(); field != ""; field = iter.ReadObject() {
+			pullValue(iter)
+		}
+	default:
+		iter.Skip()
+	}
+}
+
+func Test_limit_error_location(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{MaxDepth: 2}.Froze()
+	var any interface{}
+	err := api.UnmarshalFromString("[\n [\n  [1]]]", &any)
+	limitErr := limitErrorOf(err)
+	should.NotNil(limitErr)
+	should.Equal(2, limitErr.Max)
+	should.Equal(3, limitErr.Line)
+	should.Equal(3, limitErr.Column)
+	should.Contains(err.Error(), "exceeded max depth of 2")
+}
+
+func Test_max_input_bytes_with_reader(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{MaxInputBytes: 30}.Froze()
+	input := `[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15]`
+	var within []int
+	should.NoError(api.NewDecoder(strings.NewReader(input[:29] + "]")).Decode(&within))
+	iter := jsoniter.Parse(api, bytes.NewBufferString(input), 8)
+	var over []int
+	iter.ReadVal(&over)
+	limitErr := limitErrorOf(iter.Error)
+	should.NotNil(limitErr, "%v", iter.Error)
+	should.Equal("MaxInputBytes", limitErr.Limit)
+	should.Equal(int64(30), limitErr.Offset)
+
+	decoder := api.NewDecoder(strings.NewReader(strings.Repeat(`"abcdefghi" `, 3)))
+	var str string
+	should.NoError(decoder.Decode(&str))
+	should.NoError(decoder.Decode(&str))
+	should.NotNil(limitErrorOf(decoder.Decode(&str)))
+
+	decoder = api.NewDecoder(strings.NewReader(strings.Repeat(`"abcdefghijklm"`, 3)))
+	should.NoError(decoder.Decode(&str))
+	should.NoError(decoder.Decode(&str))
+	should.NotNil(limitErrorOf(decoder.Decode(&str)))
+}
+
+func Test_default_max_depth(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{MaxDepth: -1}.Froze()
+	should.True(api.Valid([]byte(strings.Repeat(`[`, 10000) + strings.Repeat(`]`, 10000))))
+	should.False(api.Valid([]byte(strings.Repeat(`[`, 10001) + strings.Repeat(`]`, 10001))))
+}
@@ -0,0 +1,89 @@
+package misc_tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func structuralIndexInput(n int) string {
+	var sb strings.Builder
+	sb.WriteString(`{"skipped":[`)
+	for i := 0; i < n; i++ {
+		if i != 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"id":%d,"name":"item \"%d\"","tags":["a",{"b":[1.5,null,true]}]}`, i, i)
+	}
+	sb.WriteString(`],"wanted":{"nested":[0,{"deep":"found"}],"other":{"x":[[1],[2]]}}}`)
+	return sb.String()
+}
+
+func Test_structural_index(t *testing.T) {
+	should := require.New(t)
+	input := []byte(structuralIndexInput(500))
+	should.True(len(input) > 16*1024)
+	indexed := jsoniter.Config{UseStructuralIndex: true}.Froze()
+
+	type wanted struct {
+		Wanted struct {
+			Nested []jsoniter.Any `json:"nested"`
+		} `json:"wanted"`
+	}
+	var expected, actual wanted
+	should.NoError(jsoniter.Unmarshal(input, &expected))
+	should.NoError(indexed.Unmarshal(input, &actual))
+	should.Equal(expected.Wanted.Nested[1].Get("deep").ToString(), actual.Wanted.Nested[1].Get("deep").ToString())
+
+	for _, path := range [][]interface{}{
+		{"wanted", "nested", 1, "deep"},
+		{"skipped", 499, "name"},
+		{"skipped", 250, "tags", 1, "b", 0},
+		{"wanted", "other", "x", 1, 0},
+		{"wanted", "other", "y"},
+		{"skipped", 500},
+	} {
+		should.Equal(jsoniter.Get(input, path...).ToString(), indexed.Get(input, path...).ToString(), "%v", path)
+		should.Equal(jsoniter.Get(input, path...).ValueType(), indexed.Get(input, path...).ValueType(), "%v", path)
+	}
+
+	any := indexed.Get(input)
+	should.Equal(500, any.Get("skipped").Size())
+	should.Equal("item \"42\"", any.Get("skipped", 42, "name").ToString())
+	should.Equal(true, any.Get("skipped", 7, "tags", 1, "b", 2).ToBool())
+	should.Equal([]string{"nested", "other"}, any.Get("wanted").Keys())
+	should.Equal(500, indexed.Get(input, "skipped", '*', "id").Size())
+
+	iter := indexed.BorrowIterator(input)
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		iter.Skip()
+		return true
+	})
+	should.NoError(iter.Error)
+	indexed.ReturnIterator(iter)
+	should.True(indexed.Valid(input))
+}
+
+func Test_structural_index_invalid_input(t *testing.T) {
+	should := require.New(t)
+	input := structuralIndexInput(500)
+	indexed := jsoniter.Config{UseStructuralIndex: true}.Froze()
+	for _, invalid := range []string{
+		strings.Replace(input, `"id":42,`, `"id":42`, 1),
+		strings.Replace(input, `"id":42,`, `"id":042,`, 1),
+		strings.Replace(input, `[1.5,null,true]}]},{"id":43`, `[1.5,null,true]}],{"id":43`, 1),
+		input[:len(input)-1],
+	} {
+		var expected, actual interface{}
+		expectedErr := jsoniter.UnmarshalFromString(invalid, &expected)
+		actualErr := indexed.UnmarshalFromString(invalid, &actual)
+		should.Error(actualErr)
+		should.Equal(expectedErr.Error(), actualErr.Error())
+		should.Equal(jsoniter.Valid([]byte(invalid)), indexed.Valid([]byte(invalid)))
+		should.Equal(jsoniter.Get([]byte(invalid), "wanted", "nested", 1, "deep").ToString(),
+			indexed.Get([]byte(invalid), "wanted", "nested", 1, "deep").ToString())
+	}
+}
@@ -0,0 +1,124 @@
+package misc_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenReader struct {
+	reader io.Reader
+}
+
+// Read returns one byte at a time, so that tokens are split across reads
+func (reader *tokenReader) Read(p []byte) (int, error) {
+	return reader.reader.Read(p[:1])
+}
+
+func Test_token_as_std(t *testing.T) {
+	inputs := []string{
+		`{"a":[1,"x",true,null,{"b":-1.5e3}],"c":{}} [] 12 "s"`,
+		` [ [ ] , { } , [ { "k" : [ ] } ] ] `,
+		`{"":false}`,
+	}
+	for _, input := range inputs {
+		should := require.New(t)
+		expected := []interface{}{}
+		expectedOffsets := []int64{}
+		stdDecoder := json.NewDecoder(strings.NewReader(input))
+		for {
+			token, err := stdDecoder.Token()
+			if err == io.EOF {
+				break
+			}
+			should.NoError(err)
+			expected = append(expected, token)
+			expectedOffsets = append(expectedOffsets, stdDecoder.InputOffset())
+		}
+		actual := []interface{}{}
+		actualOffsets := []int64{}
+		decoder := jsoniter.NewDecoder(&tokenReader{strings.NewReader(input)})
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			should.NoError(err)
+			actual = append(actual, token)
+			actualOffsets = append(actualOffsets, decoder.InputOffset())
+		}
+		should.Equal(expected, actual)
+		should.Equal(expectedOffsets, actualOffsets)
+	}
+}
+
+func Test_token_mixed_with_decode(t *testing.T) {
+	should := require.New(t)
+	type item struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	input := `{"total":3,"items":[{"id":1},{"id":2,"tags":["a"]} , {"id":3}],"next":null}`
+	decoder := jsoniter.NewDecoder(&tokenReader{strings.NewReader(input)})
+	tokens := []interface{}{}
+	items := []item{}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		should.NoError(err)
+		tokens = append(tokens, token)
+		if token == "total" {
+			var total int
+			should.NoError(decoder.Decode(&total))
+			should.Equal(3, total)
+		}
+		if token == json.Delim('[') {
+			for decoder.More() {
+				var elem item
+				should.NoError(decoder.Decode(&elem))
+				items = append(items, elem)
+			}
+		}
+	}
+	should.Equal([]interface{}{json.Delim('{'), "total", "items", json.Delim('['), json.Delim(']'),
+		"next", nil, json.Delim('}')}, tokens)
+	should.Equal([]item{{ID: 1}, {ID: 2, Tags: []string{"a"}}, {ID: 3}}, items)
+}
+
+func Test_token_from_iterator(t *testing.T) {
+	should := require.New(t)
+	iter := jsoniter.ParseString(jsoniter.Config{UseNumber: true}.Froze(), `[1.50,{"a":[2]}]`)
+	should.Equal(jsoniter.Delim('['), iter.Token())
+	should.Equal(json.Number("1.50"), iter.Token())
+	should.Equal(int64(5), iter.InputOffset())
+	var obj map[string][]int
+	iter.ReadVal(&obj)
+	should.NoError(iter.Error)
+	should.Equal(map[string][]int{"a": {2}}, obj)
+	should.Equal(jsoniter.Delim(']'), iter.Token())
+	should.Nil(iter.Token())
+	should.Equal(io.EOF, iter.Error)
+}
+
+func Test_token_reports_malformed_input(t *testing.T) {
+	for _, input := range []string{`[1 2]`, `{"a" 1}`, `{"a":1,}`, `{1:2}`, `[1,]`, `]`, `{"a":1]`, `[,1]`, `{"a"::1}`} {
+		decoder := jsoniter.NewDecoder(bytes.NewBufferString(input))
+		var err error
+		for err == nil {
+			_, err = decoder.Token()
+		}
+		require.NotEqual(t, io.EOF, err, input)
+	}
+	decoder := jsoniter.NewDecoder(bytes.NewBufferString(`{"a" 1}`))
+	decoder.Token()
+	decoder.Token()
+	var value int
+	require.Error(t, decoder.Decode(&value))
+}
@@ -0,0 +1,294 @@
+package jsoniter
+
+import (
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// DecodeArrayParallel decodes the elements of the JSON array read from reader on several goroutines,
+// see API.DecodeArrayParallel.
+func DecodeArrayParallel(reader io.Reader, workers int, newElem func() interface{}, sink func(i int, v interface{})) error {
+	return ConfigDefault.DecodeArrayParallel(reader, workers, newElem, sink)
+}
+
+// DecodeArrayParallelOrdered is DecodeArrayParallel, giving the elements to sink in the order of the array.
+func DecodeArrayParallelOrdered(reader io.Reader, workers int, newElem func() interface{}, sink func(i int, v interface{})) error {
+	return ConfigDefault.DecodeArrayParallelOrdered(reader, workers, newElem, sink)
+}
+
+// the size of the batches of elements handed to the workers
+const (
+	parallelBatchBytes    = 32 * 1024
+	parallelBatchElements = 256
+)
+
+// parallelElement is a value decoded on its own, at data[start:end] of its batch.
+type parallelElement struct {
+	index  int
+	start  int
+	end    int
+	offset int64
+	line   int
+	column int
+	val    interface{}
+	err    error
+}
+
+type parallelBatch struct {
+	seq      int
+	data     []byte
+	elements []parallelElement
+}
+
+// parallelDecoder splits the input into values on a goroutine, decodes them on the workers,
+// then gives them to sink on the calling goroutine.
+type parallelDecoder struct {
+	cfg       *frozenConfig
+	workers   int
+	ordered   bool
+	operation string
+	newElem   func() interface{}
+	sink      func(i int, v interface{})
+	// onError tells if decoding goes on after an element failed
+	onError func(elem *parallelElement) bool
+	tasks   chan *parallelBatch
+	results chan *parallelBatch
+	// inflight bounds the batches split but not given to sink yet
+	inflight chan struct{}
+	stop     chan struct{}
+	batch    *parallelBatch
+	seq      int
+}
+
+func (cfg *frozenConfig) newParallelDecoder(workers int, ordered bool, operation string,
+	newElem func() interface{}, sink func(i int, v interface{})) *parallelDecoder {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &parallelDecoder{
+		cfg:       cfg,
+		workers:   workers,
+		ordered:   ordered,
+		operation: operation,
+		newElem:   newElem,
+		sink:      sink,
+		tasks:     make(chan *parallelBatch, workers),
+		results:   make(chan *parallelBatch, workers),
+		inflight:  make(chan struct{}, 4*workers),
+		stop:      make(chan struct{}),
+	}
+}
+
+// DecodeArrayParallel decodes the elements of the JSON array read from reader on workers goroutines,
+// GOMAXPROCS when workers is not positive. The elements are found with the skip scanner, then each
+// is decoded into a new value from newElem, given with its index to sink as soon as it is decoded.
+// sink is called from the calling goroutine, one element at a time.
+// Decoding stops at the first error, returned with the location of the element in the input.
+func (cfg *frozenConfig) DecodeArrayParallel(reader io.Reader, workers int, newElem func() interface{}, sink func(i int, v interface{})) error {
+	return cfg.newParallelDecoder(workers, false, "DecodeArrayParallel", newElem, sink).decodeArray(reader)
+}
+
+// DecodeArrayParallelOrdered is DecodeArrayParallel, giving the elements to sink in the order of the array.
+// The first error in the order of the array is returned.
+func (cfg *frozenConfig) DecodeArrayParallelOrdered(reader io.Reader, workers int, newElem func() interface{}, sink func(i int, v interface{})) error {
+	return cfg.newParallelDecoder(workers, true, "DecodeArrayParallel", newElem, sink).decodeArray(reader)
+}
+
+func (pd *parallelDecoder) decodeArray(reader io.Reader) error {
+	var firstErr *parallelElement
+	pd.onError = func(elem *parallelElement) bool {
+		firstErr = elem
+		return false
+	}
+	err := pd.run(func() error {
+		iter := Parse(pd.cfg, reader, 4096)
+		index := 0
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			batch := pd.nextBatch()
+			if batch == nil {
+				return false
+			}
+			iter.WhatIsNext()
+			offset, line, column := iter.location()
+			start := len(batch.data)
+			batch.data = iter.SkipAndAppendBytes(batch.data)
+			if iter.Error != nil {
+				batch.data = batch.data[:start]
+				return false
+			}
+			batch.elements = append(batch.elements, parallelElement{
+				index: index, start: start, end: len(batch.data),
+				offset: offset, line: line, column: column,
+			})
+			index++
+			return true
+		})
+		if iter.Error != nil && iter.Error != io.EOF {
+			return iter.Error
+		}
+		return nil
+	})
+	if firstErr != nil {
+		return relocateError(firstErr.err, firstErr.index, firstErr.offset, firstErr.line, firstErr.column)
+	}
+	return err
+}
+
+// run decodes the values split by split, returning the error of split.
+func (pd *parallelDecoder) run(split func() error) error {
+	var splitErr error
+	go func() {
+		splitErr = split()
+		pd.sendBatch()
+		close(pd.tasks)
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < pd.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range pd.tasks {
+				pd.decodeBatch(batch)
+				pd.results <- batch
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(pd.results)
+	}()
+	pending := map[int]*parallelBatch{}
+	next := 0
+	stopped := false
+	for batch := range pd.results {
+		if stopped {
+			<-pd.inflight
+			continue
+		}
+		if !pd.ordered {
+			stopped = !pd.emit(batch)
+			continue
+		}
+		pending[batch.seq] = batch
+		for pending[next] != nil && !stopped {
+			stopped = !pd.emit(pending[next])
+			delete(pending, next)
+			next++
+		}
+		if stopped {
+			for range pending {
+				<-pd.inflight
+			}
+		}
+	}
+	if stopped {
+		return nil
+	}
+	return splitErr
+}
+
+// nextBatch returns the batch to add the next value to, nil once decoding stopped.
+func (pd *parallelDecoder) nextBatch() *parallelBatch {
+	if pd.batch != nil && (len(pd.batch.data) >= parallelBatchBytes || len(pd.batch.elements) >= parallelBatchElements) {
+		if !pd.sendBatch() {
+			return nil
+		}
+	}
+	if pd.batch == nil {
+		pd.batch = &parallelBatch{seq: pd.seq, data: make([]byte, 0, parallelBatchBytes)}
+		pd.seq++
+	}
+	return pd.batch
+}
+
+func (pd *parallelDecoder) sendBatch() bool {
+	batch := pd.batch
+	if batch == nil {
+		return true
+	}
+	pd.batch = nil
+	select {
+	case pd.inflight <- struct{}{}:
+	case <-pd.stop:
+		return false
+	}
+	pd.tasks <- batch
+	return true
+}
+
+func (pd *parallelDecoder) decodeBatch(batch *parallelBatch) {
+	select {
+	case <-pd.stop:
+		return
+	default:
+	}
+	iter := pd.cfg.BorrowIterator(nil)
+	defer pd.cfg.ReturnIterator(iter)
+	for i := range batch.elements {
+		elem := &batch.elements[i]
+		elem.val = pd.newElem()
+		elem.err = decodeWhole(iter, batch.data[elem.start:elem.end], elem.val, pd.operation)
+	}
+}
+
+// emit gives the values of batch to sink, telling if decoding goes on.
+func (pd *parallelDecoder) emit(batch *parallelBatch) bool {
+	defer func() { <-pd.inflight }()
+	for i := range batch.elements {
+		elem := &batch.elements[i]
+		if elem.err != nil {
+			if pd.onError(elem) {
+				continue
+			}
+			close(pd.stop)
+			return false
+		}
+		pd.sink(elem.index, elem.val)
+	}
+	return true
+}
+
+// decodeWhole decodes obj from the whole of data, as Unmarshal does.
+func decodeWhole(iter *Iterator, data []byte, obj interface{}, operation string) error {
+	iter.ResetBytes(data)
+	iter.Error = nil
+	iter.ReadVal(obj)
+	if iter.Error == nil && iter.nextToken() != 0 {
+		iter.ReportError(operation, "there are bytes left after the value")
+	}
+	err := iter.Error
+	if err == io.EOF {
+		err = nil
+	}
+	return iter.withCollectedErrors(err)
+}
+
+// relocateError moves the location of err, found in the array element decoded on its own,
+// to where the element starts in the whole input, past offset on line after column bytes.
+func relocateError(err error, index int, offset int64, line int, column int) error {
+	switch err := err.(type) {
+	case DecodeErrors:
+		for _, decodeErr := range err {
+			relocateError(decodeErr, index, offset, line, column)
+		}
+	case *DecodeError:
+		err.Offset, err.Line, err.Column = relocate(err.Offset, err.Line, err.Column, offset, line, column)
+		err.addToken(strconv.Itoa(index))
+		relocateError(err.Err, index, offset, line, column)
+	case *SyntaxError:
+		err.Offset, err.Line, err.Column = relocate(err.Offset, err.Line, err.Column, offset, line, column)
+	case *LimitError:
+		err.Offset, err.Line, err.Column = relocate(err.Offset, err.Line, err.Column, offset, line, column)
+		relocateError(err.err, index, offset, line, column)
+	}
+	return err
+}
+
+func relocate(errOffset int64, errLine int, errColumn int, offset int64, line int, column int) (int64, int, int) {
+	if errLine == 1 {
+		errColumn += column
+	}
+	return errOffset + offset, errLine + line - 1, errColumn
+}
@@ -0,0 +1,128 @@
+package patch
+
+import (
+	"strconv"
+
+	"github.com/json-iterator/go"
+)
+
+// Diff generates a patch which turns document a into document b.
+// Objects are compared member by member and arrays element by element,
+// any other difference is expressed as a replace of the whole value.
+func Diff(a []byte, b []byte) (Patch, error) {
+	var raw jsoniter.RawMessage
+	if err := cfg.Unmarshal(a, &raw); err != nil {
+		return nil, err
+	}
+	if err := cfg.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	differ := &differ{patch: Patch{}}
+	differ.diffValue([]string{}, a, b)
+	if differ.err != nil {
+		return nil, differ.err
+	}
+	return differ.patch, nil
+}
+
+type differ struct {
+	patch Patch
+	err   error
+}
+
+func (differ *differ) diffValue(path []string, a []byte, b []byte) {
+	typeA := valueTypeOf(a)
+	typeB := valueTypeOf(b)
+	switch {
+	case typeA == jsoniter.ObjectValue && typeB == jsoniter.ObjectValue:
+		differ.diffObject(path, a, b)
+	case typeA == jsoniter.ArrayValue && typeB == jsoniter.ArrayValue:
+		differ.diffArray(path, a, b)
+	default:
+		equal, err := equalJSON(a, b)
+		if err != nil {
+			differ.err = err
+			return
+		}
+		if !equal {
+			differ.add("replace", path, b)
+		}
+	}
+}
+
+func (differ *differ) diffObject(path []string, a []byte, b []byte) {
+	keysA, membersA := readMembers(a)
+	keysB, membersB := readMembers(b)
+	for _, key := range keysA {
+		if _, found := membersB[key]; !found {
+			differ.add("remove", child(path, key), nil)
+		}
+	}
+	for _, key := range keysB {
+		valueA, found := membersA[key]
+		if !found {
+			differ.add("add", child(path, key), membersB[key])
+			continue
+		}
+		differ.diffValue(child(path, key), valueA, membersB[key])
+	}
+}
+
+func (differ *differ) diffArray(path []string, a []byte, b []byte) {
+	elementsA := readElements(a)
+	elementsB := readElements(b)
+	common := len(elementsA)
+	if len(elementsB) < common {
+		common = len(elementsB)
+	}
+	for i := 0; i < common; i++ {
+		differ.diffValue(child(path, strconv.Itoa(i)), elementsA[i], elementsB[i])
+	}
+	for i := len(elementsA) - 1; i >= common; i-- {
+		differ.add("remove", child(path, strconv.Itoa(i)), nil)
+	}
+	for i := common; i < len(elementsB); i++ {
+		differ.add("add", child(path, strconv.Itoa(i)), elementsB[i])
+	}
+}
+
+func (differ *differ) add(op string, path []string, value []byte) {
+	differ.patch = append(differ.patch, Operation{Op: op, Path: FormatPointer(path...), Value: value})
+}
+
+// child returns a new path, never sharing the backing array of its parent.
+func child(path []string, token string) []string {
+	return append(path[:len(path):len(path)], token)
+}
+
+func valueTypeOf(value []byte) jsoniter.ValueType {
+	iter := cfg.BorrowIterator(value)
+	defer cfg.ReturnIterator(iter)
+	return iter.WhatIsNext()
+}
+
+func readMembers(object []byte) ([]string, map[string][]byte) {
+	iter := cfg.BorrowIterator(object)
+	defer cfg.ReturnIterator(iter)
+	keys := []string{}
+	members := map[string][]byte{}
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		if _, found := members[field]; !found {
+			keys = append(keys, field)
+		}
+		members[field] = readRawValue(iter)
+		return true
+	})
+	return keys, members
+}
+
+func readElements(array []byte) [][]byte {
+	iter := cfg.BorrowIterator(array)
+	defer cfg.ReturnIterator(iter)
+	elements := [][]byte{}
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		elements = append(elements, readRawValue(iter))
+		return true
+	})
+	return elements
+}
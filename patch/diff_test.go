@@ -0,0 +1,60 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_diff_then_apply(t *testing.T) {
+	pairs := [][2]string{
+		{`{"foo":"bar"}`, `{"foo":"bar"}`},
+		{`{"foo":"bar"}`, `{"foo":"baz","qux":[1,2]}`},
+		{`{"foo":{"bar":1,"baz":2}}`, `{"foo":{"baz":2.0}}`},
+		{`[1,2,3,4]`, `[1,5]`},
+		{`[1]`, `[1,{"a":null},"x"]`},
+		{`{"a/b":{"~c":1}}`, `{"a/b":{"~c":[]}}`},
+		{`{"a":[1,2]}`, `"scalar"`},
+		{`{"n":9007199254740992}`, `{"n":9007199254740993}`},
+		{`{"a":1,"a":2}`, `{"a":3}`},
+	}
+	for _, pair := range pairs {
+		t.Run(pair[0]+" to "+pair[1], func(t *testing.T) {
+			should := require.New(t)
+			patch, err := Diff([]byte(pair[0]), []byte(pair[1]))
+			should.NoError(err)
+			output, err := patch.Apply([]byte(pair[0]))
+			should.NoError(err)
+			equal, err := equalJSON([]byte(pair[1]), output)
+			should.NoError(err)
+			should.True(equal, string(output))
+		})
+	}
+}
+
+func Test_diff_operations(t *testing.T) {
+	should := require.New(t)
+	patch, err := Diff([]byte(`{"a":1,"b":[1,2,3],"c":{"d":true}}`), []byte(`{"a":1.0,"b":[1],"c":{"d":false},"e":null}`))
+	should.NoError(err)
+	encoded, err := jsoniter.MarshalToString(patch)
+	should.NoError(err)
+	should.Equal(`[{"op":"remove","path":"/b/2"},{"op":"remove","path":"/b/1"},`+
+		`{"op":"replace","path":"/c/d","value":false},{"op":"add","path":"/e","value":null}]`, encoded)
+	decoded, err := Decode([]byte(encoded))
+	should.NoError(err)
+	should.Equal(patch, decoded)
+}
+
+func Test_diff_invalid_document(t *testing.T) {
+	should := require.New(t)
+	_, err := Diff([]byte(`{}`), []byte(`{"a":`))
+	should.Error(err)
+}
+
+func Test_diff_large_integers(t *testing.T) {
+	should := require.New(t)
+	patch, err := Diff([]byte(`[9007199254740992,1e2]`), []byte(`[9007199254740993,100]`))
+	should.NoError(err)
+	should.Equal(Patch{{Op: "replace", Path: "/0", Value: jsoniter.RawMessage(`9007199254740993`)}}, patch)
+}
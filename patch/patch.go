@@ -0,0 +1,408 @@
+// Package patch implements RFC 6902 JSON Patch on raw JSON documents.
+//
+// Operations are applied by streaming the document through a jsoniter.Iterator
+// into a jsoniter.Stream: only the containers on the path to the edited value are
+// rewritten, everything else is copied as it is in the input.
+package patch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/json-iterator/go"
+)
+
+var cfg = jsoniter.ConfigDefault
+
+// Operation is one RFC 6902 operation.
+// Value holds the raw JSON of the "value" member, From is only used by move and copy.
+type Operation struct {
+	Op    string              `json:"op"`
+	Path  string              `json:"path"`
+	From  string              `json:"from,omitempty"`
+	Value jsoniter.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a RFC 6902 JSON Patch document.
+type Patch []Operation
+
+// Decode parses a JSON Patch document, checking every operation has its mandatory members
+// and none of them twice, as RFC 6902 section A.13 expects.
+func Decode(data []byte) (Patch, error) {
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	patch := Patch{}
+	var err error
+	if iter.WhatIsNext() != jsoniter.ArrayValue {
+		return nil, errors.New("patch must be an array of operations")
+	}
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		var operation Operation
+		operation, err = decodeOperation(iter, len(patch))
+		if err != nil {
+			return false
+		}
+		patch = append(patch, operation)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIterator(iter); err != nil {
+		return nil, err
+	}
+	return patch, nil
+}
+
+func decodeOperation(iter *jsoniter.Iterator, index int) (Operation, error) {
+	var operation Operation
+	hasPath, hasFrom, hasValue := false, false, false
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return operation, fmt.Errorf("operation %d: must be an object", index)
+	}
+	fields := map[string]bool{}
+	repeated := ""
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		if fields[field] {
+			repeated = field
+			return false
+		}
+		fields[field] = true
+		switch field {
+		case "op":
+			operation.Op = iter.ReadString()
+		case "path":
+			operation.Path = iter.ReadString()
+			hasPath = true
+		case "from":
+			operation.From = iter.ReadString()
+			hasFrom = true
+		case "value":
+			operation.Value = readRawValue(iter)
+			hasValue = true
+		default:
+			iter.Skip()
+		}
+		return true
+	})
+	if repeated != "" {
+		return operation, fmt.Errorf("operation %d: repeated member %q", index, repeated)
+	}
+	if err := checkIterator(iter); err != nil {
+		return operation, err
+	}
+	if !hasPath {
+		return operation, fmt.Errorf("operation %d: missing path", index)
+	}
+	switch operation.Op {
+	case "add", "replace", "test":
+		if !hasValue {
+			return operation, fmt.Errorf("operation %d: %s requires a value", index, operation.Op)
+		}
+	case "move", "copy":
+		if !hasFrom {
+			return operation, fmt.Errorf("operation %d: %s requires from", index, operation.Op)
+		}
+	case "remove":
+	default:
+		return operation, fmt.Errorf("operation %d: unknown op %q", index, operation.Op)
+	}
+	return operation, nil
+}
+
+// Apply decodes patch and applies it to doc, see Patch.Apply.
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	decoded, err := Decode(patch)
+	if err != nil {
+		return nil, err
+	}
+	return decoded.Apply(doc)
+}
+
+// Apply applies the operations in order and returns the patched document.
+// The patch is atomic: on the first failing operation the error is returned and doc is left untouched.
+func (patch Patch) Apply(doc []byte) ([]byte, error) {
+	var raw jsoniter.RawMessage
+	if err := cfg.Unmarshal(doc, &raw); err != nil {
+		return nil, err
+	}
+	for i, operation := range patch {
+		var err error
+		doc, err = operation.apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %v", i, operation.Op, operation.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func (operation *Operation) apply(doc []byte) ([]byte, error) {
+	path, err := ParsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch operation.Op {
+	case "add":
+		return edit(doc, path, editAdd, operation.Value)
+	case "remove":
+		return edit(doc, path, editRemove, nil)
+	case "replace":
+		return edit(doc, path, editReplace, operation.Value)
+	case "move":
+		from, err := ParsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(from, path) {
+			if len(from) == len(path) {
+				return doc, nil
+			}
+			return nil, errors.New("can not move a value into one of its children")
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		doc, err = edit(doc, from, editRemove, nil)
+		if err != nil {
+			return nil, err
+		}
+		return edit(doc, path, editAdd, value)
+	case "copy":
+		from, err := ParsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return edit(doc, path, editAdd, value)
+	case "test":
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		equal, err := equalJSON(value, operation.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			return nil, fmt.Errorf("test failed, found %s", value)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", operation.Op)
+}
+
+func isPrefix(prefix []string, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i, token := range prefix {
+		if path[i] != token {
+			return false
+		}
+	}
+	return true
+}
+
+// equalJSON compares two JSON values semantically: numbers as decimals, so that 1.0 equals 1
+// but 9007199254740993 does not equal 9007199254740992, and objects regardless of member order.
+func equalJSON(a []byte, b []byte) (bool, error) {
+	var raw jsoniter.RawMessage
+	if err := cfg.Unmarshal(a, &raw); err != nil {
+		return false, err
+	}
+	if err := cfg.Unmarshal(b, &raw); err != nil {
+		return false, err
+	}
+	return jsoniter.Equal(cfg.Get(a), cfg.Get(b)), nil
+}
+
+type editMode int
+
+const (
+	editAdd editMode = iota
+	editReplace
+	editRemove
+)
+
+// edit rewrites doc with the value referenced by path added, replaced or removed.
+func edit(doc []byte, path []string, mode editMode, value []byte) ([]byte, error) {
+	if len(path) == 0 {
+		if mode == editRemove {
+			return nil, errors.New("can not remove the whole document")
+		}
+		return value, nil
+	}
+	iter := cfg.BorrowIterator(doc)
+	defer cfg.ReturnIterator(iter)
+	stream := cfg.BorrowStream(nil)
+	defer cfg.ReturnStream(stream)
+	editor := &editor{path: path, mode: mode, value: value}
+	editor.editValue(iter, stream, 0)
+	if editor.err != nil {
+		return nil, editor.err
+	}
+	if err := checkIterator(iter); err != nil {
+		return nil, err
+	}
+	if stream.Error != nil {
+		return nil, stream.Error
+	}
+	result := stream.Buffer()
+	copied := make([]byte, len(result))
+	copy(copied, result)
+	return copied, nil
+}
+
+type editor struct {
+	path  []string
+	mode  editMode
+	value []byte
+	err   error
+}
+
+func (editor *editor) editValue(iter *jsoniter.Iterator, stream *jsoniter.Stream, depth int) {
+	token := editor.path[depth]
+	last := depth == len(editor.path)-1
+	switch iter.WhatIsNext() {
+	case jsoniter.ObjectValue:
+		editor.editObject(iter, stream, depth, token, last)
+	case jsoniter.ArrayValue:
+		editor.editArray(iter, stream, depth, token, last)
+	default:
+		editor.err = fmt.Errorf("%s not found", FormatPointer(editor.path[:depth+1]...))
+	}
+}
+
+type objectMember struct {
+	field string
+	value []byte
+}
+
+// editObject edits the last member named token, the one a reader keeping the last of
+// repeated keys sees. The earlier ones are dropped when it is the edited value itself,
+// so that neither a removed key nor a replaced value reappears.
+func (editor *editor) editObject(iter *jsoniter.Iterator, stream *jsoniter.Stream, depth int, token string, last bool) {
+	members := []objectMember{}
+	found := -1
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		if field == token {
+			found = len(members)
+		}
+		members = append(members, objectMember{field, readRawValue(iter)})
+		return true
+	})
+	if err := checkIterator(iter); err != nil {
+		editor.err = err
+		return
+	}
+	if found == -1 && (!last || editor.mode != editAdd) {
+		editor.err = fmt.Errorf("%s not found", FormatPointer(editor.path[:depth+1]...))
+		return
+	}
+	first := true
+	writeField := func(field string) {
+		if !first {
+			stream.WriteMore()
+		}
+		first = false
+		stream.WriteObjectField(field)
+	}
+	stream.WriteObjectStart()
+	for i, member := range members {
+		switch {
+		case i == found && !last:
+			writeField(member.field)
+			editor.editMember(member.value, stream, depth+1)
+			if editor.err != nil {
+				return
+			}
+		case member.field == token && last:
+			if i == found && editor.mode != editRemove {
+				writeField(member.field)
+				stream.Write(editor.value)
+			}
+		default:
+			writeField(member.field)
+			stream.Write(member.value)
+		}
+	}
+	if found == -1 {
+		writeField(token)
+		stream.Write(editor.value)
+	}
+	stream.WriteObjectEnd()
+}
+
+// editMember edits the value of an object member, read beforehand.
+func (editor *editor) editMember(value []byte, stream *jsoniter.Stream, depth int) {
+	iter := cfg.BorrowIterator(value)
+	defer cfg.ReturnIterator(iter)
+	editor.editValue(iter, stream, depth)
+	if editor.err == nil {
+		editor.err = checkIterator(iter)
+	}
+}
+
+func (editor *editor) editArray(iter *jsoniter.Iterator, stream *jsoniter.Stream, depth int, token string, last bool) {
+	index, ok := parseArrayIndex(token)
+	if !ok {
+		if token != "-" || !last || editor.mode != editAdd {
+			editor.err = fmt.Errorf("invalid array index %q in %s", token, FormatPointer(editor.path[:depth+1]...))
+			return
+		}
+		index = -1
+	}
+	first := true
+	writeElement := func(element []byte) {
+		if !first {
+			stream.WriteMore()
+		}
+		first = false
+		stream.Write(element)
+	}
+	i := 0
+	found := false
+	stream.WriteArrayStart()
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		defer func() { i++ }()
+		if i != index {
+			writeElement(readRawValue(iter))
+			return true
+		}
+		found = true
+		if !last {
+			if !first {
+				stream.WriteMore()
+			}
+			first = false
+			editor.editValue(iter, stream, depth+1)
+			return editor.err == nil
+		}
+		switch editor.mode {
+		case editAdd:
+			writeElement(editor.value)
+			writeElement(readRawValue(iter))
+		case editReplace:
+			iter.Skip()
+			writeElement(editor.value)
+		case editRemove:
+			iter.Skip()
+		}
+		return true
+	})
+	if editor.err != nil {
+		return
+	}
+	if !found {
+		if !last || editor.mode != editAdd || (index != -1 && index != i) {
+			editor.err = fmt.Errorf("%s out of range", FormatPointer(editor.path[:depth+1]...))
+			return
+		}
+		writeElement(editor.value)
+	}
+	stream.WriteArrayEnd()
+}
@@ -0,0 +1,180 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6902Examples are the examples of RFC 6902 appendix A.
+var rfc6902Examples = []struct {
+	name     string
+	doc      string
+	patch    string
+	expected string
+}{
+	{"A.1 adding an object member",
+		`{"foo":"bar"}`,
+		`[{"op":"add","path":"/baz","value":"qux"}]`,
+		`{"baz":"qux","foo":"bar"}`},
+	{"A.2 adding an array element",
+		`{"foo":["bar","baz"]}`,
+		`[{"op":"add","path":"/foo/1","value":"qux"}]`,
+		`{"foo":["bar","qux","baz"]}`},
+	{"A.3 removing an object member",
+		`{"baz":"qux","foo":"bar"}`,
+		`[{"op":"remove","path":"/baz"}]`,
+		`{"foo":"bar"}`},
+	{"A.4 removing an array element",
+		`{"foo":["bar","qux","baz"]}`,
+		`[{"op":"remove","path":"/foo/1"}]`,
+		`{"foo":["bar","baz"]}`},
+	{"A.5 replacing a value",
+		`{"baz":"qux","foo":"bar"}`,
+		`[{"op":"replace","path":"/baz","value":"boo"}]`,
+		`{"baz":"boo","foo":"bar"}`},
+	{"A.6 moving a value",
+		`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+		`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+		`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+	{"A.7 moving an array element",
+		`{"foo":["all","grass","cows","eat"]}`,
+		`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+		`{"foo":["all","cows","eat","grass"]}`},
+	{"A.8 testing a value: success",
+		`{"baz":"qux","foo":["a",2,"c"]}`,
+		`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+		`{"baz":"qux","foo":["a",2,"c"]}`},
+	{"A.10 adding a nested member object",
+		`{"foo":"bar"}`,
+		`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+		`{"foo":"bar","child":{"grandchild":{}}}`},
+	{"A.11 ignoring unrecognized elements",
+		`{"foo":"bar"}`,
+		`[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+		`{"foo":"bar","baz":"qux"}`},
+	{"A.14 ~ escape ordering",
+		`{"/":9,"~1":10}`,
+		`[{"op":"test","path":"/~01","value":10}]`,
+		`{"/":9,"~1":10}`},
+	{"A.16 adding an array value",
+		`{"foo":["bar"]}`,
+		`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+		`{"foo":["bar",["abc","def"]]}`},
+	{"replacing the whole document",
+		`{"foo":"bar"}`,
+		`[{"op":"replace","path":"","value":[1,2]}]`,
+		`[1,2]`},
+	{"copying a value",
+		`{"foo":{"bar":[1,2]},"baz":{}}`,
+		`[{"op":"copy","from":"/foo/bar","path":"/baz/bar"},{"op":"add","path":"/baz/bar/0","value":0}]`,
+		`{"foo":{"bar":[1,2]},"baz":{"bar":[0,1,2]}}`},
+}
+
+func Test_apply_rfc6902_examples(t *testing.T) {
+	for _, example := range rfc6902Examples {
+		t.Run(example.name, func(t *testing.T) {
+			should := require.New(t)
+			output, err := Apply([]byte(example.doc), []byte(example.patch))
+			should.NoError(err)
+			equal, err := equalJSON([]byte(example.expected), output)
+			should.NoError(err)
+			should.True(equal, string(output))
+		})
+	}
+}
+
+// rfc6902Errors are the failing examples of RFC 6902 appendix A, plus a few more.
+var rfc6902Errors = []struct {
+	name  string
+	doc   string
+	patch string
+}{
+	{"A.9 testing a value: error",
+		`{"baz":"qux"}`,
+		`[{"op":"test","path":"/baz","value":"bar"}]`},
+	{"A.12 adding to a nonexistent target",
+		`{"foo":"bar"}`,
+		`[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+	{"A.13 invalid JSON patch document",
+		`{"foo":"bar","baz":"qux"}`,
+		`[{"op":"add","path":"/baz","value":"qux","op":"remove"}]`},
+	{"A.15 comparing strings and numbers",
+		`{"/":9,"~1":10}`,
+		`[{"op":"test","path":"/~01","value":"10"}]`},
+	{"removing a missing member",
+		`{"foo":"bar"}`,
+		`[{"op":"remove","path":"/baz"}]`},
+	{"index with leading zero",
+		`{"foo":[1,2]}`,
+		`[{"op":"replace","path":"/foo/01","value":3}]`},
+	{"index out of range",
+		`{"foo":[1,2]}`,
+		`[{"op":"add","path":"/foo/3","value":3}]`},
+	{"moving into a child",
+		`{"foo":{"bar":1}}`,
+		`[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`},
+	{"missing value",
+		`{"foo":"bar"}`,
+		`[{"op":"add","path":"/baz"}]`},
+	{"unknown op",
+		`{"foo":"bar"}`,
+		`[{"op":"merge","path":"/baz","value":1}]`},
+	{"testing a large integer by its digits",
+		`{"n":9007199254740993}`,
+		`[{"op":"test","path":"/n","value":9007199254740992}]`},
+	{"invalid document",
+		`{"foo":`,
+		`[{"op":"add","path":"/baz","value":1}]`},
+}
+
+func Test_apply_rfc6902_errors(t *testing.T) {
+	for _, example := range rfc6902Errors {
+		t.Run(example.name, func(t *testing.T) {
+			should := require.New(t)
+			_, err := Apply([]byte(example.doc), []byte(example.patch))
+			should.Error(err)
+		})
+	}
+}
+
+func Test_decode_rejects_repeated_members(t *testing.T) {
+	should := require.New(t)
+	_, err := Decode([]byte(`[{"op":"remove","path":"/a"},{"op":"add","path":"/baz","value":"qux","op":"remove"}]`))
+	should.EqualError(err, `operation 1: repeated member "op"`)
+	_, err = Decode([]byte(`[{"op":"test","path":"/a","value":1,"x":1,"x":2}]`))
+	should.EqualError(err, `operation 0: repeated member "x"`)
+}
+
+func Test_apply_keeps_untouched_values_as_is(t *testing.T) {
+	should := require.New(t)
+	output, err := Apply([]byte(`{"a": [1, 2.50, 3], "b": {"c": 1}}`), []byte(`[{"op":"replace","path":"/b/c","value":2}]`))
+	should.NoError(err)
+	should.Equal(`{"a":[1, 2.50, 3],"b":{"c":2}}`, string(output))
+}
+
+func Test_apply_repeated_keys(t *testing.T) {
+	testCases := []struct {
+		patch    string
+		expected string
+	}{
+		{`[{"op":"test","path":"/a","value":2}]`, `{"a":1,"b":{"c":1,"c":2},"a":2}`},
+		{`[{"op":"replace","path":"/a","value":3}]`, `{"b":{"c":1,"c":2},"a":3}`},
+		{`[{"op":"remove","path":"/a"}]`, `{"b":{"c":1,"c":2}}`},
+		{`[{"op":"add","path":"/a","value":3}]`, `{"b":{"c":1,"c":2},"a":3}`},
+		{`[{"op":"replace","path":"/b/c","value":3}]`, `{"a":1,"b":{"c":3},"a":2}`},
+		{`[{"op":"copy","from":"/a","path":"/d"}]`, `{"a":1,"b":{"c":1,"c":2},"a":2,"d":2}`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.patch, func(t *testing.T) {
+			should := require.New(t)
+			output, err := Apply([]byte(`{"a":1,"b":{"c":1,"c":2},"a":2}`), []byte(testCase.patch))
+			should.NoError(err)
+			should.Equal(testCase.expected, string(output))
+		})
+	}
+	should := require.New(t)
+	value, err := Get([]byte(`{"a":1,"a":{"b":2}}`), "/a/b")
+	should.NoError(err)
+	should.Equal(`2`, string(value))
+}
@@ -0,0 +1,134 @@
+package patch
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/json-iterator/go"
+)
+
+// ParsePointer splits a RFC 6901 JSON Pointer into its unescaped reference tokens.
+// The empty pointer references the whole document and yields no token.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if strings.IndexByte(token, '~') == -1 {
+			continue
+		}
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON pointer %q: bad escape in %q", pointer, token)
+			}
+		}
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// FormatPointer builds a RFC 6901 JSON Pointer from unescaped reference tokens.
+func FormatPointer(tokens ...string) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteByte('/')
+		builder.WriteString(strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1))
+	}
+	return builder.String()
+}
+
+// parseArrayIndex validates an array reference token, which must not have leading zeros.
+func parseArrayIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
+// Get returns the raw bytes of the value referenced by pointer in doc,
+// the last one of a repeated key as jsoniter reads by default.
+func Get(doc []byte, pointer string) ([]byte, error) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return get(doc, tokens)
+}
+
+func get(doc []byte, tokens []string) ([]byte, error) {
+	iter := cfg.BorrowIterator(doc)
+	defer cfg.ReturnIterator(iter)
+	for depth, token := range tokens {
+		var found []byte
+		switch iter.WhatIsNext() {
+		case jsoniter.ObjectValue:
+			iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+				// the last of repeated keys wins, as editing it does
+				if field == token {
+					found = readRawValue(iter)
+					return true
+				}
+				iter.Skip()
+				return true
+			})
+		case jsoniter.ArrayValue:
+			index, ok := parseArrayIndex(token)
+			if !ok {
+				return nil, fmt.Errorf("invalid array index %q in %s", token, FormatPointer(tokens[:depth+1]...))
+			}
+			i := 0
+			iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+				if i == index {
+					found = readRawValue(iter)
+					return false
+				}
+				iter.Skip()
+				i++
+				return true
+			})
+		default:
+			return nil, fmt.Errorf("%s not found", FormatPointer(tokens[:depth+1]...))
+		}
+		if err := checkIterator(iter); err != nil {
+			return nil, err
+		}
+		if found == nil {
+			return nil, fmt.Errorf("%s not found", FormatPointer(tokens[:depth+1]...))
+		}
+		iter.Error = nil
+		iter.ResetBytes(found)
+	}
+	found := readRawValue(iter)
+	if err := checkIterator(iter); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// readRawValue skips the leading whitespaces, then returns the next value as it is in the input.
+func readRawValue(iter *jsoniter.Iterator) []byte {
+	iter.WhatIsNext()
+	return iter.SkipAndReturnBytes()
+}
+
+func checkIterator(iter *jsoniter.Iterator) error {
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6901Document is the example document of RFC 6901 section 5.
+const rfc6901Document = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "c%d": 2,
+  "e^f": 3,
+  "g|h": 4,
+  "i\\j": 5,
+  "k\"l": 6,
+  " ": 7,
+  "m~n": 8
+}`
+
+func Test_get_rfc6901_examples(t *testing.T) {
+	examples := map[string]string{
+		"":       rfc6901Document,
+		"/foo":   `["bar", "baz"]`,
+		"/foo/0": `"bar"`,
+		"/":      `0`,
+		"/a~1b":  `1`,
+		"/c%d":   `2`,
+		"/e^f":   `3`,
+		"/g|h":   `4`,
+		"/i\\j":  `5`,
+		"/k\"l":  `6`,
+		"/ ":     `7`,
+		"/m~0n":  `8`,
+	}
+	for pointer, expected := range examples {
+		t.Run(pointer, func(t *testing.T) {
+			should := require.New(t)
+			found, err := Get([]byte(rfc6901Document), pointer)
+			should.NoError(err)
+			should.Equal(expected, string(found))
+		})
+	}
+}
+
+func Test_get_missing(t *testing.T) {
+	should := require.New(t)
+	for _, pointer := range []string{"foo", "/bar", "/foo/2", "/foo/-", "/foo/01", "/foo/0/x", "/m~2n"} {
+		_, err := Get([]byte(rfc6901Document), pointer)
+		should.Error(err, pointer)
+	}
+}
+
+func Test_format_pointer(t *testing.T) {
+	should := require.New(t)
+	should.Equal("", FormatPointer())
+	should.Equal("/a~1b/m~0n/0", FormatPointer("a/b", "m~n", "0"))
+	tokens, err := ParsePointer("/a~1b/m~0n/0")
+	should.NoError(err)
+	should.Equal([]string{"a/b", "m~n", "0"}, tokens)
+}
@@ -26,6 +26,7 @@ func (cfg *frozenConfig) ReturnStream(stream *Stream) {
 	stream.out = nil
 	stream.Error = nil
 	stream.Attachment = nil
+	stream.checked = false
 	cfg.streamPool.Put(stream)
 }
 
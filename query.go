@@ -0,0 +1,783 @@
+package jsoniter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Query evaluates a JSONPath expression against data and returns every matched value.
+// Supported syntax: $ root, .name and ['name'] children, * wildcard, .. recursive descent,
+// [n] (negative from the end) indexes, [start:end:step] slices, [a,b] unions and
+// [?(expr)] filters using @ for the current node, comparisons, &&, || and !.
+// Values are returned as lazy Any, untouched sections of data are never decoded.
+func (cfg *frozenConfig) Query(data []byte, expr string) ([]Any, error) {
+	path, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	iter := cfg.BorrowIterator(data)
+	defer cfg.ReturnIterator(iter)
+	prefix, steps := path.literalPrefix()
+	if !steps.refersToRoot() {
+		node := locatePath(iter, prefix)
+		if iter.Error != nil && iter.Error != io.EOF {
+			return nil, iter.Error
+		}
+		if node.ValueType() == InvalidValue {
+			return []Any{}, nil
+		}
+		return steps.evaluate(node, node), nil
+	}
+	// a filter reads $ from the document, which must be kept beside the node of the prefix
+	root := iter.readAny()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, iter.Error
+	}
+	node := root.Get(prefix...)
+	if node.ValueType() == InvalidValue {
+		return []Any{}, nil
+	}
+	return steps.evaluate(root, node), nil
+}
+
+// jsonPath is a compiled JSONPath expression, a sequence of steps applied from the root.
+type jsonPath []*jsonPathStep
+
+type jsonPathStep struct {
+	recursive bool
+	selectors []jsonPathSelector
+}
+
+type jsonPathSelector interface {
+	selectFrom(root Any, node Any, found []Any) []Any
+}
+
+// literalPrefix splits off the leading steps which can be located by locatePath directly,
+// without materializing the intermediate values.
+func (path jsonPath) literalPrefix() ([]interface{}, jsonPath) {
+	prefix := []interface{}{}
+	for i, step := range path {
+		if step.recursive || len(step.selectors) != 1 {
+			return prefix, path[i:]
+		}
+		switch selector := step.selectors[0].(type) {
+		case *jsonPathNameSelector:
+			prefix = append(prefix, selector.name)
+		case *jsonPathIndexSelector:
+			if selector.index < 0 {
+				return prefix, path[i:]
+			}
+			prefix = append(prefix, selector.index)
+		default:
+			return prefix, path[i:]
+		}
+	}
+	return prefix, nil
+}
+
+// refersToRoot tells if a filter of path queries $, the root of the document.
+func (path jsonPath) refersToRoot() bool {
+	for _, step := range path {
+		for _, selector := range step.selectors {
+			if filter, isFilter := selector.(*jsonPathFilterSelector); isFilter && exprRefersToRoot(filter.expr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func exprRefersToRoot(expr jsonPathExpr) bool {
+	switch expr := expr.(type) {
+	case *jsonPathQueryExpr:
+		return expr.absolute || expr.path.refersToRoot()
+	case *jsonPathCompareExpr:
+		return exprRefersToRoot(expr.left) || exprRefersToRoot(expr.right)
+	case *jsonPathLogicalExpr:
+		return exprRefersToRoot(expr.left) || exprRefersToRoot(expr.right)
+	case *jsonPathNotExpr:
+		return exprRefersToRoot(expr.expr)
+	}
+	return false
+}
+
+func (path jsonPath) evaluate(root Any, node Any) []Any {
+	nodes := []Any{node}
+	for _, step := range path {
+		var found []Any
+		for _, current := range nodes {
+			found = step.apply(root, current, found)
+		}
+		if len(found) == 0 {
+			return []Any{}
+		}
+		nodes = found
+	}
+	return nodes
+}
+
+func (step *jsonPathStep) apply(root Any, node Any, found []Any) []Any {
+	for _, selector := range step.selectors {
+		found = selector.selectFrom(root, node, found)
+	}
+	if step.recursive {
+		forEachJSONPathChild(node, func(key string, child Any) {
+			found = step.apply(root, child, found)
+		})
+	}
+	return found
+}
+
+// forEachJSONPathChild visits the members of an object or the elements of an array.
+func forEachJSONPathChild(node Any, callback func(key string, child Any)) {
+	node.ForEach(func(key string, child Any) bool {
+		callback(key, child)
+		return true
+	})
+	node.ForEachIndex(func(index int, child Any) bool {
+		callback("", child)
+		return true
+	})
+}
+
+type jsonPathNameSelector struct {
+	name string
+}
+
+func (selector *jsonPathNameSelector) selectFrom(root Any, node Any, found []Any) []Any {
+	if node.ValueType() != ObjectValue {
+		return found
+	}
+	child := node.Get(selector.name)
+	if child.ValueType() == InvalidValue {
+		return found
+	}
+	return append(found, child)
+}
+
+type jsonPathWildcardSelector struct {
+}
+
+func (selector *jsonPathWildcardSelector) selectFrom(root Any, node Any, found []Any) []Any {
+	forEachJSONPathChild(node, func(key string, child Any) {
+		found = append(found, child)
+	})
+	return found
+}
+
+type jsonPathIndexSelector struct {
+	index int
+}
+
+func (selector *jsonPathIndexSelector) selectFrom(root Any, node Any, found []Any) []Any {
+	if node.ValueType() != ArrayValue {
+		return found
+	}
+	index := selector.index
+	if index < 0 {
+		index += node.Size()
+		if index < 0 {
+			return found
+		}
+	}
+	child := node.Get(index)
+	if child.ValueType() == InvalidValue {
+		return found
+	}
+	return append(found, child)
+}
+
+type jsonPathSliceSelector struct {
+	start *int
+	end   *int
+	step  int
+}
+
+func (selector *jsonPathSliceSelector) selectFrom(root Any, node Any, found []Any) []Any {
+	if node.ValueType() != ArrayValue || selector.step == 0 {
+		return found
+	}
+	elements := []Any{}
+	forEachJSONPathChild(node, func(key string, child Any) {
+		elements = append(elements, child)
+	})
+	size := len(elements)
+	normalize := func(bound *int, defaultValue int) int {
+		if bound == nil {
+			return defaultValue
+		}
+		if *bound < 0 {
+			return *bound + size
+		}
+		return *bound
+	}
+	clamp := func(i, lower, upper int) int {
+		if i < lower {
+			return lower
+		}
+		if i > upper {
+			return upper
+		}
+		return i
+	}
+	if selector.step > 0 {
+		lower := clamp(normalize(selector.start, 0), 0, size)
+		upper := clamp(normalize(selector.end, size), 0, size)
+		for i := lower; i < upper; i += selector.step {
+			found = append(found, elements[i])
+		}
+		return found
+	}
+	upper := clamp(normalize(selector.start, size-1), -1, size-1)
+	lower := clamp(normalize(selector.end, -size-1), -1, size-1)
+	for i := upper; lower < i; i += selector.step {
+		found = append(found, elements[i])
+	}
+	return found
+}
+
+type jsonPathFilterSelector struct {
+	expr jsonPathExpr
+}
+
+func (selector *jsonPathFilterSelector) selectFrom(root Any, node Any, found []Any) []Any {
+	forEachJSONPathChild(node, func(key string, child Any) {
+		if selector.expr.test(root, child) {
+			found = append(found, child)
+		}
+	})
+	return found
+}
+
+// jsonPathExpr is a node of a filter expression.
+// value returns the operand used by comparisons, test the truthiness used by filters.
+type jsonPathExpr interface {
+	value(root Any, node Any) (Any, bool)
+	test(root Any, node Any) bool
+}
+
+type jsonPathQueryExpr struct {
+	absolute bool
+	path     jsonPath
+}
+
+func (expr *jsonPathQueryExpr) value(root Any, node Any) (Any, bool) {
+	if expr.absolute {
+		node = root
+	}
+	found := expr.path.evaluate(root, node)
+	if len(found) != 1 {
+		return nil, false
+	}
+	return found[0], true
+}
+
+func (expr *jsonPathQueryExpr) test(root Any, node Any) bool {
+	if expr.absolute {
+		node = root
+	}
+	return len(expr.path.evaluate(root, node)) != 0
+}
+
+type jsonPathLiteralExpr struct {
+	val Any
+}
+
+func (expr *jsonPathLiteralExpr) value(root Any, node Any) (Any, bool) {
+	return expr.val, true
+}
+
+func (expr *jsonPathLiteralExpr) test(root Any, node Any) bool {
+	return expr.val.ToBool()
+}
+
+type jsonPathCompareExpr struct {
+	op    string
+	left  jsonPathExpr
+	right jsonPathExpr
+}
+
+func (expr *jsonPathCompareExpr) value(root Any, node Any) (Any, bool) {
+	return jsonPathBool(expr.test(root, node)), true
+}
+
+func (expr *jsonPathCompareExpr) test(root Any, node Any) bool {
+	left, leftFound := expr.left.value(root, node)
+	right, rightFound := expr.right.value(root, node)
+	if !leftFound || !rightFound {
+		// an empty result only equals another empty result
+		switch expr.op {
+		case "==", "<=", ">=":
+			return !leftFound && !rightFound
+		case "!=":
+			return leftFound || rightFound
+		}
+		return false
+	}
+	switch expr.op {
+	case "==":
+		return jsonPathEqual(left, right)
+	case "!=":
+		return !jsonPathEqual(left, right)
+	case "<":
+		return jsonPathLess(left, right)
+	case ">":
+		return jsonPathLess(right, left)
+	case "<=":
+		return jsonPathLess(left, right) || jsonPathEqual(left, right)
+	case ">=":
+		return jsonPathLess(right, left) || jsonPathEqual(left, right)
+	}
+	return false
+}
+
+type jsonPathLogicalExpr struct {
+	and   bool
+	left  jsonPathExpr
+	right jsonPathExpr
+}
+
+func (expr *jsonPathLogicalExpr) value(root Any, node Any) (Any, bool) {
+	return jsonPathBool(expr.test(root, node)), true
+}
+
+func (expr *jsonPathLogicalExpr) test(root Any, node Any) bool {
+	if expr.and {
+		return expr.left.test(root, node) && expr.right.test(root, node)
+	}
+	return expr.left.test(root, node) || expr.right.test(root, node)
+}
+
+type jsonPathNotExpr struct {
+	expr jsonPathExpr
+}
+
+func (expr *jsonPathNotExpr) value(root Any, node Any) (Any, bool) {
+	return jsonPathBool(expr.test(root, node)), true
+}
+
+func (expr *jsonPathNotExpr) test(root Any, node Any) bool {
+	return !expr.expr.test(root, node)
+}
+
+func jsonPathBool(val bool) Any {
+	if val {
+		return &trueAny{baseAny{BoolValue}}
+	}
+	return &falseAny{baseAny{BoolValue}}
+}
+
+func jsonPathEqual(left Any, right Any) bool {
+	if left.ValueType() != right.ValueType() {
+		return false
+	}
+	switch left.ValueType() {
+	case NumberValue:
+		return compareDecimals(anyDecimalOf(left), anyDecimalOf(right)) == 0
+	case StringValue:
+		return left.ToString() == right.ToString()
+	case BoolValue:
+		return left.ToBool() == right.ToBool()
+	case NilValue:
+		return true
+	}
+	return reflect.DeepEqual(left.GetInterface(), right.GetInterface())
+}
+
+func jsonPathLess(left Any, right Any) bool {
+	if left.ValueType() != right.ValueType() {
+		return false
+	}
+	switch left.ValueType() {
+	case NumberValue:
+		return compareDecimals(anyDecimalOf(left), anyDecimalOf(right)) < 0
+	case StringValue:
+		return left.ToString() < right.ToString()
+	}
+	return false
+}
+
+type jsonPathParser struct {
+	expr string
+	pos  int
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	parser := &jsonPathParser{expr: expr}
+	parser.skipWhitespaces()
+	if !parser.consume('$') {
+		return nil, parser.errorf("expect $ at the beginning of the path")
+	}
+	path, err := parser.parseSteps()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipWhitespaces()
+	if parser.pos != len(parser.expr) {
+		return nil, parser.errorf("unexpected %q", parser.expr[parser.pos:])
+	}
+	return path, nil
+}
+
+func (parser *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid JSONPath %q at offset %d: %s", parser.expr, parser.pos, fmt.Sprintf(format, args...))
+}
+
+func (parser *jsonPathParser) peek() byte {
+	if parser.pos < len(parser.expr) {
+		return parser.expr[parser.pos]
+	}
+	return 0
+}
+
+func (parser *jsonPathParser) consume(c byte) bool {
+	if parser.peek() == c {
+		parser.pos++
+		return true
+	}
+	return false
+}
+
+func (parser *jsonPathParser) consumeString(str string) bool {
+	if strings.HasPrefix(parser.expr[parser.pos:], str) {
+		parser.pos += len(str)
+		return true
+	}
+	return false
+}
+
+func (parser *jsonPathParser) skipWhitespaces() {
+	for parser.pos < len(parser.expr) {
+		switch parser.expr[parser.pos] {
+		case ' ', '\n', '\t', '\r':
+			parser.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (parser *jsonPathParser) parseSteps() (jsonPath, error) {
+	path := jsonPath{}
+	for {
+		switch {
+		case parser.consumeString(".."):
+			step, err := parser.parseDotStep()
+			if err != nil {
+				return nil, err
+			}
+			step.recursive = true
+			path = append(path, step)
+		case parser.consume('.'):
+			step, err := parser.parseDotStep()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, step)
+		case parser.peek() == '[':
+			step, err := parser.parseBracketStep()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, step)
+		default:
+			return path, nil
+		}
+	}
+}
+
+func (parser *jsonPathParser) parseDotStep() (*jsonPathStep, error) {
+	if parser.peek() == '[' {
+		return parser.parseBracketStep()
+	}
+	if parser.consume('*') {
+		return &jsonPathStep{selectors: []jsonPathSelector{&jsonPathWildcardSelector{}}}, nil
+	}
+	start := parser.pos
+	for parser.pos < len(parser.expr) && isJSONPathNameChar(parser.expr[parser.pos]) {
+		parser.pos++
+	}
+	if start == parser.pos {
+		return nil, parser.errorf("expect member name after .")
+	}
+	name := parser.expr[start:parser.pos]
+	return &jsonPathStep{selectors: []jsonPathSelector{&jsonPathNameSelector{name}}}, nil
+}
+
+func isJSONPathNameChar(c byte) bool {
+	return c >= 0x80 || c == '_' || c == '-' || c == '$' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func (parser *jsonPathParser) parseBracketStep() (*jsonPathStep, error) {
+	parser.consume('[')
+	step := &jsonPathStep{}
+	for {
+		parser.skipWhitespaces()
+		selector, err := parser.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		step.selectors = append(step.selectors, selector)
+		parser.skipWhitespaces()
+		if parser.consume(']') {
+			return step, nil
+		}
+		if !parser.consume(',') {
+			return nil, parser.errorf("expect , or ] in selector list")
+		}
+	}
+}
+
+func (parser *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := parser.peek(); {
+	case c == '*':
+		parser.pos++
+		return &jsonPathWildcardSelector{}, nil
+	case c == '\'' || c == '"':
+		name, err := parser.parseQuotedString()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathNameSelector{name}, nil
+	case c == '?':
+		parser.pos++
+		parser.skipWhitespaces()
+		expr, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathFilterSelector{expr}, nil
+	case c == '-' || c == ':' || ('0' <= c && c <= '9'):
+		return parser.parseIndexOrSlice()
+	}
+	return nil, parser.errorf("unexpected selector")
+}
+
+func (parser *jsonPathParser) parseIndexOrSlice() (jsonPathSelector, error) {
+	bounds := [3]*int{}
+	colons := 0
+	for {
+		parser.skipWhitespaces()
+		if parser.peek() == '-' || ('0' <= parser.peek() && parser.peek() <= '9') {
+			val, err := parser.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[colons] = &val
+			parser.skipWhitespaces()
+		}
+		if colons == 2 || !parser.consume(':') {
+			break
+		}
+		colons++
+	}
+	if colons == 0 {
+		if bounds[0] == nil {
+			return nil, parser.errorf("expect index")
+		}
+		return &jsonPathIndexSelector{*bounds[0]}, nil
+	}
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return &jsonPathSliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+func (parser *jsonPathParser) parseInt() (int, error) {
+	start := parser.pos
+	parser.consume('-')
+	for '0' <= parser.peek() && parser.peek() <= '9' {
+		parser.pos++
+	}
+	val, err := strconv.Atoi(parser.expr[start:parser.pos])
+	if err != nil {
+		return 0, parser.errorf("invalid integer %q", parser.expr[start:parser.pos])
+	}
+	return val, nil
+}
+
+func (parser *jsonPathParser) parseNumber() (Any, error) {
+	start := parser.pos
+	for parser.pos < len(parser.expr) {
+		c := parser.expr[parser.pos]
+		if c != '-' && c != '+' && c != '.' && c != 'e' && c != 'E' && (c < '0' || c > '9') {
+			break
+		}
+		parser.pos++
+	}
+	text := parser.expr[start:parser.pos]
+	val, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, parser.errorf("invalid number %q", text)
+	}
+	if json.Valid([]byte(text)) {
+		// kept as read, to compare it as a decimal beyond the precision of float64
+		return ConfigDefault.Get([]byte(text)), nil
+	}
+	return WrapFloat64(val), nil
+}
+
+func (parser *jsonPathParser) parseQuotedString() (string, error) {
+	quote := parser.expr[parser.pos]
+	parser.pos++
+	var str []byte
+	for parser.pos < len(parser.expr) {
+		c := parser.expr[parser.pos]
+		parser.pos++
+		if c == quote {
+			return string(str), nil
+		}
+		if c != '\\' {
+			str = append(str, c)
+			continue
+		}
+		if parser.pos == len(parser.expr) {
+			break
+		}
+		c = parser.expr[parser.pos]
+		parser.pos++
+		switch c {
+		case 'b':
+			str = append(str, '\b')
+		case 'f':
+			str = append(str, '\f')
+		case 'n':
+			str = append(str, '\n')
+		case 'r':
+			str = append(str, '\r')
+		case 't':
+			str = append(str, '\t')
+		case 'u':
+			if parser.pos+4 > len(parser.expr) {
+				return "", parser.errorf("incomplete unicode escape")
+			}
+			r, err := strconv.ParseUint(parser.expr[parser.pos:parser.pos+4], 16, 16)
+			if err != nil {
+				return "", parser.errorf("invalid unicode escape")
+			}
+			parser.pos += 4
+			str = appendRune(str, rune(r))
+		default:
+			str = append(str, c)
+		}
+	}
+	return "", parser.errorf("string not terminated")
+}
+
+func (parser *jsonPathParser) parseOr() (jsonPathExpr, error) {
+	left, err := parser.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		parser.skipWhitespaces()
+		if !parser.consumeString("||") {
+			return left, nil
+		}
+		right, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &jsonPathLogicalExpr{and: false, left: left, right: right}
+	}
+}
+
+func (parser *jsonPathParser) parseAnd() (jsonPathExpr, error) {
+	left, err := parser.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		parser.skipWhitespaces()
+		if !parser.consumeString("&&") {
+			return left, nil
+		}
+		right, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &jsonPathLogicalExpr{and: true, left: left, right: right}
+	}
+}
+
+func (parser *jsonPathParser) parseUnary() (jsonPathExpr, error) {
+	parser.skipWhitespaces()
+	if parser.peek() == '!' && !strings.HasPrefix(parser.expr[parser.pos:], "!=") {
+		parser.pos++
+		expr, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathNotExpr{expr}, nil
+	}
+	if parser.consume('(') {
+		expr, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		parser.skipWhitespaces()
+		if !parser.consume(')') {
+			return nil, parser.errorf("expect ) to close the expression")
+		}
+		return expr, nil
+	}
+	return parser.parseComparison()
+}
+
+var jsonPathCompareOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (parser *jsonPathParser) parseComparison() (jsonPathExpr, error) {
+	left, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipWhitespaces()
+	for _, op := range jsonPathCompareOps {
+		if parser.consumeString(op) {
+			parser.skipWhitespaces()
+			right, err := parser.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &jsonPathCompareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (parser *jsonPathParser) parseOperand() (jsonPathExpr, error) {
+	switch c := parser.peek(); {
+	case c == '@' || c == '$':
+		parser.pos++
+		path, err := parser.parseSteps()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathQueryExpr{absolute: c == '$', path: path}, nil
+	case c == '\'' || c == '"':
+		str, err := parser.parseQuotedString()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathLiteralExpr{WrapString(str)}, nil
+	case c == '-' || ('0' <= c && c <= '9'):
+		val, err := parser.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathLiteralExpr{val}, nil
+	case parser.consumeString("true"):
+		return &jsonPathLiteralExpr{&trueAny{baseAny{BoolValue}}}, nil
+	case parser.consumeString("false"):
+		return &jsonPathLiteralExpr{&falseAny{baseAny{BoolValue}}}, nil
+	case parser.consumeString("null"):
+		return &jsonPathLiteralExpr{&nilAny{baseAny{NilValue}}}, nil
+	}
+	return nil, parser.errorf("unexpected operand")
+}
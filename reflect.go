@@ -60,6 +60,10 @@ func (b *ctx) append(prefix string) *ctx {
 
 // ReadVal copy the underlying JSON into go interface, same as json.Unmarshal
 func (iter *Iterator) ReadVal(obj interface{}) {
+	tokenLevel := iter.isTokenLevel()
+	if tokenLevel && !iter.tokenPrepareForDecode() {
+		return
+	}
 	depth := iter.depth
 	cacheKey := reflect2.RTypeOf(obj)
 	decoder := iter.cfg.getDecoderFromCache(cacheKey)
@@ -76,11 +80,18 @@ func (iter *Iterator) ReadVal(obj interface{}) {
 		iter.ReportError("ReadVal", "can not read into nil pointer")
 		return
 	}
+	start, failed := iter.offset(), iter.hasDecodeError()
 	decoder.Decode(ptr, iter)
+	if !failed && iter.hasDecodeError() {
+		iter.addErrorValue(reflect2.TypeOf(obj).(*reflect2.UnsafePtrType).Elem(), start)
+	}
 	if iter.depth != depth {
 		iter.ReportError("ReadVal", "unexpected mismatched nesting")
 		return
 	}
+	if tokenLevel {
+		iter.tokenValueEnd()
+	}
 }
 
 // WriteVal copy the go interface into underlying JSON, same as json.Marshal
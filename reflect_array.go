@@ -62,9 +62,10 @@ type arrayDecoder struct {
 }
 
 func (decoder *arrayDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	mark := iter.errorMark()
 	decoder.doDecode(ptr, iter)
-	if iter.Error != nil && iter.Error != io.EOF {
-		iter.Error = fmt.Errorf("%v: %s", decoder.arrayType, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorContainer(decoder.arrayType, mark)
 	}
 }
 
@@ -83,11 +84,19 @@ func (decoder *arrayDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 	if c == ']' {
 		return
 	}
+	if !iter.incrementDepth() {
+		return
+	}
 	iter.unreadByte()
 	elemPtr := arrayType.UnsafeGetIndex(ptr, 0)
-	decoder.elemDecoder.Decode(elemPtr, iter)
+	decoder.decodeElem(elemPtr, 0, iter)
 	length := 1
+	elements := 1
 	for c = iter.nextToken(); c == ','; c = iter.nextToken() {
+		elements++
+		if !iter.checkArrayElements("decode array", elements) {
+			break
+		}
 		if length >= arrayType.Len() {
 			iter.Skip()
 			continue
@@ -95,10 +104,18 @@ func (decoder *arrayDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		idx := length
 		length += 1
 		elemPtr = arrayType.UnsafeGetIndex(ptr, idx)
-		decoder.elemDecoder.Decode(elemPtr, iter)
+		decoder.decodeElem(elemPtr, idx, iter)
 	}
 	if c != ']' {
 		iter.ReportError("decode array", "expect ], but found "+string([]byte{c}))
-		return
+	}
+	iter.decrementDepth()
+}
+
+func (decoder *arrayDecoder) decodeElem(elemPtr unsafe.Pointer, index int, iter *Iterator) {
+	start, mark := iter.offset(), iter.errorMark()
+	decoder.elemDecoder.Decode(elemPtr, iter)
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorIndex(decoder.arrayType.Elem(), index, start, mark)
 	}
 }
@@ -0,0 +1,360 @@
+package jsoniter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/modern-go/reflect2"
+)
+
+// DecodeError is the error reported when decoding into a Go value fails.
+// It locates the failing value both in the input and in the Go value being decoded.
+type DecodeError struct {
+	// Path is the JSON Pointer of the failing value, e.g. /items/3/price
+	Path string
+	// Type is the Go type of the failing value
+	Type reflect.Type
+	// Value is the kind of JSON value found, one of "string", "number", "bool", "array" or "object",
+	// when it can never be decoded into Type. It is empty when the input itself is wrong.
+	Value string
+	// Struct is the name of the struct type holding the failing field,
+	// Field the dotted names of the fields leading to it, as in json.UnmarshalTypeError
+	Struct string
+	Field  string
+	Offset int64
+	Line   int
+	Column int
+	// Err is the error reported while decoding, usually a *SyntaxError
+	Err error
+	// prefix keeps the message built by the decoders the error went through
+	prefix      string
+	classified  bool
+	structKnown bool
+	// reported with Config.StandardLibraryErrors, see As
+	compatible bool
+}
+
+func (err *DecodeError) Error() string {
+	return err.prefix + err.Err.Error()
+}
+
+// Unwrap returns Err, unless Value is set: the input is then well formed
+// and errors.As must not find a *json.SyntaxError.
+func (err *DecodeError) Unwrap() error {
+	if err.Value != "" {
+		return nil
+	}
+	return err.Err
+}
+
+// As lets errors.As find a *json.UnmarshalTypeError, for code written against encoding/json,
+// when the error is reported with Config.StandardLibraryErrors set.
+func (err *DecodeError) As(target interface{}) bool {
+	if target, isStdError := target.(**json.UnmarshalTypeError); isStdError && err.Value != "" && err.compatible {
+		*target = &json.UnmarshalTypeError{
+			Value:  err.Value,
+			Type:   err.Type,
+			Offset: err.Offset,
+			Struct: err.Struct,
+			Field:  err.Field,
+		}
+		return true
+	}
+	return false
+}
+
+// decodeError turns the pending error of iter into a *DecodeError about a value of typ.
+func (iter *Iterator) decodeError(typ reflect2.Type) *DecodeError {
+	if decodeErr, isDecodeErr := iter.Error.(*DecodeError); isDecodeErr {
+		return decodeErr
+	}
+	decodeErr := newDecodeError(iter, iter.Error)
+	decodeErr.Type = typ.Type1()
+	iter.Error = decodeErr
+	return decodeErr
+}
+
+func newDecodeError(iter *Iterator, err error) *DecodeError {
+	decodeErr := &DecodeError{Err: err, compatible: iter.cfg.compatible}
+	if syntaxErr, isSyntaxErr := err.(*SyntaxError); isSyntaxErr {
+		decodeErr.Offset, decodeErr.Line, decodeErr.Column = syntaxErr.Offset, syntaxErr.Line, syntaxErr.Column
+	} else {
+		decodeErr.Offset, decodeErr.Line, decodeErr.Column = iter.location()
+	}
+	return decodeErr
+}
+
+// classify tells if the error is a type mismatch, looking at the value of typ which started at
+// offset start. The first decoder knowing where its value started does it.
+// A mismatch is located at the first byte of the value.
+func (decodeErr *DecodeError) classify(iter *Iterator, typ reflect2.Type, start int64) {
+	if decodeErr.classified {
+		return
+	}
+	decodeErr.classified = true
+	if _, isLimitErr := decodeErr.Err.(*LimitError); isLimitErr {
+		return
+	}
+	for i := start - iter.bufOffset; i >= 0 && i < int64(iter.tail); i++ {
+		c := iter.buf[i]
+		switch c {
+		case ' ', '\n', '\t', '\r':
+			continue
+		}
+		valueType := valueTypes[c]
+		if valueType != InvalidValue && valueType != NilValue && !acceptsValueType(typ, valueType) ||
+			valueType == NumberValue && isNumericType(typ) && iter.isNumberAt(int(i)) {
+			decodeErr.Value = valueTypeNames[valueType]
+			decodeErr.Offset, decodeErr.Line, decodeErr.Column = iter.locationAt(int(i) + 1)
+		}
+		return
+	}
+}
+
+// isNumberAt tells if a well formed JSON number starts at buf[i], ending in the buffer.
+// A numeric type failing to decode it can not hold its value, as 1.5 or 300 for an int8.
+func (iter *Iterator) isNumberAt(i int) bool {
+	end := i
+	for end < iter.tail && strings.IndexByte("+-.0123456789eE", iter.buf[end]) >= 0 {
+		end++
+	}
+	if end == iter.tail {
+		if iter.reader != nil {
+			// the number may go on past the buffer
+			return false
+		}
+	} else if strings.IndexByte(" \t\n\r,]}", iter.buf[end]) < 0 {
+		return false
+	}
+	return json.Valid(iter.buf[i:end])
+}
+
+// isNumericType tells if typ is decoded from numbers by the decoders of Go numbers.
+func isNumericType(typ reflect2.Type) bool {
+	if typ.Implements(unmarshalerType) || reflect2.PtrTo(typ).Implements(unmarshalerType) ||
+		typ.Implements(textUnmarshalerType) || reflect2.PtrTo(typ).Implements(textUnmarshalerType) {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return isNumericType(typ.(*reflect2.UnsafePtrType).Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+var efaceType = reflect2.TypeOfPtr((*interface{})(nil)).Elem()
+
+var valueTypeNames = map[ValueType]string{
+	StringValue: "string",
+	NumberValue: "number",
+	BoolValue:   "bool",
+	ArrayValue:  "array",
+	ObjectValue: "object",
+}
+
+// acceptsValueType tells if a value of typ may be decoded from a JSON value of valueType.
+func acceptsValueType(typ reflect2.Type, valueType ValueType) bool {
+	if typ == anyType || typ == jsonNumberType || typ == jsoniterNumberType ||
+		typ == jsonRawMessageType || typ == jsoniterRawMessageType ||
+		typ.Implements(unmarshalerType) || reflect2.PtrTo(typ).Implements(unmarshalerType) ||
+		typ.Implements(textUnmarshalerType) || reflect2.PtrTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return acceptsValueType(typ.(*reflect2.UnsafePtrType).Elem(), valueType)
+	case reflect.Bool:
+		return valueType == BoolValue
+	case reflect.String:
+		return valueType == StringValue
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return valueType == NumberValue
+	case reflect.Slice:
+		return valueType == ArrayValue ||
+			(valueType == StringValue && typ.(*reflect2.UnsafeSliceType).Elem().Kind() == reflect.Uint8)
+	case reflect.Array:
+		return valueType == ArrayValue
+	case reflect.Map, reflect.Struct:
+		return valueType == ObjectValue
+	}
+	return true
+}
+
+func (iter *Iterator) hasDecodeError() bool {
+	return iter.Error != nil && iter.Error != io.EOF
+}
+
+// errorMark remembers the errors pending before a value is decoded,
+// so that only the errors of that value are given its location.
+type errorMark struct {
+	failed    bool
+	collected int
+}
+
+func (iter *Iterator) errorMark() errorMark {
+	return errorMark{iter.hasDecodeError(), len(iter.collectedErrors)}
+}
+
+// hasErrorsSince tells if the value reported or collected errors.
+func (iter *Iterator) hasErrorsSince(mark errorMark) bool {
+	return (!mark.failed && iter.hasDecodeError()) || len(iter.collectedErrors) != mark.collected
+}
+
+// addErrorValue records on the pending error the value of typ which started at offset start.
+func (iter *Iterator) addErrorValue(typ reflect2.Type, start int64) {
+	iter.decodeError(typ).classify(iter, typ, start)
+}
+
+// addErrorField records on the errors of the value the struct field it comes from,
+// then collects the pending error if it can be.
+func (iter *Iterator) addErrorField(decoder *structFieldDecoder, start int64, mark errorMark) {
+	for _, decodeErr := range iter.collectedErrors[mark.collected:] {
+		decodeErr.addField(decoder)
+	}
+	if !mark.failed && iter.hasDecodeError() {
+		decodeErr := iter.decodeError(decoder.field.Type())
+		decodeErr.classify(iter, decoder.field.Type(), start)
+		decodeErr.addField(decoder)
+		iter.collectError(start)
+	}
+}
+
+// addErrorElement records on the errors of the value the array element or map entry it comes from,
+// then collects the pending error if it can be.
+func (iter *Iterator) addErrorElement(typ reflect2.Type, token string, start int64, mark errorMark) {
+	for _, decodeErr := range iter.collectedErrors[mark.collected:] {
+		decodeErr.addToken(token)
+	}
+	if !mark.failed && iter.hasDecodeError() {
+		decodeErr := iter.decodeError(typ)
+		decodeErr.classify(iter, typ, start)
+		decodeErr.addToken(token)
+		iter.collectError(start)
+	}
+}
+
+func (iter *Iterator) addErrorIndex(typ reflect2.Type, index int, start int64, mark errorMark) {
+	iter.addErrorElement(typ, strconv.Itoa(index), start, mark)
+}
+
+// addErrorStruct records on the errors of the value the struct type holding the failing field.
+func (iter *Iterator) addErrorStruct(typ reflect2.Type, mark errorMark) {
+	for _, decodeErr := range iter.collectedErrors[mark.collected:] {
+		decodeErr.addStruct(typ)
+	}
+	if !mark.failed && iter.hasDecodeError() {
+		iter.decodeError(typ).addStruct(typ)
+	}
+}
+
+// addErrorContainer records on the errors of the value the slice or array type it comes through.
+func (iter *Iterator) addErrorContainer(typ reflect2.Type, mark errorMark) {
+	for _, decodeErr := range iter.collectedErrors[mark.collected:] {
+		decodeErr.prefix = fmt.Sprintf("%v: %s", typ, decodeErr.prefix)
+	}
+	if !mark.failed && iter.hasDecodeError() {
+		decodeErr := iter.decodeError(typ)
+		decodeErr.prefix = fmt.Sprintf("%v: %s", typ, decodeErr.prefix)
+	}
+}
+
+func (decodeErr *DecodeError) addField(decoder *structFieldDecoder) {
+	if decoder.name != "" {
+		decodeErr.addToken(decoder.name)
+		if decodeErr.Field == "" {
+			decodeErr.Field = decoder.name
+		} else {
+			decodeErr.Field = decoder.name + "." + decodeErr.Field
+		}
+	}
+	decodeErr.prefix = decoder.field.Name() + ": " + decodeErr.prefix
+}
+
+func (decodeErr *DecodeError) addStruct(typ reflect2.Type) {
+	if !decodeErr.structKnown && decodeErr.Field != "" {
+		decodeErr.Struct = typ.Type1().Name()
+		decodeErr.structKnown = true
+	}
+	if len(typ.Type1().Name()) != 0 {
+		decodeErr.prefix = fmt.Sprintf("%v.%s", typ, decodeErr.prefix)
+	}
+}
+
+func (decodeErr *DecodeError) addToken(token string) {
+	decodeErr.Path = "/" + escapePointerToken(token) + decodeErr.Path
+}
+
+// collectError moves a type mismatch from iter.Error to the collected errors with
+// Config.CollectAllErrors, then skips the value which started at offset start so that decoding goes on.
+// Malformed input, or a value no more in the buffer, still stops decoding.
+func (iter *Iterator) collectError(start int64) {
+	if !iter.cfg.collectAllErrors {
+		return
+	}
+	decodeErr, isDecodeErr := iter.Error.(*DecodeError)
+	if !isDecodeErr || decodeErr.Value == "" || start < iter.bufOffset {
+		return
+	}
+	iter.collectedErrors = append(iter.collectedErrors, decodeErr)
+	iter.Error = nil
+	iter.head = int(start - iter.bufOffset)
+	iter.Skip()
+}
+
+// DecodeErrors lists the errors found when decoding with Config.CollectAllErrors,
+// in the order of the input.
+type DecodeErrors []*DecodeError
+
+func (errs DecodeErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+		if err.Path != "" {
+			msgs[i] = err.Path + ": " + msgs[i]
+		}
+	}
+	return fmt.Sprintf("%d errors: %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As.
+func (errs DecodeErrors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// withCollectedErrors returns err, preceded by the errors collected since the last call if any.
+func (iter *Iterator) withCollectedErrors(err error) error {
+	if len(iter.collectedErrors) == 0 {
+		return err
+	}
+	errs := DecodeErrors(iter.collectedErrors)
+	iter.collectedErrors = nil
+	if err != nil {
+		decodeErr, isDecodeErr := err.(*DecodeError)
+		if !isDecodeErr {
+			decodeErr = newDecodeError(iter, err)
+		}
+		errs = append(errs, decodeErr)
+	}
+	return errs
+}
+
+func escapePointerToken(token string) string {
+	if strings.IndexAny(token, "~/") == -1 {
+		return token
+	}
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
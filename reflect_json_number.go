@@ -73,7 +73,7 @@ func (codec *jsonNumberCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
 func (codec *jsonNumberCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
 	number := *((*json.Number)(ptr))
 	if len(number) == 0 {
-		stream.writeByte('0')
+		stream.WriteRaw("0")
 	} else {
 		stream.WriteRaw(string(number))
 	}
@@ -101,7 +101,7 @@ func (codec *jsoniterNumberCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
 func (codec *jsoniterNumberCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
 	number := *((*Number)(ptr))
 	if len(number) == 0 {
-		stream.writeByte('0')
+		stream.WriteRaw("0")
 	} else {
 		stream.WriteRaw(string(number))
 	}
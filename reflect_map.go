@@ -12,6 +12,9 @@ import (
 func decoderOfMap(ctx *ctx, typ reflect2.Type) ValDecoder {
 	mapType := typ.(*reflect2.UnsafeMapType)
 	keyDecoder := decoderOfMapKey(ctx.append("[mapKey]"), mapType.Key())
+	if ctx.allowSingleQuotes || ctx.allowUnquotedKeys {
+		keyDecoder = &relaxedMapKeyDecoder{keyDecoder}
+	}
 	elemDecoder := decoderOfType(ctx.append("[mapElem]"), mapType.Elem())
 	return &mapDecoder{
 		mapType:     mapType,
@@ -167,32 +170,77 @@ func (decoder *mapDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
 	if c == '}' {
 		return
 	}
-	iter.unreadByte()
-	key := decoder.keyType.UnsafeNew()
-	decoder.keyDecoder.Decode(key, iter)
-	c = iter.nextToken()
-	if c != ':' {
-		iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
+	if !iter.incrementDepth() {
 		return
 	}
-	elem := decoder.elemType.UnsafeNew()
-	decoder.elemDecoder.Decode(elem, iter)
-	decoder.mapType.UnsafeSetIndex(ptr, key, elem)
-	for c = iter.nextToken(); c == ','; c = iter.nextToken() {
+	iter.unreadByte()
+	keys := 0
+	var seen objectKeys
+	for c = ','; c == ','; c = iter.nextToken() {
+		keys++
+		if !iter.checkObjectKeys("ReadMapCB", keys) {
+			break
+		}
 		key := decoder.keyType.UnsafeNew()
 		decoder.keyDecoder.Decode(key, iter)
 		c = iter.nextToken()
 		if c != ':' {
 			iter.ReportError("ReadMapCB", "expect : after object field, but found "+string([]byte{c}))
-			return
+			break
+		}
+		if iter.cfg.duplicateKeys != DuplicateKeysLastWins {
+			keyVal := decoder.keyType.UnsafeIndirect(key)
+			if iter.duplicateKey("ReadMapCB", &seen, keyVal, fmt.Sprint(keyVal)) {
+				iter.Skip()
+				continue
+			}
 		}
 		elem := decoder.elemType.UnsafeNew()
-		decoder.elemDecoder.Decode(elem, iter)
-		decoder.mapType.UnsafeSetIndex(ptr, key, elem)
+		if decoder.decodeElem(key, elem, iter) {
+			decoder.mapType.UnsafeSetIndex(ptr, key, elem)
+		}
 	}
 	if c != '}' {
 		iter.ReportError("ReadMapCB", `expect }, but found `+string([]byte{c}))
 	}
+	iter.decrementDepth()
+}
+
+// decodeElem decodes the value of key, telling if it is to be stored: a value which can not be
+// decoded, its error collected, is left out of the map rather than stored as zero.
+func (decoder *mapDecoder) decodeElem(key unsafe.Pointer, elem unsafe.Pointer, iter *Iterator) bool {
+	start, mark := iter.offset(), iter.errorMark()
+	decoder.elemDecoder.Decode(elem, iter)
+	if !iter.hasErrorsSince(mark) {
+		return true
+	}
+	failed := !mark.failed && iter.hasDecodeError()
+	iter.addErrorElement(decoder.elemType, fmt.Sprint(decoder.keyType.UnsafeIndirect(key)), start, mark)
+	return !failed || iter.hasDecodeError()
+}
+
+// relaxedMapKeyDecoder decodes a key in single quotes or without quotes as if it was in double quotes.
+type relaxedMapKeyDecoder struct {
+	decoder ValDecoder
+}
+
+func (decoder *relaxedMapKeyDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	c := iter.nextToken()
+	iter.unreadByte()
+	if c == '"' || !iter.isRelaxedKeyStart(c) {
+		decoder.decoder.Decode(ptr, iter)
+		return
+	}
+	field := iter.ReadString()
+	stream := iter.cfg.BorrowStream(nil)
+	defer iter.cfg.ReturnStream(stream)
+	stream.WriteString(field)
+	subIter := iter.cfg.BorrowIterator(stream.Buffer())
+	defer iter.cfg.ReturnIterator(subIter)
+	decoder.decoder.Decode(ptr, subIter)
+	if subIter.Error != nil && subIter.Error != io.EOF {
+		iter.ReportError("ReadMapCB", subIter.Error.Error())
+	}
 }
 
 type numericMapKeyDecoder struct {
@@ -260,7 +308,12 @@ func (encoder *mapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 			stream.WriteMore()
 		}
 		key, elem := iter.UnsafeNext()
+		if !stream.checkField() {
+			break
+		}
+		stream.pauseCheck()
 		encoder.keyEncoder.Encode(key, stream)
+		stream.resumeCheck()
 		if stream.indention > 0 {
 			stream.writeTwoBytes(byte(':'), byte(' '))
 		} else {
@@ -314,12 +367,21 @@ func (encoder *sortKeysMapEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
 			keyValue: subStream.Buffer()[subStreamIndex:],
 		})
 	}
-	sort.Sort(keyValues)
+	if stream.cfg.canonical {
+		sort.Sort(canonicalKeyValues(keyValues))
+	} else {
+		sort.Sort(keyValues)
+	}
 	for i, keyValue := range keyValues {
 		if i != 0 {
 			stream.WriteMore()
 		}
+		if !stream.checkField() || !stream.checkValue() {
+			break
+		}
+		stream.pauseCheck()
 		stream.Write(keyValue.keyValue)
+		stream.resumeCheck()
 	}
 	if subStream.Error != nil && stream.Error == nil {
 		stream.Error = subStream.Error
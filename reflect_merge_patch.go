@@ -0,0 +1,307 @@
+package jsoniter
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// MergePatch applies a RFC 7396 JSON Merge Patch to target, which must be a pointer.
+// Members of the patch overwrite the matching struct fields or map entries, null members
+// zero the field or delete the entry, and nested objects are merged recursively.
+// Fields absent from the patch are left untouched.
+// A patch which is not an object replaces target as a whole.
+func (cfg *frozenConfig) MergePatch(target interface{}, patch []byte) error {
+	typ := reflect2.TypeOf(target)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return errors.New("MergePatch: can only merge patch into pointer")
+	}
+	ptr := reflect2.PtrOf(target)
+	if ptr == nil {
+		return errors.New("MergePatch: can not merge patch into nil pointer")
+	}
+	iter := cfg.BorrowIterator(patch)
+	defer cfg.ReturnIterator(iter)
+	valType := typ.(*reflect2.UnsafePtrType).Elem()
+	mergePatchValue(iter, ptr, valType, cfg.DecoderOf(typ), cfg.mergePatcherOf(typ))
+	c := iter.nextToken()
+	if c == 0 {
+		if iter.Error == io.EOF {
+			return iter.withCollectedErrors(nil)
+		}
+		return iter.withCollectedErrors(iter.Error)
+	}
+	iter.ReportError("MergePatch", "there are bytes left after merge patch")
+	return iter.withCollectedErrors(iter.Error)
+}
+
+// mergePatcherOf returns the decoder merging a patch object into a value of typ.Elem(),
+// nil if such a value is always replaced.
+func (cfg *frozenConfig) mergePatcherOf(typ reflect2.Type) ValDecoder {
+	cacheKey := typ.RType()
+	cached, found := cfg.mergePatcherCache.Load(cacheKey)
+	if found {
+		patcher, _ := cached.(ValDecoder)
+		return patcher
+	}
+	ctx := &ctx{
+		frozenConfig: cfg,
+		prefix:       "",
+		decoders:     map[reflect2.Type]ValDecoder{},
+		encoders:     map[reflect2.Type]ValEncoder{},
+	}
+	patcher := mergePatcherOfType(ctx, typ.(*reflect2.UnsafePtrType).Elem(), map[reflect2.Type]ValDecoder{})
+	cfg.mergePatcherCache.Store(cacheKey, patcher)
+	return patcher
+}
+
+func mergePatcherOfType(ctx *ctx, typ reflect2.Type, patchers map[reflect2.Type]ValDecoder) ValDecoder {
+	if patcher, found := patchers[typ]; found {
+		return patcher
+	}
+	if typ.Kind() == reflect.Interface && typ.Type1().NumMethod() == 0 {
+		return &efaceMergePatcher{}
+	}
+	if getTypeDecoderFromExtension(ctx, typ) != nil || typ == anyType ||
+		typ.Implements(unmarshalerType) || reflect2.PtrTo(typ).Implements(unmarshalerType) ||
+		typ.Implements(textUnmarshalerType) || reflect2.PtrTo(typ).Implements(textUnmarshalerType) {
+		return nil
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		placeholder := &placeholderDecoder{}
+		patchers[typ] = placeholder
+		patcher := &structMergePatcher{typ: typ, fields: map[string]*structFieldMergePatcher{}}
+		for name, fieldDecoder := range structFieldDecodersOf(ctx, typ) {
+			fieldType, decoder := fieldDecoder.leaf()
+			patcher.fields[name] = &structFieldMergePatcher{
+				fieldDecoder: fieldDecoder,
+				valType:      fieldType,
+				decoder:      decoder,
+				patcher:      mergePatcherOfType(ctx, fieldType, patchers),
+			}
+		}
+		placeholder.decoder = patcher
+		return patcher
+	case reflect.Map:
+		placeholder := &placeholderDecoder{}
+		patchers[typ] = placeholder
+		mapType := typ.(*reflect2.UnsafeMapType)
+		patcher := &mapMergePatcher{
+			mapType:     mapType,
+			keyType:     mapType.Key(),
+			elemType:    mapType.Elem(),
+			keyDecoder:  decoderOfMapKey(ctx.append("[mapKey]"), mapType.Key()),
+			elemDecoder: decoderOfType(ctx.append("[mapElem]"), mapType.Elem()),
+			elemPatcher: mergePatcherOfType(ctx, mapType.Elem(), patchers),
+		}
+		placeholder.decoder = patcher
+		return patcher
+	case reflect.Ptr:
+		placeholder := &placeholderDecoder{}
+		patchers[typ] = placeholder
+		ptrType := typ.(*reflect2.UnsafePtrType)
+		elemPatcher := mergePatcherOfType(ctx, ptrType.Elem(), patchers)
+		if elemPatcher == nil {
+			delete(patchers, typ)
+			return nil
+		}
+		patcher := &ptrMergePatcher{elemType: ptrType.Elem(), elemPatcher: elemPatcher}
+		placeholder.decoder = patcher
+		return patcher
+	}
+	return nil
+}
+
+// mergePatchValue applies the next patch value to the value of valType at ptr:
+// null zeroes it, an object is merged by patcher when there is one, anything else replaces it.
+func mergePatchValue(iter *Iterator, ptr unsafe.Pointer, valType reflect2.Type, decoder ValDecoder, patcher ValDecoder) {
+	switch iter.WhatIsNext() {
+	case NilValue:
+		iter.skipFourBytes('n', 'u', 'l', 'l')
+		valType.UnsafeSet(ptr, valType.UnsafeNew())
+	case ObjectValue:
+		if patcher != nil {
+			patcher.Decode(ptr, iter)
+			return
+		}
+		fallthrough
+	default:
+		valType.UnsafeSet(ptr, valType.UnsafeNew())
+		decoder.Decode(ptr, iter)
+	}
+}
+
+// leaf returns the type and decoder of the field the decoder finally writes to,
+// looking through the fields of embedded structs.
+func (decoder *structFieldDecoder) leaf() (reflect2.Type, ValDecoder) {
+	switch inner := decoder.fieldDecoder.(type) {
+	case *structFieldDecoder:
+		return inner.leaf()
+	case *dereferenceDecoder:
+		if innerField, isField := inner.valueDecoder.(*structFieldDecoder); isField {
+			return innerField.leaf()
+		}
+	}
+	return decoder.field.Type(), decoder.fieldDecoder
+}
+
+// leafPtr returns the address of the field the decoder finally writes to,
+// allocating the embedded struct pointers on the way.
+func (decoder *structFieldDecoder) leafPtr(ptr unsafe.Pointer) unsafe.Pointer {
+	fieldPtr := decoder.field.UnsafeGet(ptr)
+	switch inner := decoder.fieldDecoder.(type) {
+	case *structFieldDecoder:
+		return inner.leafPtr(fieldPtr)
+	case *dereferenceDecoder:
+		if innerField, isField := inner.valueDecoder.(*structFieldDecoder); isField {
+			if *((*unsafe.Pointer)(fieldPtr)) == nil {
+				*((*unsafe.Pointer)(fieldPtr)) = inner.valueType.UnsafeNew()
+			}
+			return innerField.leafPtr(*((*unsafe.Pointer)(fieldPtr)))
+		}
+	}
+	return fieldPtr
+}
+
+type structMergePatcher struct {
+	typ    reflect2.Type
+	fields map[string]*structFieldMergePatcher
+}
+
+type structFieldMergePatcher struct {
+	fieldDecoder *structFieldDecoder
+	valType      reflect2.Type
+	decoder      ValDecoder
+	patcher      ValDecoder
+}
+
+func (patcher *structMergePatcher) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	mark := iter.errorMark()
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		fieldPatcher := patcher.fields[field]
+		if fieldPatcher == nil && !iter.cfg.caseSensitive {
+			fieldPatcher = patcher.fields[strings.ToLower(field)]
+		}
+		if fieldPatcher == nil {
+			if iter.cfg.disallowUnknownFields {
+				iter.ReportError("MergePatch", "found unknown field: "+field)
+				return false
+			}
+			iter.Skip()
+			return true
+		}
+		start, mark := iter.offset(), iter.errorMark()
+		fieldPtr := fieldPatcher.fieldDecoder.leafPtr(ptr)
+		mergePatchValue(iter, fieldPtr, fieldPatcher.valType, fieldPatcher.decoder, fieldPatcher.patcher)
+		if iter.hasErrorsSince(mark) {
+			iter.addErrorField(fieldPatcher.fieldDecoder, start, mark)
+		}
+		return !iter.hasDecodeError()
+	})
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(patcher.typ, mark)
+	}
+}
+
+type mapMergePatcher struct {
+	mapType     *reflect2.UnsafeMapType
+	keyType     reflect2.Type
+	elemType    reflect2.Type
+	keyDecoder  ValDecoder
+	elemDecoder ValDecoder
+	elemPatcher ValDecoder
+}
+
+func (patcher *mapMergePatcher) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	mapType := patcher.mapType
+	if mapType.UnsafeIsNil(ptr) {
+		mapType.UnsafeSet(ptr, mapType.UnsafeMakeMap(0))
+	}
+	c := iter.nextToken()
+	if c != '{' {
+		iter.ReportError("MergePatch", `expect {, but found `+string([]byte{c}))
+		return
+	}
+	c = iter.nextToken()
+	if c == '}' {
+		return
+	}
+	if !iter.incrementDepth() {
+		return
+	}
+	iter.unreadByte()
+	keys := 0
+	for c = ','; c == ','; c = iter.nextToken() {
+		keys++
+		if !iter.checkObjectKeys("MergePatch", keys) {
+			break
+		}
+		key := patcher.keyType.UnsafeNew()
+		patcher.keyDecoder.Decode(key, iter)
+		c = iter.nextToken()
+		if c != ':' {
+			iter.ReportError("MergePatch", "expect : after object field, but found "+string([]byte{c}))
+			break
+		}
+		if iter.WhatIsNext() == NilValue {
+			iter.skipFourBytes('n', 'u', 'l', 'l')
+			reflect.NewAt(mapType.Type1(), ptr).Elem().SetMapIndex(
+				reflect.NewAt(patcher.keyType.Type1(), key).Elem(), reflect.Value{})
+			continue
+		}
+		elem := patcher.elemType.UnsafeNew()
+		if existing := mapType.UnsafeGetIndex(ptr, key); existing != nil && patcher.elemPatcher != nil {
+			patcher.elemType.UnsafeSet(elem, existing)
+		}
+		mergePatchValue(iter, elem, patcher.elemType, patcher.elemDecoder, patcher.elemPatcher)
+		mapType.UnsafeSetIndex(ptr, key, elem)
+	}
+	if c != '}' {
+		iter.ReportError("MergePatch", `expect }, but found `+string([]byte{c}))
+	}
+	iter.decrementDepth()
+}
+
+type ptrMergePatcher struct {
+	elemType    reflect2.Type
+	elemPatcher ValDecoder
+}
+
+func (patcher *ptrMergePatcher) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if *((*unsafe.Pointer)(ptr)) == nil {
+		*((*unsafe.Pointer)(ptr)) = patcher.elemType.UnsafeNew()
+	}
+	patcher.elemPatcher.Decode(*((*unsafe.Pointer)(ptr)), iter)
+}
+
+// efaceMergePatcher merges into the map[string]interface{} held by an interface{},
+// any other content is replaced by an empty object first.
+type efaceMergePatcher struct {
+}
+
+func (patcher *efaceMergePatcher) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	obj, isMap := (*((*interface{})(ptr))).(map[string]interface{})
+	if !isMap {
+		obj = map[string]interface{}{}
+		*((*interface{})(ptr)) = obj
+	}
+	iter.ReadMapCB(func(iter *Iterator, field string) bool {
+		switch iter.WhatIsNext() {
+		case NilValue:
+			iter.skipFourBytes('n', 'u', 'l', 'l')
+			delete(obj, field)
+		case ObjectValue:
+			elem := obj[field]
+			patcher.Decode(unsafe.Pointer(&elem), iter)
+			obj[field] = elem
+		default:
+			obj[field] = iter.Read()
+		}
+		return true
+	})
+}
@@ -436,6 +436,9 @@ func (codec *base64Codec) Encode(ptr unsafe.Pointer, stream *Stream) {
 		stream.WriteNil()
 		return
 	}
+	if !stream.checkValue() {
+		return
+	}
 	src := *((*[]byte)(ptr))
 	encoding := base64.StdEncoding
 	stream.writeByte('"')
@@ -57,9 +57,10 @@ type sliceDecoder struct {
 }
 
 func (decoder *sliceDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	mark := iter.errorMark()
 	decoder.doDecode(ptr, iter)
-	if iter.Error != nil && iter.Error != io.EOF {
-		iter.Error = fmt.Errorf("%v: %s", decoder.sliceType, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorContainer(decoder.sliceType, mark)
 	}
 }
 
@@ -80,20 +81,31 @@ func (decoder *sliceDecoder) doDecode(ptr unsafe.Pointer, iter *Iterator) {
 		sliceType.UnsafeSet(ptr, sliceType.UnsafeMakeSlice(0, 0))
 		return
 	}
+	if !iter.incrementDepth() {
+		return
+	}
 	iter.unreadByte()
 	sliceType.UnsafeGrow(ptr, 1)
 	elemPtr := sliceType.UnsafeGetIndex(ptr, 0)
-	decoder.elemDecoder.Decode(elemPtr, iter)
+	decoder.decodeElem(elemPtr, 0, iter)
 	length := 1
-	for c = iter.nextToken(); c == ','; c = iter.nextToken() {
+	for c = iter.nextToken(); c == ',' && iter.checkArrayElements("decode slice", length+1); c = iter.nextToken() {
 		idx := length
 		length += 1
 		sliceType.UnsafeGrow(ptr, length)
 		elemPtr = sliceType.UnsafeGetIndex(ptr, idx)
-		decoder.elemDecoder.Decode(elemPtr, iter)
+		decoder.decodeElem(elemPtr, idx, iter)
 	}
 	if c != ']' {
 		iter.ReportError("decode slice", "expect ], but found "+string([]byte{c}))
-		return
+	}
+	iter.decrementDepth()
+}
+
+func (decoder *sliceDecoder) decodeElem(elemPtr unsafe.Pointer, index int, iter *Iterator) {
+	start, mark := iter.offset(), iter.errorMark()
+	decoder.elemDecoder.Decode(elemPtr, iter)
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorIndex(decoder.sliceType.Elem(), index, start, mark)
 	}
 }
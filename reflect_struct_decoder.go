@@ -1,8 +1,6 @@
 package jsoniter
 
 import (
-	"fmt"
-	"io"
 	"strings"
 	"unsafe"
 
@@ -10,6 +8,12 @@ import (
 )
 
 func decoderOfStruct(ctx *ctx, typ reflect2.Type) ValDecoder {
+	return createStructDecoder(ctx, typ, structFieldDecodersOf(ctx, typ))
+}
+
+// structFieldDecodersOf maps every accepted field name to the decoder of its field,
+// resolving conflicts between bindings. Lower cased names are added when case insensitive.
+func structFieldDecodersOf(ctx *ctx, typ reflect2.Type) map[string]*structFieldDecoder {
 	bindings := map[string]*Binding{}
 	structDescriptor := describeStruct(ctx, typ)
 	for _, binding := range structDescriptor.Fields {
@@ -40,13 +44,13 @@ func decoderOfStruct(ctx *ctx, typ reflect2.Type) ValDecoder {
 			}
 		}
 	}
-
-	return createStructDecoder(ctx, typ, fields)
+	return fields
 }
 
 func createStructDecoder(ctx *ctx, typ reflect2.Type, fields map[string]*structFieldDecoder) ValDecoder {
-	if ctx.disallowUnknownFields {
-		return &generalStructDecoder{typ: typ, fields: fields, disallowUnknownFields: true}
+	if ctx.disallowUnknownFields || ctx.duplicateKeys != DuplicateKeysLastWins {
+		// the general decoder reads the keys, to find the unknown or duplicate ones
+		return &generalStructDecoder{typ: typ, fields: fields, disallowUnknownFields: ctx.disallowUnknownFields}
 	}
 	knownHash := map[int64]struct{}{
 		0: {},
@@ -503,12 +507,15 @@ func (decoder *generalStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator)
 	if !iter.incrementDepth() {
 		return
 	}
-	var c byte
-	for c = ','; c == ','; c = iter.nextToken() {
-		decoder.decodeOneField(ptr, iter)
+	mark := iter.errorMark()
+	var seen objectKeys
+	c := byte(',')
+	for keys := 1; c == ',' && iter.checkObjectKeys("struct Decode", keys); keys++ {
+		decoder.decodeOneField(ptr, iter, &seen)
+		c = iter.nextToken()
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	if c != '}' {
 		iter.ReportError("struct Decode", `expect }, but found `+string([]byte{c}))
@@ -516,10 +523,11 @@ func (decoder *generalStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator)
 	iter.decrementDepth()
 }
 
-func (decoder *generalStructDecoder) decodeOneField(ptr unsafe.Pointer, iter *Iterator) {
+func (decoder *generalStructDecoder) decodeOneField(ptr unsafe.Pointer, iter *Iterator, seen *objectKeys) {
 	var field string
 	var fieldDecoder *structFieldDecoder
-	if iter.cfg.objectFieldMustBeSimpleString {
+	// the keys kept to find the duplicates are not to change with the buffer
+	if iter.cfg.objectFieldMustBeSimpleString && iter.cfg.duplicateKeys == DuplicateKeysLastWins {
 		fieldBytes := iter.ReadStringAsSlice()
 		field = *(*string)(unsafe.Pointer(&fieldBytes))
 		fieldDecoder = decoder.fields[field]
@@ -533,8 +541,17 @@ func (decoder *generalStructDecoder) decodeOneField(ptr unsafe.Pointer, iter *It
 			fieldDecoder = decoder.fields[strings.ToLower(field)]
 		}
 	}
-	if fieldDecoder == nil {
-		if decoder.disallowUnknownFields {
+	var duplicate bool
+	if iter.cfg.duplicateKeys != DuplicateKeysLastWins {
+		// a field is repeated by any of the keys it is decoded from, whatever their case
+		var id interface{} = field
+		if fieldDecoder != nil {
+			id = fieldDecoder
+		}
+		duplicate = iter.duplicateKey("ReadObject", seen, id, field)
+	}
+	if fieldDecoder == nil || duplicate {
+		if fieldDecoder == nil && decoder.disallowUnknownFields {
 			msg := "found unknown field: " + field
 			iter.ReportError("ReadObject", msg)
 		}
@@ -578,7 +595,8 @@ func (decoder *oneFieldStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator)
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		if iter.readFieldHash() == decoder.fieldHash {
 			decoder.fieldDecoder.Decode(ptr, iter)
 		} else {
@@ -588,8 +606,8 @@ func (decoder *oneFieldStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator)
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -609,7 +627,8 @@ func (decoder *twoFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -622,8 +641,8 @@ func (decoder *twoFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -645,7 +664,8 @@ func (decoder *threeFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -660,8 +680,8 @@ func (decoder *threeFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -685,7 +705,8 @@ func (decoder *fourFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -702,8 +723,8 @@ func (decoder *fourFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -729,7 +750,8 @@ func (decoder *fiveFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -748,8 +770,8 @@ func (decoder *fiveFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -777,7 +799,8 @@ func (decoder *sixFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -798,8 +821,8 @@ func (decoder *sixFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -829,7 +852,8 @@ func (decoder *sevenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -852,8 +876,8 @@ func (decoder *sevenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -885,7 +909,8 @@ func (decoder *eightFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -910,8 +935,8 @@ func (decoder *eightFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterat
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -945,7 +970,8 @@ func (decoder *nineFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -972,8 +998,8 @@ func (decoder *nineFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterato
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -1009,7 +1035,8 @@ func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 	if !iter.incrementDepth() {
 		return
 	}
-	for {
+	mark := iter.errorMark()
+	for keys := 1; iter.checkObjectKeys("struct Decode", keys); keys++ {
 		switch iter.readFieldHash() {
 		case decoder.fieldHash1:
 			decoder.fieldDecoder1.Decode(ptr, iter)
@@ -1038,8 +1065,8 @@ func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 			break
 		}
 	}
-	if iter.Error != nil && iter.Error != io.EOF && len(decoder.typ.Type1().Name()) != 0 {
-		iter.Error = fmt.Errorf("%v.%s", decoder.typ, iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorStruct(decoder.typ, mark)
 	}
 	iter.decrementDepth()
 }
@@ -1047,13 +1074,16 @@ func (decoder *tenFieldsStructDecoder) Decode(ptr unsafe.Pointer, iter *Iterator
 type structFieldDecoder struct {
 	field        reflect2.StructField
 	fieldDecoder ValDecoder
+	// name is the JSON name of the field, empty for the fields holding embedded structs
+	name string
 }
 
 func (decoder *structFieldDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	start, mark := iter.offset(), iter.errorMark()
 	fieldPtr := decoder.field.UnsafeGet(ptr)
 	decoder.fieldDecoder.Decode(fieldPtr, iter)
-	if iter.Error != nil && iter.Error != io.EOF {
-		iter.Error = fmt.Errorf("%s: %s", decoder.field.Name(), iter.Error.Error())
+	if iter.hasErrorsSince(mark) {
+		iter.addErrorField(decoder, start, mark)
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"github.com/modern-go/reflect2"
 	"io"
 	"reflect"
+	"sort"
 	"unsafe"
 )
 
@@ -43,6 +44,11 @@ func encoderOfStruct(ctx *ctx, typ reflect2.Type) ValEncoder {
 			})
 		}
 	}
+	if ctx.canonical {
+		sort.Slice(finalOrderedFields, func(i, j int) bool {
+			return lessUTF16(finalOrderedFields[i].toName, finalOrderedFields[j].toName)
+		})
+	}
 	return &structEncoder{typ, finalOrderedFields}
 }
 
@@ -184,9 +190,14 @@ type stringModeNumberEncoder struct {
 }
 
 func (encoder *stringModeNumberEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	if !stream.checkValue() {
+		return
+	}
+	stream.pauseCheck()
 	stream.writeByte('"')
 	encoder.elemEncoder.Encode(ptr, stream)
 	stream.writeByte('"')
+	stream.resumeCheck()
 }
 
 func (encoder *stringModeNumberEncoder) IsEmpty(ptr unsafe.Pointer) bool {
@@ -0,0 +1,425 @@
+// Package schema validates JSON documents against JSON Schema draft 2020-12.
+//
+// A schema is compiled once, then documents are validated in a single streaming
+// pass over a jsoniter.Iterator: every subschema applying to a value is evaluated
+// while the value is read, so the document is never decoded into an intermediate tree.
+// All the violations are reported, each with the JSON Pointer of the offending value.
+// Unmarshal is not a single pass: it validates the document before decoding it.
+// Documents are read with jsoniter.ConfigDefault, or with the API given to Schema.Validator.
+//
+// The supported keywords are the core applicators ($ref to the same document, $defs,
+// allOf, anyOf, oneOf, not, if/then/else, dependentSchemas, properties, patternProperties,
+// additionalProperties, propertyNames, prefixItems, items, contains) and the validation
+// vocabulary (type, enum, const, multipleOf, maximum, exclusiveMaximum, minimum,
+// exclusiveMinimum, maxLength, minLength, pattern, maxItems, minItems, uniqueItems,
+// maxContains, minContains, maxProperties, minProperties, required, dependentRequired).
+// format is an annotation only. Schemas using unevaluatedItems, unevaluatedProperties,
+// $dynamicRef or references to other documents are rejected by Compile.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/json-iterator/go"
+)
+
+var schemaConfig = jsoniter.Config{UseNumber: true}.Froze()
+
+// Schema is a compiled JSON Schema, safe for concurrent use.
+type Schema struct {
+	location string
+	// boolean schemas only set always
+	always *bool
+
+	types      []string
+	enum       []interface{}
+	hasConst   bool
+	constValue interface{}
+
+	multipleOf       *big.Rat
+	maximum          *big.Rat
+	exclusiveMaximum *big.Rat
+	minimum          *big.Rat
+	exclusiveMinimum *big.Rat
+
+	maxLength int
+	minLength int
+	pattern   *regexp.Regexp
+
+	prefixItems []*Schema
+	items       *Schema
+	contains    *Schema
+	maxContains int
+	minContains int
+	maxItems    int
+	minItems    int
+	uniqueItems bool
+
+	properties           map[string]*Schema
+	patternProperties    []*patternSchema
+	additionalProperties *Schema
+	propertyNames        *Schema
+	maxProperties        int
+	minProperties        int
+	required             []string
+	dependentRequired    map[string][]string
+	dependentSchemas     map[string]*Schema
+
+	ref        *Schema
+	allOf      []*Schema
+	anyOf      []*Schema
+	oneOf      []*Schema
+	not        *Schema
+	ifSchema   *Schema
+	thenSchema *Schema
+	elseSchema *Schema
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *Schema
+}
+
+// Compile parses and compiles a JSON Schema document.
+func Compile(data []byte) (*Schema, error) {
+	var doc interface{}
+	if err := schemaConfig.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	compiler := &compiler{root: doc, compiled: map[string]*Schema{}}
+	return compiler.compileAt("")
+}
+
+// MustCompile is like Compile but panics if the schema can not be compiled.
+func MustCompile(data []byte) *Schema {
+	schema, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+type compiler struct {
+	root     interface{}
+	compiled map[string]*Schema
+}
+
+// compileAt compiles the subschema found at the JSON Pointer location of the schema document,
+// every location is compiled once so recursive references end up as cycles.
+func (compiler *compiler) compileAt(location string) (*Schema, error) {
+	if schema := compiler.compiled[location]; schema != nil {
+		return schema, nil
+	}
+	doc := compiler.root
+	if location != "" {
+		for _, token := range strings.Split(location[1:], "/") {
+			token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+			switch container := doc.(type) {
+			case map[string]interface{}:
+				doc = container[token]
+			case []interface{}:
+				var index int
+				if _, err := fmt.Sscanf(token, "%d", &index); err != nil || index < 0 || index >= len(container) {
+					return nil, fmt.Errorf("%s not found in schema", location)
+				}
+				doc = container[index]
+			default:
+				return nil, fmt.Errorf("%s not found in schema", location)
+			}
+		}
+	}
+	schema := &Schema{location: location, maxLength: -1, maxItems: -1, maxContains: -1, maxProperties: -1, minContains: 1}
+	compiler.compiled[location] = schema
+	if err := compiler.compile(schema, doc); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (compiler *compiler) compile(schema *Schema, doc interface{}) error {
+	switch doc := doc.(type) {
+	case bool:
+		schema.always = &doc
+		return nil
+	case map[string]interface{}:
+		keywords := make([]string, 0, len(doc))
+		for keyword := range doc {
+			keywords = append(keywords, keyword)
+		}
+		sort.Strings(keywords)
+		for _, keyword := range keywords {
+			if err := compiler.compileKeyword(schema, keyword, doc[keyword]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: schema must be an object or a boolean", schema.keywordLocation(""))
+}
+
+func (compiler *compiler) compileKeyword(schema *Schema, keyword string, value interface{}) error {
+	var err error
+	location := schema.location + "/" + escapePointerToken(keyword)
+	switch keyword {
+	case "$schema", "$id", "$anchor", "$comment", "$defs", "definitions", "$vocabulary",
+		"title", "description", "default", "examples", "deprecated", "readOnly", "writeOnly",
+		"format", "contentEncoding", "contentMediaType", "contentSchema":
+		// annotations, or subschemas only reachable through $ref
+	case "$ref":
+		ref, isString := value.(string)
+		if !isString || (ref != "#" && !strings.HasPrefix(ref, "#/")) {
+			return fmt.Errorf("%s: only references inside the same document are supported", location)
+		}
+		schema.ref, err = compiler.compileAt(unescapeURIFragment(ref[1:]))
+	case "type":
+		switch value := value.(type) {
+		case string:
+			schema.types = []string{value}
+		case []interface{}:
+			for _, typ := range value {
+				typeName, isString := typ.(string)
+				if !isString {
+					return fmt.Errorf("%s: type must be a string or an array of strings", location)
+				}
+				schema.types = append(schema.types, typeName)
+			}
+		default:
+			return fmt.Errorf("%s: type must be a string or an array of strings", location)
+		}
+		for _, typeName := range schema.types {
+			switch typeName {
+			case "null", "boolean", "object", "array", "number", "string", "integer":
+			default:
+				return fmt.Errorf("%s: unknown type %q", location, typeName)
+			}
+		}
+	case "enum":
+		values, isArray := value.([]interface{})
+		if !isArray {
+			return fmt.Errorf("%s: enum must be an array", location)
+		}
+		schema.enum = values
+	case "const":
+		schema.hasConst = true
+		schema.constValue = value
+	case "multipleOf":
+		schema.multipleOf, err = compileNumber(location, value)
+		if err == nil && schema.multipleOf.Sign() <= 0 {
+			err = fmt.Errorf("%s: multipleOf must be strictly positive", location)
+		}
+	case "maximum":
+		schema.maximum, err = compileNumber(location, value)
+	case "exclusiveMaximum":
+		schema.exclusiveMaximum, err = compileNumber(location, value)
+	case "minimum":
+		schema.minimum, err = compileNumber(location, value)
+	case "exclusiveMinimum":
+		schema.exclusiveMinimum, err = compileNumber(location, value)
+	case "maxLength":
+		schema.maxLength, err = compileCount(location, value)
+	case "minLength":
+		schema.minLength, err = compileCount(location, value)
+	case "pattern":
+		schema.pattern, err = compilePattern(location, value)
+	case "prefixItems":
+		schema.prefixItems, err = compiler.compileList(location, value)
+	case "items":
+		schema.items, err = compiler.compileAt(location)
+	case "contains":
+		schema.contains, err = compiler.compileAt(location)
+	case "maxContains":
+		schema.maxContains, err = compileCount(location, value)
+	case "minContains":
+		schema.minContains, err = compileCount(location, value)
+	case "maxItems":
+		schema.maxItems, err = compileCount(location, value)
+	case "minItems":
+		schema.minItems, err = compileCount(location, value)
+	case "uniqueItems":
+		unique, isBool := value.(bool)
+		if !isBool {
+			return fmt.Errorf("%s: uniqueItems must be a boolean", location)
+		}
+		schema.uniqueItems = unique
+	case "properties":
+		schema.properties, err = compiler.compileMap(location, value)
+	case "patternProperties":
+		var schemas map[string]*Schema
+		schemas, err = compiler.compileMap(location, value)
+		patterns := make([]string, 0, len(schemas))
+		for pattern := range schemas {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			compiled, err := compilePattern(location, pattern)
+			if err != nil {
+				return err
+			}
+			schema.patternProperties = append(schema.patternProperties, &patternSchema{compiled, schemas[pattern]})
+		}
+	case "additionalProperties":
+		schema.additionalProperties, err = compiler.compileAt(location)
+	case "propertyNames":
+		schema.propertyNames, err = compiler.compileAt(location)
+	case "maxProperties":
+		schema.maxProperties, err = compileCount(location, value)
+	case "minProperties":
+		schema.minProperties, err = compileCount(location, value)
+	case "required":
+		schema.required, err = compileStrings(location, value)
+	case "dependentRequired":
+		members, isObject := value.(map[string]interface{})
+		if !isObject {
+			return fmt.Errorf("%s: dependentRequired must be an object", location)
+		}
+		schema.dependentRequired = map[string][]string{}
+		for member, dependencies := range members {
+			schema.dependentRequired[member], err = compileStrings(location+"/"+escapePointerToken(member), dependencies)
+			if err != nil {
+				return err
+			}
+		}
+	case "dependentSchemas":
+		schema.dependentSchemas, err = compiler.compileMap(location, value)
+	case "allOf":
+		schema.allOf, err = compiler.compileList(location, value)
+	case "anyOf":
+		schema.anyOf, err = compiler.compileList(location, value)
+	case "oneOf":
+		schema.oneOf, err = compiler.compileList(location, value)
+	case "not":
+		schema.not, err = compiler.compileAt(location)
+	case "if":
+		schema.ifSchema, err = compiler.compileAt(location)
+	case "then":
+		schema.thenSchema, err = compiler.compileAt(location)
+	case "else":
+		schema.elseSchema, err = compiler.compileAt(location)
+	case "unevaluatedItems", "unevaluatedProperties", "$dynamicRef", "$dynamicAnchor", "$recursiveRef":
+		return fmt.Errorf("%s: keyword is not supported", location)
+	}
+	return err
+}
+
+func (compiler *compiler) compileList(location string, value interface{}) ([]*Schema, error) {
+	list, isArray := value.([]interface{})
+	if !isArray || len(list) == 0 {
+		return nil, fmt.Errorf("%s: must be a non-empty array of schemas", location)
+	}
+	schemas := make([]*Schema, len(list))
+	for i := range list {
+		schema, err := compiler.compileAt(fmt.Sprintf("%s/%d", location, i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = schema
+	}
+	return schemas, nil
+}
+
+func (compiler *compiler) compileMap(location string, value interface{}) (map[string]*Schema, error) {
+	members, isObject := value.(map[string]interface{})
+	if !isObject {
+		return nil, fmt.Errorf("%s: must be an object of schemas", location)
+	}
+	schemas := map[string]*Schema{}
+	for member := range members {
+		schema, err := compiler.compileAt(location + "/" + escapePointerToken(member))
+		if err != nil {
+			return nil, err
+		}
+		schemas[member] = schema
+	}
+	return schemas, nil
+}
+
+func compileNumber(location string, value interface{}) (*big.Rat, error) {
+	number, isNumber := value.(json.Number)
+	if !isNumber {
+		return nil, fmt.Errorf("%s: must be a number", location)
+	}
+	rat, ok := new(big.Rat).SetString(string(number))
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid number %s", location, number)
+	}
+	return rat, nil
+}
+
+func compileCount(location string, value interface{}) (int, error) {
+	rat, err := compileNumber(location, value)
+	if err != nil {
+		return 0, err
+	}
+	if !rat.IsInt() || rat.Sign() < 0 || !rat.Num().IsInt64() {
+		return 0, fmt.Errorf("%s: must be a non-negative integer", location)
+	}
+	return int(rat.Num().Int64()), nil
+}
+
+func compileStrings(location string, value interface{}) ([]string, error) {
+	list, isArray := value.([]interface{})
+	if !isArray {
+		return nil, fmt.Errorf("%s: must be an array of strings", location)
+	}
+	strs := make([]string, len(list))
+	for i, elem := range list {
+		str, isString := elem.(string)
+		if !isString {
+			return nil, fmt.Errorf("%s: must be an array of strings", location)
+		}
+		strs[i] = str
+	}
+	return strs, nil
+}
+
+func compilePattern(location string, value interface{}) (*regexp.Regexp, error) {
+	pattern, isString := value.(string)
+	if !isString {
+		return nil, fmt.Errorf("%s: pattern must be a string", location)
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", location, err)
+	}
+	return compiled, nil
+}
+
+func (schema *Schema) keywordLocation(keyword string) string {
+	if keyword == "" {
+		if schema.location == "" {
+			return "#"
+		}
+		return "#" + schema.location
+	}
+	return "#" + schema.location + "/" + keyword
+}
+
+func escapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// unescapeURIFragment decodes the percent-encoding allowed in the fragment of a $ref.
+func unescapeURIFragment(fragment string) string {
+	if strings.IndexByte(fragment, '%') == -1 {
+		return fragment
+	}
+	var unescaped []byte
+	for i := 0; i < len(fragment); i++ {
+		if fragment[i] == '%' && i+2 < len(fragment) {
+			var b byte
+			if _, err := fmt.Sscanf(fragment[i+1:i+3], "%02x", &b); err == nil {
+				unescaped = append(unescaped, b)
+				i += 2
+				continue
+			}
+		}
+		unescaped = append(unescaped, fragment[i])
+	}
+	return string(unescaped)
+}
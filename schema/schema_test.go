@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_compile_errors(t *testing.T) {
+	for _, input := range []string{
+		`1`,
+		`{"type": "text"}`,
+		`{"minLength": -1}`,
+		`{"maxItems": 1.5}`,
+		`{"pattern": "("}`,
+		`{"allOf": []}`,
+		`{"$ref": "other.json#/a"}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"unevaluatedProperties": false}`,
+		`{"properties": {"a": 1}}`,
+		`{`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := Compile([]byte(input))
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_compile_boolean_schemas(t *testing.T) {
+	should := require.New(t)
+	should.NoError(MustCompile([]byte(`true`)).Validate([]byte(`{"a":[1]}`)))
+	should.Error(MustCompile([]byte(`false`)).Validate([]byte(`null`)))
+	should.Panics(func() { MustCompile([]byte(`"a"`)) })
+}
+
+func Test_compile_escaped_references(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{
+		"$defs": {"a/b": {"type": "string"}, "c%d": {"type": "integer"}},
+		"properties": {"x": {"$ref": "#/$defs/a~1b"}, "y": {"$ref": "#/$defs/c%25d"}}
+	}`))
+	should.NoError(schema.Validate([]byte(`{"x":"s","y":1}`)))
+	should.Equal([]string{"#/$defs/a~1b/type", "#/$defs/c%d/type"},
+		keywordLocations(schema.Validate([]byte(`{"x":1,"y":"s"}`))))
+}
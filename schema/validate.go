@@ -0,0 +1,649 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/json-iterator/go"
+)
+
+// Violation is a value of the document failing a keyword of the schema.
+type Violation struct {
+	// InstanceLocation is the JSON Pointer of the value in the document.
+	InstanceLocation string
+	// KeywordLocation is the location of the failing keyword in the schema, as a URI fragment.
+	KeywordLocation string
+	Message         string
+}
+
+func (violation Violation) String() string {
+	return fmt.Sprintf("%q: %s (%s)", violation.InstanceLocation, violation.Message, violation.KeywordLocation)
+}
+
+// ValidationError reports every violation found in a document.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (err *ValidationError) Error() string {
+	lines := make([]string, len(err.Violations))
+	for i, violation := range err.Violations {
+		lines[i] = violation.String()
+	}
+	return fmt.Sprintf("schema: %d violation(s): %s", len(lines), strings.Join(lines, "; "))
+}
+
+// Validator validates documents against a schema, reading and decoding them with an API,
+// which tells what the documents may hold such as JSON5 or duplicate keys, and the limits.
+// It is safe for concurrent use.
+type Validator struct {
+	schema *Schema
+	api    jsoniter.API
+}
+
+// Validator returns the Validator of the schema reading documents with api.
+func (schema *Schema) Validator(api jsoniter.API) *Validator {
+	return &Validator{schema, api}
+}
+
+// Validate validates the document data with jsoniter.ConfigDefault, the error is
+// a *ValidationError if the document is well formed but does not match the schema.
+func (schema *Schema) Validate(data []byte) error {
+	return schema.Validator(jsoniter.ConfigDefault).Validate(data)
+}
+
+// ValidateReader validates the document read from reader with jsoniter.ConfigDefault,
+// without buffering it as a whole.
+func (schema *Schema) ValidateReader(reader io.Reader) error {
+	return schema.Validator(jsoniter.ConfigDefault).ValidateReader(reader)
+}
+
+// Unmarshal validates data then decodes it into v with jsoniter.ConfigDefault,
+// as Validator.Unmarshal does.
+func (schema *Schema) Unmarshal(data []byte, v interface{}) error {
+	return schema.Validator(jsoniter.ConfigDefault).Unmarshal(data, v)
+}
+
+// ValidateIterator validates the next value read from iter, leaving the iterator after it.
+func (schema *Schema) ValidateIterator(iter *jsoniter.Iterator) error {
+	validator := &validator{iter: iter}
+	results, _ := validator.validate([]*Schema{schema}, "", false)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	if len(results[0]) != 0 {
+		return &ValidationError{Violations: results[0]}
+	}
+	return nil
+}
+
+// Validate validates the document data, the error is a *ValidationError if the document is
+// well formed but does not match the schema.
+func (validator *Validator) Validate(data []byte) error {
+	iter := validator.api.BorrowIterator(data)
+	defer validator.api.ReturnIterator(iter)
+	return validator.schema.validateDocument(iter)
+}
+
+// ValidateReader validates the document read from reader, without buffering it as a whole.
+func (validator *Validator) ValidateReader(reader io.Reader) error {
+	return validator.schema.validateDocument(jsoniter.Parse(validator.api, reader, 512))
+}
+
+// Unmarshal validates data, then reads it a second time to decode it into v, so that v is left
+// untouched when the document does not match the schema. It costs about as much as Validate
+// followed by the Unmarshal of the API.
+func (validator *Validator) Unmarshal(data []byte, v interface{}) error {
+	iter := validator.api.BorrowIterator(data)
+	defer validator.api.ReturnIterator(iter)
+	if err := validator.schema.validateDocument(iter); err != nil {
+		return err
+	}
+	// the second pass, the Error being io.EOF past the document
+	iter.ResetBytes(data)
+	iter.Error = nil
+	iter.ReadVal(v)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	return nil
+}
+
+func (schema *Schema) validateDocument(iter *jsoniter.Iterator) error {
+	err := schema.ValidateIterator(iter)
+	if _, isViolation := err.(*ValidationError); err != nil && !isViolation {
+		return err
+	}
+	if iter.ReadAny().ValueType() != jsoniter.InvalidValue {
+		iter.ReportError("Validate", "there are bytes left after the document")
+		return iter.Error
+	}
+	return err
+}
+
+type validator struct {
+	iter *jsoniter.Iterator
+}
+
+// evaluations holds every schema applying to the current value, the schemas given to validate
+// plus the ones reachable through the in-place applicators, so that the value is read once.
+type evaluations struct {
+	list  []*evaluation
+	index map[*Schema]*evaluation
+	// keys of the object being validated, for required, dependentRequired and dependentSchemas
+	keys map[string]bool
+}
+
+type evaluation struct {
+	schema     *Schema
+	location   string
+	violations []Violation
+	final      []Violation
+	state      int
+	contained  int
+}
+
+const (
+	evaluationPending = iota
+	evaluationRunning
+	evaluationDone
+)
+
+func (evaluations *evaluations) add(schema *Schema, location string) {
+	if schema == nil || evaluations.index[schema] != nil {
+		return
+	}
+	evaluation := &evaluation{schema: schema, location: location}
+	evaluations.index[schema] = evaluation
+	evaluations.list = append(evaluations.list, evaluation)
+	if schema.always != nil {
+		if !*schema.always {
+			evaluation.fail("", "no value is allowed")
+		}
+		return
+	}
+	evaluations.add(schema.ref, location)
+	for _, subschemas := range [][]*Schema{schema.allOf, schema.anyOf, schema.oneOf} {
+		for _, subschema := range subschemas {
+			evaluations.add(subschema, location)
+		}
+	}
+	evaluations.add(schema.not, location)
+	evaluations.add(schema.ifSchema, location)
+	evaluations.add(schema.thenSchema, location)
+	evaluations.add(schema.elseSchema, location)
+	for _, subschema := range schema.dependentSchemas {
+		evaluations.add(subschema, location)
+	}
+}
+
+func (evaluation *evaluation) fail(keyword string, format string, args ...interface{}) {
+	evaluation.violations = append(evaluation.violations, Violation{
+		InstanceLocation: evaluation.location,
+		KeywordLocation:  evaluation.schema.keywordLocation(keyword),
+		Message:          fmt.Sprintf(format, args...),
+	})
+}
+
+// validate reads the next value and returns the violations of each schema,
+// plus the value itself when needValue is set or a schema needs it for enum or const.
+func (validator *validator) validate(schemas []*Schema, location string, needValue bool) ([][]Violation, interface{}) {
+	iter := validator.iter
+	evaluations := &evaluations{index: map[*Schema]*evaluation{}}
+	for _, schema := range schemas {
+		evaluations.add(schema, location)
+	}
+	for _, evaluation := range evaluations.list {
+		if evaluation.schema.enum != nil || evaluation.schema.hasConst {
+			needValue = true
+		}
+	}
+	var value interface{}
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		str := iter.ReadString()
+		value = str
+		evaluations.checkString(str)
+	case jsoniter.NumberValue:
+		number := iter.ReadNumber()
+		rat, ok := parseNumber(number)
+		if !ok {
+			iter.ReportError("Validate", "invalid number "+string(number))
+			return make([][]Violation, len(schemas)), nil
+		}
+		value = number
+		evaluations.checkNumber(rat)
+	case jsoniter.BoolValue:
+		value = iter.ReadBool()
+		evaluations.checkType("boolean", false)
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		evaluations.checkType("null", false)
+	case jsoniter.ArrayValue:
+		value = validator.validateArray(evaluations, location, needValue)
+	case jsoniter.ObjectValue:
+		value = validator.validateObject(evaluations, location, needValue)
+	default:
+		iter.Skip()
+		return make([][]Violation, len(schemas)), nil
+	}
+	if needValue {
+		evaluations.checkValue(value)
+	}
+	results := make([][]Violation, len(schemas))
+	for i, schema := range schemas {
+		results[i] = evaluations.finalOf(schema)
+	}
+	return results, value
+}
+
+// children collects the schemas each evaluation applies to a member or an element,
+// so that all of them are validated in the same pass.
+type children struct {
+	schemas []*Schema
+	index   map[*Schema]int
+}
+
+func (children *children) add(schema *Schema) int {
+	if i, found := children.index[schema]; found {
+		return i
+	}
+	children.index[schema] = len(children.schemas)
+	children.schemas = append(children.schemas, schema)
+	return len(children.schemas) - 1
+}
+
+func (validator *validator) validateObject(evaluations *evaluations, location string, needValue bool) interface{} {
+	var obj map[string]interface{}
+	if needValue {
+		obj = map[string]interface{}{}
+	}
+	evaluations.keys = map[string]bool{}
+	validator.iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		evaluations.keys[key] = true
+		memberLocation := location + "/" + escapePointerToken(key)
+		members := &children{index: map[*Schema]int{}}
+		applied := make([][]int, len(evaluations.list))
+		for i, evaluation := range evaluations.list {
+			schema := evaluation.schema
+			if schema.propertyNames != nil {
+				evaluation.violations = append(evaluation.violations, validator.validateKey(schema.propertyNames, key, memberLocation)...)
+			}
+			matched := false
+			if property := schema.properties[key]; property != nil {
+				applied[i] = append(applied[i], members.add(property))
+				matched = true
+			}
+			for _, patternProperty := range schema.patternProperties {
+				if patternProperty.pattern.MatchString(key) {
+					applied[i] = append(applied[i], members.add(patternProperty.schema))
+					matched = true
+				}
+			}
+			if !matched && schema.additionalProperties != nil {
+				applied[i] = append(applied[i], members.add(schema.additionalProperties))
+			}
+		}
+		results, member := validator.validate(members.schemas, memberLocation, needValue)
+		for i, evaluation := range evaluations.list {
+			for _, child := range applied[i] {
+				evaluation.violations = append(evaluation.violations, results[child]...)
+			}
+		}
+		if obj != nil {
+			obj[key] = member
+		}
+		return iter.Error == nil
+	})
+	for _, evaluation := range evaluations.list {
+		schema := evaluation.schema
+		evaluation.checkType("object", false)
+		if len(evaluations.keys) < schema.minProperties {
+			evaluation.fail("minProperties", "expected at least %d properties, but found %d", schema.minProperties, len(evaluations.keys))
+		}
+		if schema.maxProperties >= 0 && len(evaluations.keys) > schema.maxProperties {
+			evaluation.fail("maxProperties", "expected at most %d properties, but found %d", schema.maxProperties, len(evaluations.keys))
+		}
+		for _, required := range schema.required {
+			if !evaluations.keys[required] {
+				evaluation.fail("required", "missing required property %q", required)
+			}
+		}
+		for _, key := range sortedKeys(schema.dependentRequired) {
+			if !evaluations.keys[key] {
+				continue
+			}
+			for _, required := range schema.dependentRequired[key] {
+				if !evaluations.keys[required] {
+					evaluation.fail("dependentRequired/"+escapePointerToken(key),
+						"missing property %q required by property %q", required, key)
+				}
+			}
+		}
+	}
+	return obj
+}
+
+func (validator *validator) validateArray(evaluations *evaluations, location string, needValue bool) interface{} {
+	uniqueItems := false
+	for _, evaluation := range evaluations.list {
+		uniqueItems = uniqueItems || evaluation.schema.uniqueItems
+	}
+	var array []interface{}
+	index := 0
+	validator.iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		elements := &children{index: map[*Schema]int{}}
+		applied := make([]int, len(evaluations.list))
+		contains := make([]int, len(evaluations.list))
+		for i, evaluation := range evaluations.list {
+			schema := evaluation.schema
+			applied[i], contains[i] = -1, -1
+			if index < len(schema.prefixItems) {
+				applied[i] = elements.add(schema.prefixItems[index])
+			} else if schema.items != nil {
+				applied[i] = elements.add(schema.items)
+			}
+			if schema.contains != nil {
+				contains[i] = elements.add(schema.contains)
+			}
+		}
+		results, element := validator.validate(elements.schemas, location+"/"+strconv.Itoa(index), needValue || uniqueItems)
+		for i, evaluation := range evaluations.list {
+			if applied[i] != -1 {
+				evaluation.violations = append(evaluation.violations, results[applied[i]]...)
+			}
+			if contains[i] != -1 && len(results[contains[i]]) == 0 {
+				evaluation.contained++
+			}
+		}
+		if needValue || uniqueItems {
+			array = append(array, element)
+		}
+		index++
+		return iter.Error == nil
+	})
+	for _, evaluation := range evaluations.list {
+		schema := evaluation.schema
+		evaluation.checkType("array", false)
+		if index < schema.minItems {
+			evaluation.fail("minItems", "expected at least %d items, but found %d", schema.minItems, index)
+		}
+		if schema.maxItems >= 0 && index > schema.maxItems {
+			evaluation.fail("maxItems", "expected at most %d items, but found %d", schema.maxItems, index)
+		}
+		if schema.contains != nil {
+			if evaluation.contained < schema.minContains {
+				evaluation.fail("minContains", "expected at least %d items matching contains, but found %d",
+					schema.minContains, evaluation.contained)
+			}
+			if schema.maxContains >= 0 && evaluation.contained > schema.maxContains {
+				evaluation.fail("maxContains", "expected at most %d items matching contains, but found %d",
+					schema.maxContains, evaluation.contained)
+			}
+		}
+		if schema.uniqueItems {
+			if i, j, found := findDuplicate(array); found {
+				evaluation.fail("uniqueItems", "items %d and %d are equal", i, j)
+			}
+		}
+	}
+	if !needValue {
+		return nil
+	}
+	if array == nil {
+		array = []interface{}{}
+	}
+	return array
+}
+
+func (evaluations *evaluations) checkType(instanceType string, isInteger bool) {
+	for _, evaluation := range evaluations.list {
+		evaluation.checkType(instanceType, isInteger)
+	}
+}
+
+func (evaluation *evaluation) checkType(instanceType string, isInteger bool) {
+	types := evaluation.schema.types
+	if len(types) == 0 {
+		return
+	}
+	for _, typeName := range types {
+		if typeName == instanceType || (typeName == "integer" && isInteger) {
+			return
+		}
+	}
+	evaluation.fail("type", "expected %s, but found %s", strings.Join(types, " or "), instanceType)
+}
+
+func (evaluations *evaluations) checkString(str string) {
+	length := -1
+	for _, evaluation := range evaluations.list {
+		schema := evaluation.schema
+		evaluation.checkType("string", false)
+		if schema.minLength > 0 || schema.maxLength >= 0 {
+			if length == -1 {
+				length = utf8.RuneCountInString(str)
+			}
+			if length < schema.minLength {
+				evaluation.fail("minLength", "expected at least %d characters, but found %d", schema.minLength, length)
+			}
+			if schema.maxLength >= 0 && length > schema.maxLength {
+				evaluation.fail("maxLength", "expected at most %d characters, but found %d", schema.maxLength, length)
+			}
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(str) {
+			evaluation.fail("pattern", "does not match pattern %q", schema.pattern.String())
+		}
+	}
+}
+
+func (evaluations *evaluations) checkNumber(number *big.Rat) {
+	for _, evaluation := range evaluations.list {
+		schema := evaluation.schema
+		evaluation.checkType("number", number.IsInt())
+		if schema.multipleOf != nil && !new(big.Rat).Quo(number, schema.multipleOf).IsInt() {
+			evaluation.fail("multipleOf", "%s is not a multiple of %s", formatRat(number), formatRat(schema.multipleOf))
+		}
+		if schema.maximum != nil && number.Cmp(schema.maximum) > 0 {
+			evaluation.fail("maximum", "%s is greater than %s", formatRat(number), formatRat(schema.maximum))
+		}
+		if schema.exclusiveMaximum != nil && number.Cmp(schema.exclusiveMaximum) >= 0 {
+			evaluation.fail("exclusiveMaximum", "%s is not less than %s", formatRat(number), formatRat(schema.exclusiveMaximum))
+		}
+		if schema.minimum != nil && number.Cmp(schema.minimum) < 0 {
+			evaluation.fail("minimum", "%s is less than %s", formatRat(number), formatRat(schema.minimum))
+		}
+		if schema.exclusiveMinimum != nil && number.Cmp(schema.exclusiveMinimum) <= 0 {
+			evaluation.fail("exclusiveMinimum", "%s is not greater than %s", formatRat(number), formatRat(schema.exclusiveMinimum))
+		}
+	}
+}
+
+func (evaluations *evaluations) checkValue(value interface{}) {
+	for _, evaluation := range evaluations.list {
+		schema := evaluation.schema
+		if schema.hasConst && !equalValue(value, schema.constValue) {
+			evaluation.fail("const", "must be equal to the const value")
+		}
+		if schema.enum != nil {
+			found := false
+			for _, candidate := range schema.enum {
+				if equalValue(value, candidate) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				evaluation.fail("enum", "must be one of the enum values")
+			}
+		}
+	}
+}
+
+// finalOf adds to the violations of the schema the outcome of its in-place applicators.
+// A schema referring back to itself through them is considered valid on the second visit.
+func (evaluations *evaluations) finalOf(schema *Schema) []Violation {
+	evaluation := evaluations.index[schema]
+	switch evaluation.state {
+	case evaluationDone:
+		return evaluation.final
+	case evaluationRunning:
+		return nil
+	}
+	evaluation.state = evaluationRunning
+	violations := append([]Violation{}, evaluation.violations...)
+	if schema.ref != nil {
+		violations = append(violations, evaluations.finalOf(schema.ref)...)
+	}
+	for _, subschema := range schema.allOf {
+		violations = append(violations, evaluations.finalOf(subschema)...)
+	}
+	if schema.anyOf != nil && evaluations.countValid(schema.anyOf) == 0 {
+		violations = append(violations, evaluation.violation("anyOf", "must match at least one schema"))
+	}
+	if schema.oneOf != nil {
+		if valid := evaluations.countValid(schema.oneOf); valid != 1 {
+			violations = append(violations, evaluation.violation("oneOf",
+				fmt.Sprintf("must match exactly one schema, but matched %d", valid)))
+		}
+	}
+	if schema.not != nil && len(evaluations.finalOf(schema.not)) == 0 {
+		violations = append(violations, evaluation.violation("not", "must not match the schema"))
+	}
+	if schema.ifSchema != nil {
+		if len(evaluations.finalOf(schema.ifSchema)) == 0 {
+			if schema.thenSchema != nil {
+				violations = append(violations, evaluations.finalOf(schema.thenSchema)...)
+			}
+		} else if schema.elseSchema != nil {
+			violations = append(violations, evaluations.finalOf(schema.elseSchema)...)
+		}
+	}
+	for _, key := range sortedKeys(schema.dependentSchemas) {
+		if evaluations.keys[key] {
+			violations = append(violations, evaluations.finalOf(schema.dependentSchemas[key])...)
+		}
+	}
+	evaluation.final = violations
+	evaluation.state = evaluationDone
+	return violations
+}
+
+func (evaluations *evaluations) countValid(schemas []*Schema) int {
+	valid := 0
+	for _, schema := range schemas {
+		if len(evaluations.finalOf(schema)) == 0 {
+			valid++
+		}
+	}
+	return valid
+}
+
+func (evaluation *evaluation) violation(keyword string, message string) Violation {
+	return Violation{
+		InstanceLocation: evaluation.location,
+		KeywordLocation:  evaluation.schema.keywordLocation(keyword),
+		Message:          message,
+	}
+}
+
+// validateKey validates an object key against propertyNames, reporting at the member location.
+func (validator *validator) validateKey(schema *Schema, key string, location string) []Violation {
+	quoted, _ := json.Marshal(key)
+	pool := validator.iter.Pool()
+	iter := pool.BorrowIterator(quoted)
+	defer pool.ReturnIterator(iter)
+	keyValidator := *validator
+	keyValidator.iter = iter
+	results, _ := keyValidator.validate([]*Schema{schema}, location, false)
+	return results[0]
+}
+
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func parseNumber(number json.Number) (*big.Rat, bool) {
+	if !numberPattern.MatchString(string(number)) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(number))
+}
+
+func formatRat(rat *big.Rat) string {
+	if rat.IsInt() {
+		return rat.Num().String()
+	}
+	f, _ := rat.Float64()
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// equalValue compares two decoded JSON values, numbers being equal when their values are.
+func equalValue(a interface{}, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, isNumber := b.(json.Number)
+		if !isNumber {
+			return false
+		}
+		ratA, okA := new(big.Rat).SetString(string(a))
+		ratB, okB := new(big.Rat).SetString(string(b))
+		return okA && okB && ratA.Cmp(ratB) == 0
+	case []interface{}:
+		b, isArray := b.([]interface{})
+		if !isArray || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalValue(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, isObject := b.(map[string]interface{})
+		if !isObject || len(a) != len(b) {
+			return false
+		}
+		for key, valueA := range a {
+			valueB, found := b[key]
+			if !found || !equalValue(valueA, valueB) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func findDuplicate(array []interface{}) (int, int, bool) {
+	for i := range array {
+		for j := i + 1; j < len(array); j++ {
+			if equalValue(array[i], array[j]) {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string][]string:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]*Schema:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
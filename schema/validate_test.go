@@ -0,0 +1,235 @@
+package schema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+var orderSchema = MustCompile([]byte(`{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "items"],
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"status": {"enum": ["open", "closed"]},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"$ref": "#/$defs/item"}
+		}
+	},
+	"additionalProperties": false,
+	"$defs": {
+		"item": {
+			"type": "object",
+			"required": ["name", "price"],
+			"properties": {
+				"name": {"type": "string", "minLength": 1},
+				"price": {"type": "number", "exclusiveMinimum": 0, "multipleOf": 0.01}
+			}
+		}
+	}
+}`))
+
+func Test_validate_valid_document(t *testing.T) {
+	should := require.New(t)
+	should.NoError(orderSchema.Validate([]byte(
+		`{"id":1,"status":"open","items":[{"name":"pen","price":1.10},{"name":"ink","price":20}]}`)))
+	should.NoError(orderSchema.ValidateReader(bytes.NewBufferString(`{"id":2.0,"items":[{"name":"a","price":0.01}]}`)))
+}
+
+func Test_validate_reports_all_violations(t *testing.T) {
+	should := require.New(t)
+	err := orderSchema.Validate([]byte(
+		`{"id":0,"status":"lost","items":[{"name":"pen","price":1},{"name":"","price":1},{"name":"x"},{"name":"y","price":-3.001}],"x":1}`))
+	validationErr, isValidationErr := err.(*ValidationError)
+	should.True(isValidationErr, "%v", err)
+	should.Equal([]Violation{
+		{"/id", "#/properties/id/minimum", "0 is less than 1"},
+		{"/status", "#/properties/status/enum", "must be one of the enum values"},
+		{"/items/1/name", "#/$defs/item/properties/name/minLength", "expected at least 1 characters, but found 0"},
+		{"/items/2", "#/$defs/item/required", `missing required property "price"`},
+		{"/items/3/price", "#/$defs/item/properties/price/multipleOf", "-3.001 is not a multiple of 0.01"},
+		{"/items/3/price", "#/$defs/item/properties/price/exclusiveMinimum", "-3.001 is not greater than 0"},
+		{"/x", "#/additionalProperties", "no value is allowed"},
+	}, validationErr.Violations)
+}
+
+func Test_validate_syntax_error(t *testing.T) {
+	should := require.New(t)
+	for _, input := range []string{`{"id":1,`, `{"id":01}`, `{"id":1} 1`, `[1,]`, ``} {
+		err := orderSchema.Validate([]byte(input))
+		should.Error(err, input)
+		_, isValidationErr := err.(*ValidationError)
+		should.False(isValidationErr, input)
+	}
+}
+
+func Test_validate_applicators(t *testing.T) {
+	schema := MustCompile([]byte(`{
+		"properties": {
+			"any": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"one": {"oneOf": [{"minimum": 0}, {"multipleOf": 2}]},
+			"all": {"allOf": [{"minLength": 2}, {"maxLength": 3}]},
+			"not": {"not": {"type": "null"}},
+			"cond": {
+				"if": {"properties": {"kind": {"const": "circle"}}},
+				"then": {"required": ["radius"]},
+				"else": {"required": ["side"]}
+			},
+			"dep": {
+				"dependentRequired": {"a": ["b"]},
+				"dependentSchemas": {"c": {"properties": {"d": {"type": "boolean"}}}}
+			}
+		}
+	}`))
+	tests := []struct {
+		input      string
+		violations []string
+	}{
+		{`{"any":"a","one":-2,"all":"ab","not":1,"cond":{"kind":"circle","radius":1},"dep":{"a":1,"b":2,"c":3,"d":true}}`, nil},
+		{`{"any":1.5}`, []string{"#/properties/any/anyOf"}},
+		{`{"one":4}`, []string{"#/properties/one/oneOf"}},
+		{`{"one":-3}`, []string{"#/properties/one/oneOf"}},
+		{`{"all":"abcd"}`, []string{"#/properties/all/allOf/1/maxLength"}},
+		{`{"not":null}`, []string{"#/properties/not/not"}},
+		{`{"cond":{"kind":"circle"}}`, []string{"#/properties/cond/then/required"}},
+		{`{"cond":{"kind":"square"}}`, []string{"#/properties/cond/else/required"}},
+		{`{"dep":{"a":1,"c":1,"d":1}}`, []string{"#/properties/dep/dependentRequired/a",
+			"#/properties/dep/dependentSchemas/c/properties/d/type"}},
+		{`{"dep":{"d":1}}`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			should := require.New(t)
+			should.Equal(test.violations, keywordLocations(schema.Validate([]byte(test.input))))
+		})
+	}
+}
+
+func Test_validate_arrays(t *testing.T) {
+	schema := MustCompile([]byte(`{
+		"type": "array",
+		"prefixItems": [{"type": "string"}, {"type": "boolean"}],
+		"items": {"type": "number"},
+		"contains": {"const": 1},
+		"maxContains": 2,
+		"uniqueItems": true,
+		"maxItems": 5
+	}`))
+	tests := []struct {
+		input      string
+		violations []string
+	}{
+		{`["a",true,1,2]`, nil},
+		{`["a",true,2]`, []string{"#/minContains"}},
+		{`["a",1,1.0]`, []string{"#/prefixItems/1/type", "#/uniqueItems"}},
+		{`["a",true,1,"b"]`, []string{"#/items/type"}},
+		{`["a",true,1,{"a":1},{"a":1.0},[1]]`, []string{"#/items/type", "#/items/type",
+			"#/items/type", "#/maxItems", "#/uniqueItems"}},
+		{`{"a":1}`, []string{"#/type"}},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			should := require.New(t)
+			should.Equal(test.violations, keywordLocations(schema.Validate([]byte(test.input))))
+		})
+	}
+}
+
+func Test_validate_objects(t *testing.T) {
+	schema := MustCompile([]byte(`{
+		"patternProperties": {"^x-": {"type": "string"}},
+		"additionalProperties": {"type": "integer"},
+		"propertyNames": {"maxLength": 4},
+		"minProperties": 1,
+		"maxProperties": 3
+	}`))
+	tests := []struct {
+		input      string
+		violations []string
+	}{
+		{`{"x-a":"b","c":1}`, nil},
+		{`{}`, []string{"#/minProperties"}},
+		{`{"x-a":1,"long-name":2,"c":"d","e":4}`, []string{"#/patternProperties/^x-/type",
+			"#/propertyNames/maxLength", "#/additionalProperties/type", "#/maxProperties"}},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			should := require.New(t)
+			should.Equal(test.violations, keywordLocations(schema.Validate([]byte(test.input))))
+		})
+	}
+}
+
+func Test_validate_recursive_schema(t *testing.T) {
+	should := require.New(t)
+	schema := MustCompile([]byte(`{
+		"type": "object",
+		"properties": {
+			"value": {"type": "integer"},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		}
+	}`))
+	should.NoError(schema.Validate([]byte(`{"value":1,"children":[{"value":2,"children":[{"value":3}]}]}`)))
+	err := schema.Validate([]byte(`{"value":1,"children":[{"value":2,"children":[{"value":"3"}]}]}`))
+	should.Equal([]Violation{{"/children/0/children/0/value", "#/properties/value/type", "expected integer, but found string"}},
+		err.(*ValidationError).Violations)
+}
+
+func Test_unmarshal_validates_before_decoding(t *testing.T) {
+	should := require.New(t)
+	type item struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}
+	type order struct {
+		ID    int    `json:"id"`
+		Items []item `json:"items"`
+	}
+	var valid order
+	should.NoError(orderSchema.Unmarshal([]byte(`{"id":1,"items":[{"name":"pen","price":1.5}]}`), &valid))
+	should.Equal(order{ID: 1, Items: []item{{"pen", 1.5}}}, valid)
+	var invalid order
+	err := orderSchema.Unmarshal([]byte(`{"id":1,"items":[{"name":"pen","price":-1}]}`), &invalid)
+	should.IsType(&ValidationError{}, err)
+	should.Equal(order{}, invalid)
+}
+
+func Test_validator_reads_with_its_api(t *testing.T) {
+	should := require.New(t)
+	input := []byte(`{id: 1, items: [{name: 'pen', price: 1.5,},], /* note */}`)
+	should.Error(orderSchema.Validate(input))
+	validator := orderSchema.Validator(jsoniter.Config{AllowJSON5: true}.Froze())
+	should.NoError(validator.Validate(input))
+	should.NoError(validator.ValidateReader(bytes.NewReader(input)))
+	var decoded map[string]interface{}
+	should.NoError(validator.Unmarshal(input, &decoded))
+	should.Equal(float64(1), decoded["id"])
+	should.Error(validator.Unmarshal([]byte(`{id: 0, items: []}`), &decoded))
+
+	names := MustCompile([]byte(`{"propertyNames": {"maxLength": 2}}`))
+	strict := names.Validator(jsoniter.Config{Strict: true}.Froze())
+	should.NoError(strict.Validate([]byte(`{"ab":1}`)))
+	should.Error(strict.Validate([]byte(`{"ab":1,"ab":2}`)))
+	_, isViolation := strict.Validate([]byte(`{"abc":1}`)).(*ValidationError)
+	should.True(isViolation)
+}
+
+func keywordLocations(err error) []string {
+	if err == nil {
+		return nil
+	}
+	validationErr, isValidationErr := err.(*ValidationError)
+	if !isValidationErr {
+		return []string{err.Error()}
+	}
+	locations := []string{}
+	for _, violation := range validationErr.Violations {
+		locations = append(locations, violation.KeywordLocation)
+	}
+	return locations
+}
@@ -13,6 +13,11 @@ type Stream struct {
 	Error      error
 	indention  int
 	Attachment interface{} // open for customized encoder
+	// a checked stream follows the JSON grammar with the states of Token, see SetChecked
+	checked     bool
+	checkState  int
+	checkStack  []int
+	checkPaused int
 }
 
 // NewStream create new stream instance.
@@ -38,6 +43,7 @@ func (stream *Stream) Pool() StreamPool {
 func (stream *Stream) Reset(out io.Writer) {
 	stream.out = out
 	stream.buf = stream.buf[:0]
+	stream.resetCheck()
 }
 
 // Available returns how many bytes are unused in the buffer.
@@ -64,7 +70,11 @@ func (stream *Stream) SetBuffer(buf []byte) {
 // It returns the number of bytes written.
 // If nn < len(p), it also returns an error explaining
 // why the write is short.
+// On a checked stream, p is taken as a whole value.
 func (stream *Stream) Write(p []byte) (nn int, err error) {
+	if !stream.checkValue() {
+		return 0, stream.Error
+	}
 	stream.buf = append(stream.buf, p...)
 	if stream.out != nil {
 		nn, err = stream.out.Write(stream.buf)
@@ -96,9 +106,11 @@ func (stream *Stream) writeFiveBytes(c1 byte, c2 byte, c3 byte, c4 byte, c5 byte
 }
 
 // Flush writes any buffered data to the underlying io.Writer.
+// On a checked stream, it also reports the document left unfinished, see SetChecked.
 func (stream *Stream) Flush() error {
+	unfinished := stream.checkFinished()
 	if stream.out == nil {
-		return nil
+		return unfinished
 	}
 	if stream.Error != nil {
 		return stream.Error
@@ -111,26 +123,44 @@ func (stream *Stream) Flush() error {
 		return err
 	}
 	stream.buf = stream.buf[:0]
-	return nil
+	return unfinished
 }
 
-// WriteRaw write string out without quotes, just like []byte
+// WriteRaw write string out without quotes, just like []byte.
+// On a checked stream, s is taken as a whole value.
+// On a canonical stream, s is read as a JSON value to be written canonically.
 func (stream *Stream) WriteRaw(s string) {
+	if stream.cfg.canonical {
+		stream.writeCanonicalRaw([]byte(s))
+		return
+	}
+	if !stream.checkValue() {
+		return
+	}
 	stream.buf = append(stream.buf, s...)
 }
 
 // WriteNil write null to stream
 func (stream *Stream) WriteNil() {
+	if !stream.checkValue() {
+		return
+	}
 	stream.writeFourBytes('n', 'u', 'l', 'l')
 }
 
 // WriteTrue write true to stream
 func (stream *Stream) WriteTrue() {
+	if !stream.checkValue() {
+		return
+	}
 	stream.writeFourBytes('t', 'r', 'u', 'e')
 }
 
 // WriteFalse write false to stream
 func (stream *Stream) WriteFalse() {
+	if !stream.checkValue() {
+		return
+	}
 	stream.writeFiveBytes('f', 'a', 'l', 's', 'e')
 }
 
@@ -145,6 +175,9 @@ func (stream *Stream) WriteBool(val bool) {
 
 // WriteObjectStart write { with possible indention
 func (stream *Stream) WriteObjectStart() {
+	if !stream.checkStart(tokenObjectStart) {
+		return
+	}
 	stream.indention += stream.cfg.indentionStep
 	stream.writeByte('{')
 	stream.writeIndention(0)
@@ -152,7 +185,12 @@ func (stream *Stream) WriteObjectStart() {
 
 // WriteObjectField write "field": with possible indention
 func (stream *Stream) WriteObjectField(field string) {
+	if !stream.checkField() {
+		return
+	}
+	stream.pauseCheck()
 	stream.WriteString(field)
+	stream.resumeCheck()
 	if stream.indention > 0 {
 		stream.writeTwoBytes(':', ' ')
 	} else {
@@ -162,6 +200,9 @@ func (stream *Stream) WriteObjectField(field string) {
 
 // WriteObjectEnd write } with possible indention
 func (stream *Stream) WriteObjectEnd() {
+	if !stream.checkEnd('}') {
+		return
+	}
 	stream.writeIndention(stream.cfg.indentionStep)
 	stream.indention -= stream.cfg.indentionStep
 	stream.writeByte('}')
@@ -169,18 +210,31 @@ func (stream *Stream) WriteObjectEnd() {
 
 // WriteEmptyObject write {}
 func (stream *Stream) WriteEmptyObject() {
+	if !stream.checkValue() {
+		return
+	}
 	stream.writeByte('{')
 	stream.writeByte('}')
 }
 
 // WriteMore write , with possible indention
 func (stream *Stream) WriteMore() {
+	if !stream.checkMore() {
+		return
+	}
+	stream.writeMore()
+}
+
+func (stream *Stream) writeMore() {
 	stream.writeByte(',')
 	stream.writeIndention(0)
 }
 
 // WriteArrayStart write [ with possible indention
 func (stream *Stream) WriteArrayStart() {
+	if !stream.checkStart(tokenArrayStart) {
+		return
+	}
 	stream.indention += stream.cfg.indentionStep
 	stream.writeByte('[')
 	stream.writeIndention(0)
@@ -188,11 +242,17 @@ func (stream *Stream) WriteArrayStart() {
 
 // WriteEmptyArray write []
 func (stream *Stream) WriteEmptyArray() {
+	if !stream.checkValue() {
+		return
+	}
 	stream.writeTwoBytes('[', ']')
 }
 
 // WriteArrayEnd write ] with possible indention
 func (stream *Stream) WriteArrayEnd() {
+	if !stream.checkEnd(']') {
+		return
+	}
 	stream.writeIndention(stream.cfg.indentionStep)
 	stream.indention -= stream.cfg.indentionStep
 	stream.writeByte(']')
@@ -0,0 +1,250 @@
+package jsoniter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// The writers of Config.Canonical, which writes JSON as RFC 8785 (JCS) does. Each writer of the
+// Stream checks cfg.canonical before calling them, JSON is written as fast as before without it.
+
+// lessUTF16 tells if a sorts before b when both are compared as UTF-16 code units, as the keys
+// of a canonical object are. It differs from comparing the bytes for the characters above U+FFFF,
+// whose surrogates sort before U+E000 to U+FFFF.
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		r1, n1 := utf8.DecodeRuneInString(a)
+		r2, n2 := utf8.DecodeRuneInString(b)
+		if r1 != r2 {
+			if (r1 > 0xffff) == (r2 > 0xffff) {
+				return r1 < r2
+			}
+			if r1 > 0xffff {
+				return r2 >= 0xe000
+			}
+			return r1 < 0xd800
+		}
+		a, b = a[n1:], b[n2:]
+	}
+	return len(a) < len(b)
+}
+
+// maxSafeInteger is the greatest integer such as all the integers up to it are float64 values.
+// The integers above it, or below its opposite, are written as floats.
+const maxSafeInteger = 1 << 53
+
+type canonicalKeyValues encodedKeyValues
+
+func (sv canonicalKeyValues) Len() int           { return len(sv) }
+func (sv canonicalKeyValues) Swap(i, j int)      { sv[i], sv[j] = sv[j], sv[i] }
+func (sv canonicalKeyValues) Less(i, j int) bool { return lessUTF16(sv[i].key, sv[j].key) }
+
+// appendCanonicalFloat appends val as the Number.prototype.toString of ECMAScript does: the
+// shortest digits reading back as val, in plain notation from 1e-6 to 1e21, in exponent notation
+// such as 1e+21 or 1.5e-7 out of it. RFC 8785 reads every number as a float64, so a float32 is
+// written as the float64 it converts to, 0.1 as 0.10000000149011612, and so is an integer out of
+// the range of maxSafeInteger.
+func appendCanonicalFloat(buf []byte, val float64) []byte {
+	if val == 0 {
+		// -0 as well
+		return append(buf, '0')
+	}
+	if val < 0 {
+		buf = append(buf, '-')
+		val = -val
+	}
+	var scratch [32]byte
+	formatted := strconv.AppendFloat(scratch[:0], val, 'e', -1, 64)
+	// formatted is d[.ddd]e±xx, the digits are moved over the dot
+	e := len(formatted) - 1
+	for formatted[e] != 'e' {
+		e--
+	}
+	exp, _ := strconv.Atoi(string(formatted[e+1:]))
+	digits := formatted[:1]
+	if e > 1 {
+		digits = formatted[:1+copy(formatted[1:], formatted[2:e])]
+	}
+	k, n := len(digits), exp+1
+	switch {
+	case k <= n && n <= 21:
+		buf = append(buf, digits...)
+		for i := k; i < n; i++ {
+			buf = append(buf, '0')
+		}
+	case 0 < n && n <= 21:
+		buf = append(buf, digits[:n]...)
+		buf = append(buf, '.')
+		buf = append(buf, digits[n:]...)
+	case -6 < n && n <= 0:
+		buf = append(buf, '0', '.')
+		for i := n; i < 0; i++ {
+			buf = append(buf, '0')
+		}
+		buf = append(buf, digits...)
+	default:
+		buf = append(buf, digits[0])
+		if k > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, digits[1:]...)
+		}
+		buf = append(buf, 'e')
+		if n-1 > 0 {
+			buf = append(buf, '+')
+		}
+		buf = strconv.AppendInt(buf, int64(n-1), 10)
+	}
+	return buf
+}
+
+// writeCanonicalString writes s escaping only what JSON requires: the quote, the backslash and
+// the control characters, with the short escapes where there is one and \u00xx otherwise.
+func (stream *Stream) writeCanonicalString(s string) {
+	if !utf8.ValidString(s) {
+		// the offset only, so s does not escape to the heap for every config
+		stream.Error = fmt.Errorf("invalid UTF-8 in string at byte %d", invalidUTF8Offset(s))
+		return
+	}
+	stream.buf = append(stream.buf, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b >= ' ' && b != '"' && b != '\\' {
+			continue
+		}
+		stream.buf = append(stream.buf, s[start:i]...)
+		switch b {
+		case '\\', '"':
+			stream.writeTwoBytes('\\', b)
+		case '\b':
+			stream.writeTwoBytes('\\', 'b')
+		case '\f':
+			stream.writeTwoBytes('\\', 'f')
+		case '\n':
+			stream.writeTwoBytes('\\', 'n')
+		case '\r':
+			stream.writeTwoBytes('\\', 'r')
+		case '\t':
+			stream.writeTwoBytes('\\', 't')
+		default:
+			stream.buf = append(stream.buf, `\u00`...)
+			stream.writeTwoBytes(hex[b>>4], hex[b&0xF])
+		}
+		start = i + 1
+	}
+	stream.buf = append(stream.buf, s[start:]...)
+	stream.writeByte('"')
+}
+
+// invalidUTF8Offset returns the offset of the first byte of s which is not valid UTF-8.
+func invalidUTF8Offset(s string) int {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return len(s)
+}
+
+// writeRawValue writes raw, a JSON value, such as the output of a json.Marshaler or the buffer
+// of a lazy Any. A canonical stream reads it to write it canonically.
+func (stream *Stream) writeRawValue(raw []byte) {
+	if stream.cfg.canonical {
+		stream.writeCanonicalRaw(raw)
+		return
+	}
+	stream.Write(raw)
+}
+
+func (stream *Stream) writeCanonicalRaw(raw []byte) {
+	iter := stream.cfg.BorrowIterator(raw)
+	defer stream.cfg.ReturnIterator(iter)
+	stream.writeCanonicalValue(iter)
+	if iter.Error == nil || iter.Error == io.EOF {
+		if iter.nextToken() != 0 {
+			iter.ReportError("writeCanonicalRaw", "there are bytes left after the value")
+		}
+	}
+	if iter.Error != nil && iter.Error != io.EOF && stream.Error == nil {
+		stream.Error = iter.Error
+	}
+}
+
+// writeCanonicalValue reads the next value of iter and writes it canonically,
+// the numbers as float64, as RFC 8785 reads them.
+func (stream *Stream) writeCanonicalValue(iter *Iterator) {
+	switch iter.WhatIsNext() {
+	case StringValue:
+		stream.WriteString(iter.ReadString())
+	case NumberValue:
+		val := iter.ReadFloat64()
+		if math.IsInf(val, 0) {
+			iter.ReportError("writeCanonicalValue", "number out of the range of float64")
+			return
+		}
+		stream.WriteFloat64(val)
+	case BoolValue:
+		stream.WriteBool(iter.ReadBool())
+	case NilValue:
+		iter.ReadNil()
+		stream.WriteNil()
+	case ArrayValue:
+		stream.WriteArrayStart()
+		first := true
+		iter.ReadArrayCB(func(iter *Iterator) bool {
+			if !first {
+				stream.WriteMore()
+			}
+			first = false
+			stream.writeCanonicalValue(iter)
+			return iter.Error == nil
+		})
+		stream.WriteArrayEnd()
+	case ObjectValue:
+		stream.writeCanonicalObject(iter)
+	default:
+		iter.ReportError("writeCanonicalValue", "unexpected value")
+	}
+}
+
+// writeCanonicalObject writes the object read by iter with its keys sorted,
+// each field being written aside first as sortKeysMapEncoder does.
+func (stream *Stream) writeCanonicalObject(iter *Iterator) {
+	subStream := stream.cfg.BorrowStream(nil)
+	defer stream.cfg.ReturnStream(subStream)
+	keyValues := canonicalKeyValues{}
+	iter.ReadObjectCB(func(iter *Iterator, field string) bool {
+		subStreamIndex := subStream.Buffered()
+		subStream.WriteString(field)
+		subStream.writeByte(':')
+		subStream.writeCanonicalValue(iter)
+		keyValues = append(keyValues, encodedKV{
+			key:      field,
+			keyValue: subStream.Buffer()[subStreamIndex:],
+		})
+		return iter.Error == nil && subStream.Error == nil
+	})
+	if subStream.Error != nil && stream.Error == nil {
+		stream.Error = subStream.Error
+	}
+	sort.Sort(keyValues)
+	stream.WriteObjectStart()
+	for i, keyValue := range keyValues {
+		if i != 0 {
+			stream.WriteMore()
+		}
+		if !stream.checkField() || !stream.checkValue() {
+			break
+		}
+		stream.pauseCheck()
+		stream.Write(keyValue.keyValue)
+		stream.resumeCheck()
+	}
+	stream.WriteObjectEnd()
+}
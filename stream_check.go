@@ -0,0 +1,179 @@
+package jsoniter
+
+import (
+	"fmt"
+)
+
+// the state of a checked stream once its top value is written, the states of Token otherwise
+const checkTopEnd = -1
+
+// SetChecked turns on or off the checked mode of the stream, starting a new document.
+// A checked stream follows the arrays and objects written: it writes the commas between
+// their elements and fields by itself, WriteMore being still allowed where a comma is expected,
+// and rejects the writes breaking the JSON grammar, such as a value where an object field is
+// expected or the end of the wrong container. A rejected write is skipped and sets stream.Error.
+// Flush reports the document left unfinished, then a new document can be written.
+// Write and WriteRaw are taken as whole values.
+func (stream *Stream) SetChecked(checked bool) {
+	stream.checked = checked
+	stream.resetCheck()
+}
+
+func (stream *Stream) resetCheck() {
+	stream.checkState = tokenTopValue
+	stream.checkStack = stream.checkStack[:0]
+	stream.checkPaused = 0
+}
+
+// pauseCheck lets a value or object field accepted by the checks be written in several parts,
+// until resumeCheck.
+func (stream *Stream) pauseCheck() {
+	stream.checkPaused++
+}
+
+func (stream *Stream) resumeCheck() {
+	stream.checkPaused--
+}
+
+func (stream *Stream) isChecking() bool {
+	return stream.checked && stream.checkPaused == 0
+}
+
+// checkValue tells if a value can be written, writing the comma before it when needed.
+func (stream *Stream) checkValue() bool {
+	if !stream.isChecking() {
+		return true
+	}
+	if !stream.checkValueAllowed("value") {
+		return false
+	}
+	stream.checkValueEnd()
+	return true
+}
+
+func (stream *Stream) checkValueAllowed(what string) bool {
+	switch stream.checkState {
+	case tokenTopValue, tokenArrayStart, tokenArrayValue, tokenObjectValue:
+		return true
+	case tokenArrayComma:
+		stream.writeMore()
+		return true
+	}
+	return stream.checkError("unexpected " + what)
+}
+
+func (stream *Stream) checkValueEnd() {
+	switch stream.checkState {
+	case tokenTopValue:
+		stream.checkState = checkTopEnd
+	case tokenArrayStart, tokenArrayValue:
+		stream.checkState = tokenArrayComma
+	case tokenObjectValue:
+		stream.checkState = tokenObjectComma
+	}
+}
+
+// checkField tells if an object field can be written, writing the comma before it when needed.
+func (stream *Stream) checkField() bool {
+	if !stream.isChecking() {
+		return true
+	}
+	switch stream.checkState {
+	case tokenObjectStart, tokenObjectKey:
+	case tokenObjectComma:
+		stream.writeMore()
+	default:
+		return stream.checkError("unexpected object field")
+	}
+	stream.checkState = tokenObjectValue
+	return true
+}
+
+// checkStart tells if an array or object can be started, writing the comma before it when needed.
+func (stream *Stream) checkStart(state int) bool {
+	if !stream.isChecking() {
+		return true
+	}
+	what := "["
+	if state == tokenObjectStart {
+		what = "{"
+	}
+	if !stream.checkValueAllowed(what) {
+		return false
+	}
+	stream.checkStack = append(stream.checkStack, stream.checkState)
+	stream.checkState = state
+	return true
+}
+
+// checkEnd tells if the array or object can be ended by c.
+func (stream *Stream) checkEnd(c byte) bool {
+	if !stream.isChecking() {
+		return true
+	}
+	if (c == ']' && stream.checkState != tokenArrayStart && stream.checkState != tokenArrayComma) ||
+		(c == '}' && stream.checkState != tokenObjectStart && stream.checkState != tokenObjectComma) {
+		return stream.checkError("unexpected " + string([]byte{c}))
+	}
+	stream.checkState = stream.checkStack[len(stream.checkStack)-1]
+	stream.checkStack = stream.checkStack[:len(stream.checkStack)-1]
+	stream.checkValueEnd()
+	return true
+}
+
+// checkMore tells if a comma can be written.
+func (stream *Stream) checkMore() bool {
+	if !stream.isChecking() {
+		return true
+	}
+	switch stream.checkState {
+	case tokenArrayComma:
+		stream.checkState = tokenArrayValue
+	case tokenObjectComma:
+		stream.checkState = tokenObjectKey
+	default:
+		return stream.checkError("unexpected ,")
+	}
+	return true
+}
+
+// checkFinished reports the document left unfinished, or starts a new document.
+func (stream *Stream) checkFinished() error {
+	if !stream.checked {
+		return nil
+	}
+	switch stream.checkState {
+	case tokenTopValue:
+		return nil
+	case checkTopEnd:
+		stream.checkState = tokenTopValue
+		return nil
+	}
+	return fmt.Errorf("checked stream: unexpected end of document, expect %s", stream.checkExpected())
+}
+
+func (stream *Stream) checkError(unexpected string) bool {
+	if stream.Error == nil {
+		stream.Error = fmt.Errorf("checked stream: %s, expect %s", unexpected, stream.checkExpected())
+	}
+	return false
+}
+
+// checkExpected describes what can be written next.
+func (stream *Stream) checkExpected() string {
+	switch stream.checkState {
+	case tokenTopValue, tokenArrayValue, tokenObjectValue:
+		return "value"
+	case checkTopEnd:
+		return "end of document"
+	case tokenArrayStart:
+		return "value or ]"
+	case tokenArrayComma:
+		return ", or ]"
+	case tokenObjectStart:
+		return "object field or }"
+	case tokenObjectKey:
+		return "object field"
+	}
+	return ", or }"
+}
@@ -14,10 +14,21 @@ func init() {
 
 // WriteFloat32 write float32 to stream
 func (stream *Stream) WriteFloat32(val float32) {
+	if !stream.checkValue() {
+		return
+	}
+	stream.writeFloat32(val)
+}
+
+func (stream *Stream) writeFloat32(val float32) {
 	if math.IsInf(float64(val), 0) || math.IsNaN(float64(val)) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
 	}
+	if stream.cfg.canonical {
+		stream.buf = appendCanonicalFloat(stream.buf, float64(val))
+		return
+	}
 	abs := math.Abs(float64(val))
 	fmt := byte('f')
 	// Note: Must use float32 comparisons for underlying float32 value to get precise cutoffs right.
@@ -31,6 +42,9 @@ func (stream *Stream) WriteFloat32(val float32) {
 
 // WriteFloat32Lossy write float32 to stream with ONLY 6 digits precision although much much faster
 func (stream *Stream) WriteFloat32Lossy(val float32) {
+	if !stream.checkValue() {
+		return
+	}
 	if math.IsInf(float64(val), 0) || math.IsNaN(float64(val)) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
@@ -40,13 +54,13 @@ func (stream *Stream) WriteFloat32Lossy(val float32) {
 		val = -val
 	}
 	if val > 0x4ffffff {
-		stream.WriteFloat32(val)
+		stream.writeFloat32(val)
 		return
 	}
 	precision := 6
 	exp := uint64(1000000) // 6
 	lval := uint64(float64(val)*float64(exp) + 0.5)
-	stream.WriteUint64(lval / exp)
+	stream.writeUint64(lval / exp)
 	fval := lval % exp
 	if fval == 0 {
 		return
@@ -55,7 +69,7 @@ func (stream *Stream) WriteFloat32Lossy(val float32) {
 	for p := precision - 1; p > 0 && fval < pow10[p]; p-- {
 		stream.writeByte('0')
 	}
-	stream.WriteUint64(fval)
+	stream.writeUint64(fval)
 	for stream.buf[len(stream.buf)-1] == '0' {
 		stream.buf = stream.buf[:len(stream.buf)-1]
 	}
@@ -63,10 +77,21 @@ func (stream *Stream) WriteFloat32Lossy(val float32) {
 
 // WriteFloat64 write float64 to stream
 func (stream *Stream) WriteFloat64(val float64) {
+	if !stream.checkValue() {
+		return
+	}
+	stream.writeFloat64(val)
+}
+
+func (stream *Stream) writeFloat64(val float64) {
 	if math.IsInf(val, 0) || math.IsNaN(val) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
 	}
+	if stream.cfg.canonical {
+		stream.buf = appendCanonicalFloat(stream.buf, val)
+		return
+	}
 	abs := math.Abs(val)
 	fmt := byte('f')
 	// Note: Must use float32 comparisons for underlying float32 value to get precise cutoffs right.
@@ -80,6 +105,9 @@ func (stream *Stream) WriteFloat64(val float64) {
 
 // WriteFloat64Lossy write float64 to stream with ONLY 6 digits precision although much much faster
 func (stream *Stream) WriteFloat64Lossy(val float64) {
+	if !stream.checkValue() {
+		return
+	}
 	if math.IsInf(val, 0) || math.IsNaN(val) {
 		stream.Error = fmt.Errorf("unsupported value: %f", val)
 		return
@@ -89,13 +117,13 @@ func (stream *Stream) WriteFloat64Lossy(val float64) {
 		val = -val
 	}
 	if val > 0x4ffffff {
-		stream.WriteFloat64(val)
+		stream.writeFloat64(val)
 		return
 	}
 	precision := 6
 	exp := uint64(1000000) // 6
 	lval := uint64(val*float64(exp) + 0.5)
-	stream.WriteUint64(lval / exp)
+	stream.writeUint64(lval / exp)
 	fval := lval % exp
 	if fval == 0 {
 		return
@@ -104,7 +132,7 @@ func (stream *Stream) WriteFloat64Lossy(val float64) {
 	for p := precision - 1; p > 0 && fval < pow10[p]; p-- {
 		stream.writeByte('0')
 	}
-	stream.WriteUint64(fval)
+	stream.writeUint64(fval)
 	for stream.buf[len(stream.buf)-1] == '0' {
 		stream.buf = stream.buf[:len(stream.buf)-1]
 	}
@@ -31,11 +31,17 @@ func writeBuf(buf []byte, v uint32) []byte {
 
 // WriteUint8 write uint8 to stream
 func (stream *Stream) WriteUint8(val uint8) {
+	if !stream.checkValue() {
+		return
+	}
 	stream.buf = writeFirstBuf(stream.buf, digits[val])
 }
 
 // WriteInt8 write int8 to stream
 func (stream *Stream) WriteInt8(nval int8) {
+	if !stream.checkValue() {
+		return
+	}
 	var val uint8
 	if nval < 0 {
 		val = uint8(-nval)
@@ -48,6 +54,13 @@ func (stream *Stream) WriteInt8(nval int8) {
 
 // WriteUint16 write uint16 to stream
 func (stream *Stream) WriteUint16(val uint16) {
+	if !stream.checkValue() {
+		return
+	}
+	stream.writeUint16(val)
+}
+
+func (stream *Stream) writeUint16(val uint16) {
 	q1 := val / 1000
 	if q1 == 0 {
 		stream.buf = writeFirstBuf(stream.buf, digits[val])
@@ -61,6 +74,9 @@ func (stream *Stream) WriteUint16(val uint16) {
 
 // WriteInt16 write int16 to stream
 func (stream *Stream) WriteInt16(nval int16) {
+	if !stream.checkValue() {
+		return
+	}
 	var val uint16
 	if nval < 0 {
 		val = uint16(-nval)
@@ -68,11 +84,18 @@ func (stream *Stream) WriteInt16(nval int16) {
 	} else {
 		val = uint16(nval)
 	}
-	stream.WriteUint16(val)
+	stream.writeUint16(val)
 }
 
 // WriteUint32 write uint32 to stream
 func (stream *Stream) WriteUint32(val uint32) {
+	if !stream.checkValue() {
+		return
+	}
+	stream.writeUint32(val)
+}
+
+func (stream *Stream) writeUint32(val uint32) {
 	q1 := val / 1000
 	if q1 == 0 {
 		stream.buf = writeFirstBuf(stream.buf, digits[val])
@@ -100,6 +123,9 @@ func (stream *Stream) WriteUint32(val uint32) {
 
 // WriteInt32 write int32 to stream
 func (stream *Stream) WriteInt32(nval int32) {
+	if !stream.checkValue() {
+		return
+	}
 	var val uint32
 	if nval < 0 {
 		val = uint32(-nval)
@@ -107,11 +133,22 @@ func (stream *Stream) WriteInt32(nval int32) {
 	} else {
 		val = uint32(nval)
 	}
-	stream.WriteUint32(val)
+	stream.writeUint32(val)
 }
 
 // WriteUint64 write uint64 to stream
 func (stream *Stream) WriteUint64(val uint64) {
+	if !stream.checkValue() {
+		return
+	}
+	if stream.cfg.canonical && val > maxSafeInteger {
+		stream.buf = appendCanonicalFloat(stream.buf, float64(val))
+		return
+	}
+	stream.writeUint64(val)
+}
+
+func (stream *Stream) writeUint64(val uint64) {
 	q1 := val / 1000
 	if q1 == 0 {
 		stream.buf = writeFirstBuf(stream.buf, digits[val])
@@ -169,6 +206,13 @@ func (stream *Stream) WriteUint64(val uint64) {
 
 // WriteInt64 write int64 to stream
 func (stream *Stream) WriteInt64(nval int64) {
+	if !stream.checkValue() {
+		return
+	}
+	if stream.cfg.canonical && (nval > maxSafeInteger || nval < -maxSafeInteger) {
+		stream.buf = appendCanonicalFloat(stream.buf, float64(nval))
+		return
+	}
 	var val uint64
 	if nval < 0 {
 		val = uint64(-nval)
@@ -176,7 +220,7 @@ func (stream *Stream) WriteInt64(nval int64) {
 	} else {
 		val = uint64(nval)
 	}
-	stream.WriteUint64(val)
+	stream.writeUint64(val)
 }
 
 // WriteInt write int to stream
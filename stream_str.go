@@ -219,6 +219,9 @@ var hex = "0123456789abcdef"
 
 // WriteStringWithHTMLEscaped write string to stream with html special characters escaped
 func (stream *Stream) WriteStringWithHTMLEscaped(s string) {
+	if !stream.checkValue() {
+		return
+	}
 	valLen := len(s)
 	stream.buf = append(stream.buf, '"')
 	// write string, the fast path, without utf8 and escape support
@@ -248,7 +251,7 @@ func writeStringSlowPathWithHTMLEscaped(stream *Stream, i int, s string, valLen
 				continue
 			}
 			if start < i {
-				stream.WriteRaw(s[start:i])
+				stream.buf = append(stream.buf, s[start:i]...)
 			}
 			switch b {
 			case '\\', '"':
@@ -265,7 +268,7 @@ func writeStringSlowPathWithHTMLEscaped(stream *Stream, i int, s string, valLen
 				// because they can lead to security holes when
 				// user-controlled strings are rendered into JSON
 				// and served to some browsers.
-				stream.WriteRaw(`\u00`)
+				stream.buf = append(stream.buf, `\u00`...)
 				stream.writeTwoBytes(hex[b>>4], hex[b&0xF])
 			}
 			i++
@@ -275,9 +278,9 @@ func writeStringSlowPathWithHTMLEscaped(stream *Stream, i int, s string, valLen
 		c, size := utf8.DecodeRuneInString(s[i:])
 		if c == utf8.RuneError && size == 1 {
 			if start < i {
-				stream.WriteRaw(s[start:i])
+				stream.buf = append(stream.buf, s[start:i]...)
 			}
-			stream.WriteRaw(`\ufffd`)
+			stream.buf = append(stream.buf, `\ufffd`...)
 			i++
 			start = i
 			continue
@@ -291,9 +294,9 @@ func writeStringSlowPathWithHTMLEscaped(stream *Stream, i int, s string, valLen
 		// See http://timelessrepo.com/json-isnt-a-javascript-subset for discussion.
 		if c == '\u2028' || c == '\u2029' {
 			if start < i {
-				stream.WriteRaw(s[start:i])
+				stream.buf = append(stream.buf, s[start:i]...)
 			}
-			stream.WriteRaw(`\u202`)
+			stream.buf = append(stream.buf, `\u202`...)
 			stream.writeByte(hex[c&0xF])
 			i += size
 			start = i
@@ -302,13 +305,20 @@ func writeStringSlowPathWithHTMLEscaped(stream *Stream, i int, s string, valLen
 		i += size
 	}
 	if start < len(s) {
-		stream.WriteRaw(s[start:])
+		stream.buf = append(stream.buf, s[start:]...)
 	}
 	stream.writeByte('"')
 }
 
 // WriteString write string to stream without html escape
 func (stream *Stream) WriteString(s string) {
+	if !stream.checkValue() {
+		return
+	}
+	if stream.cfg.canonical {
+		stream.writeCanonicalString(s)
+		return
+	}
 	valLen := len(s)
 	stream.buf = append(stream.buf, '"')
 	// write string, the fast path, without utf8 and escape support
@@ -338,7 +348,7 @@ func writeStringSlowPath(stream *Stream, i int, s string, valLen int) {
 				continue
 			}
 			if start < i {
-				stream.WriteRaw(s[start:i])
+				stream.buf = append(stream.buf, s[start:i]...)
 			}
 			switch b {
 			case '\\', '"':
@@ -355,7 +365,7 @@ func writeStringSlowPath(stream *Stream, i int, s string, valLen int) {
 				// because they can lead to security holes when
 				// user-controlled strings are rendered into JSON
 				// and served to some browsers.
-				stream.WriteRaw(`\u00`)
+				stream.buf = append(stream.buf, `\u00`...)
 				stream.writeTwoBytes(hex[b>>4], hex[b&0xF])
 			}
 			i++
@@ -366,7 +376,7 @@ func writeStringSlowPath(stream *Stream, i int, s string, valLen int) {
 		continue
 	}
 	if start < len(s) {
-		stream.WriteRaw(s[start:])
+		stream.buf = append(stream.buf, s[start:]...)
 	}
 	stream.writeByte('"')
 }
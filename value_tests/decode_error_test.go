@@ -0,0 +1,216 @@
+//+build go1.13
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeErrorItem struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type decodeErrorOrder struct {
+	ID    int                        `json:"id"`
+	Items []decodeErrorItem          `json:"items"`
+	Tags  map[string][]int           `json:"tags"`
+	Extra map[string]decodeErrorItem `json:"extra"`
+}
+
+func Test_decode_error_locates_the_value(t *testing.T) {
+	tests := []struct {
+		input  string
+		path   string
+		value  string
+		typ    reflect.Type
+		line   int
+		column int
+	}{
+		{`{"items":[{"name":"a","price":1},{"name":"b","price":"2"}]}`, "/items/1/price", "string",
+			reflect.TypeOf(float64(0)), 1, 54},
+		{"{\n  \"id\": true\n}", "/id", "bool", reflect.TypeOf(0), 2, 9},
+		{`{"tags":{"a/b":[1,{}]}}`, "/tags/a~1b/1", "object", reflect.TypeOf(0), 1, 19},
+		{`{"extra":{"x":{"name":[]}}}`, "/extra/x/name", "array", reflect.TypeOf(""), 1, 23},
+		{`{"items":[{"name":"a","price":1x}]}`, "/items/0", "", reflect.TypeOf(decodeErrorItem{}), 1, 32},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			should := require.New(t)
+			var order decodeErrorOrder
+			err := jsoniter.Unmarshal([]byte(test.input), &order)
+			var decodeErr *jsoniter.DecodeError
+			should.True(errors.As(err, &decodeErr), "%v", err)
+			should.Equal(test.path, decodeErr.Path)
+			should.Equal(test.value, decodeErr.Value)
+			should.Equal(test.typ, decodeErr.Type)
+			should.Equal(test.line, decodeErr.Line)
+			should.Equal(test.column, decodeErr.Column)
+		})
+	}
+}
+
+func Test_decode_error_keeps_message(t *testing.T) {
+	should := require.New(t)
+	var order decodeErrorOrder
+	err := jsoniter.Unmarshal([]byte(`{"items":[{"price":"2"}]}`), &order)
+	should.Contains(err.Error(), "test.decodeErrorOrder.Items: []test.decodeErrorItem: test.decodeErrorItem.Price: ")
+	should.Contains(err.Error(), "error found in #10 byte of")
+}
+
+func Test_decode_error_is_std_unmarshal_type_error(t *testing.T) {
+	should := require.New(t)
+	var order decodeErrorOrder
+	err := jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(`{"items":[{"price":"2"}]}`), &order)
+	var typeErr *json.UnmarshalTypeError
+	should.True(errors.As(err, &typeErr))
+	should.Equal("string", typeErr.Value)
+	should.Equal(reflect.TypeOf(float64(0)), typeErr.Type)
+	should.Equal("decodeErrorItem", typeErr.Struct)
+	should.Equal("items.price", typeErr.Field)
+	var syntaxErr *json.SyntaxError
+	should.False(errors.As(err, &syntaxErr))
+}
+
+func Test_syntax_error_is_std_syntax_error(t *testing.T) {
+	should := require.New(t)
+	var order decodeErrorOrder
+	err := jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(`{"id":1,"items":[}`), &order)
+	var syntaxErr *json.SyntaxError
+	should.True(errors.As(err, &syntaxErr))
+	should.Equal(int64(18), syntaxErr.Offset)
+	var typeErr *json.UnmarshalTypeError
+	should.False(errors.As(err, &typeErr))
+	var iterErr *jsoniter.SyntaxError
+	should.True(errors.As(err, &iterErr))
+	should.Equal(1, iterErr.Line)
+	should.Equal(18, iterErr.Column)
+}
+
+func Test_std_errors_only_in_compatible_mode(t *testing.T) {
+	should := require.New(t)
+	var order decodeErrorOrder
+	err := jsoniter.Unmarshal([]byte(`{"items":[{"price":"2"}]}`), &order)
+	var typeErr *json.UnmarshalTypeError
+	should.False(errors.As(err, &typeErr))
+	err = jsoniter.Unmarshal([]byte(`{"id":1,"items":[}`), &order)
+	var syntaxErr *json.SyntaxError
+	should.False(errors.As(err, &syntaxErr))
+	var iterErr *jsoniter.SyntaxError
+	should.True(errors.As(err, &iterErr))
+}
+
+func Test_decode_error_from_reader(t *testing.T) {
+	should := require.New(t)
+	input := bytes.Repeat([]byte(" \n"), 1000)
+	input = append(input, `[1, 2, "x"]`...)
+	decoder := jsoniter.NewDecoder(bytes.NewReader(input))
+	var values []int
+	err := decoder.Decode(&values)
+	var decodeErr *jsoniter.DecodeError
+	should.True(errors.As(err, &decodeErr))
+	should.Equal("/2", decodeErr.Path)
+	should.Equal(int64(2008), decodeErr.Offset)
+	should.Equal(1001, decodeErr.Line)
+	should.Equal(8, decodeErr.Column)
+}
+
+func Test_collect_all_errors(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{CollectAllErrors: true}.Froze()
+	var order decodeErrorOrder
+	err := api.Unmarshal([]byte(
+		`{"id":"1","items":[{"name":"a","price":"x"},{"name":2,"price":3}],"tags":{"a":[1,"b",3]},"extra":{"x":{"name":"n"}}}`),
+		&order)
+	var errs jsoniter.DecodeErrors
+	should.True(errors.As(err, &errs), "%v", err)
+	paths := []string{}
+	for _, decodeErr := range errs {
+		paths = append(paths, decodeErr.Path)
+	}
+	should.Equal([]string{"/id", "/items/0/price", "/items/1/name", "/tags/a/1"}, paths)
+	should.Equal(decodeErrorOrder{
+		Items: []decodeErrorItem{{Name: "a"}, {Price: 3}},
+		Tags:  map[string][]int{"a": {1, 0, 3}},
+		Extra: map[string]decodeErrorItem{"x": {Name: "n"}},
+	}, order)
+	should.Contains(err.Error(), "4 errors: /id: test.decodeErrorOrder.ID: ")
+	// the errors of encoding/json are only found with StandardLibraryErrors
+	var typeErr *json.UnmarshalTypeError
+	should.False(errors.As(err, &typeErr))
+	sorted := jsoniter.Config{EscapeHTML: true, SortMapKeys: true, ValidateJsonRawMessage: true, CollectAllErrors: true}.Froze()
+	err = sorted.Unmarshal([]byte(`{"id":"1","items":[{"price":"x"}]}`), &order)
+	should.False(errors.As(err, &typeErr))
+	compatible := jsoniter.Config{StandardLibraryErrors: true, CollectAllErrors: true}.Froze()
+	err = compatible.Unmarshal([]byte(`{"id":"1","items":[{"price":"x"}]}`), &order)
+	should.True(errors.As(err, &typeErr))
+	should.Equal("id", typeErr.Field)
+}
+
+func Test_collect_all_errors_of_numbers_and_maps(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{CollectAllErrors: true}.Froze()
+	var counts map[string]int
+	err := api.Unmarshal([]byte(`{"j":2,"k":"x","l":1.5,"m":3,"n":1e2,"o":99999999999999999999}`), &counts)
+	var errs jsoniter.DecodeErrors
+	should.True(errors.As(err, &errs), "%v", err)
+	paths := []string{}
+	for _, decodeErr := range errs {
+		paths = append(paths, decodeErr.Path)
+		should.NotEqual("", decodeErr.Value)
+	}
+	should.Equal([]string{"/k", "/l", "/n", "/o"}, paths)
+	should.Equal(map[string]int{"j": 2, "m": 3}, counts)
+	var small []int8
+	err = api.Unmarshal([]byte(`[1,300,2.5,3]`), &small)
+	should.True(errors.As(err, &errs), "%v", err)
+	should.Len(errs, 2)
+	should.Equal("/1", errs[0].Path)
+	should.Equal("number", errs[0].Value)
+	should.Len(small, 4)
+	should.Equal(int8(3), small[3])
+	var id int
+	err = api.Unmarshal([]byte(`1.5.1`), &id)
+	var decodeErr *jsoniter.DecodeError
+	should.True(errors.As(err, &decodeErr), "%v", err)
+	should.Equal("", decodeErr.Value)
+}
+
+func Test_collect_all_errors_stops_on_malformed_input(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{CollectAllErrors: true}.Froze()
+	var order decodeErrorOrder
+	err := api.Unmarshal([]byte(`{"id":"1","items":[{"name":"a","price":1x}]}`), &order)
+	var errs jsoniter.DecodeErrors
+	should.True(errors.As(err, &errs), "%v", err)
+	should.Len(errs, 2)
+	should.Equal("/id", errs[0].Path)
+	should.Equal("/items/0", errs[1].Path)
+	should.Equal("", errs[1].Value)
+	should.NoError(api.Unmarshal([]byte(`{"id":1}`), &order))
+	var id int
+	err = api.Unmarshal([]byte(`"1"`), &id)
+	var decodeErr *jsoniter.DecodeError
+	should.True(errors.As(err, &decodeErr))
+	should.Equal("string", decodeErr.Value)
+}
+
+func Test_collect_all_errors_with_decoder(t *testing.T) {
+	should := require.New(t)
+	api := jsoniter.Config{CollectAllErrors: true}.Froze()
+	decoder := api.NewDecoder(bytes.NewBufferString(`{"id":"1","items":[{"price":true}]} {"id":2}`))
+	var order decodeErrorOrder
+	err := decoder.Decode(&order)
+	var errs jsoniter.DecodeErrors
+	should.True(errors.As(err, &errs), "%v", err)
+	should.Len(errs, 2)
+	should.NoError(decoder.Decode(&order))
+	should.Equal(2, order.ID)
+}